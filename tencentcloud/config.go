@@ -1,63 +1,317 @@
 package tencentcloud
 
 import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-ini/ini"
+	"github.com/hashicorp/terraform/helper/logging"
+	"github.com/mitchellh/go-homedir"
 	"github.com/zqfan/tencentcloud-sdk-go/client"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
 	cbs "github.com/zqfan/tencentcloud-sdk-go/services/cbs/unversioned"
 	ccs "github.com/zqfan/tencentcloud-sdk-go/services/ccs/unversioned"
+	clb "github.com/zqfan/tencentcloud-sdk-go/services/clb/v20180317"
 	cvm "github.com/zqfan/tencentcloud-sdk-go/services/cvm/v20170312"
 	lb "github.com/zqfan/tencentcloud-sdk-go/services/lb/unversioned"
+	sts "github.com/zqfan/tencentcloud-sdk-go/services/sts/v20180813"
+	tag "github.com/zqfan/tencentcloud-sdk-go/services/tag/v20180813"
 	vpc "github.com/zqfan/tencentcloud-sdk-go/services/vpc/unversioned"
+	vpcv3 "github.com/zqfan/tencentcloud-sdk-go/services/vpc/v20170312"
+)
+
+const (
+	defaultSharedCredentialsFile = "~/.tencentcloud/credentials"
+	defaultProfile               = "default"
+
+	defaultMaxRetries    = 5
+	defaultRetryMaxDelay = 30
 )
 
 type Config struct {
 	SecretId  string
 	SecretKey string
 	Region    string
+	// Endpoints overrides the endpoint of a service, it is keyed by the
+	// service name, e.g. "cvm", "vpc" or "eip", mainly used to talk to
+	// a private deployment or an offline emulator
+	Endpoints map[string]string
+	// DomainSuffix replaces the default root domain "api.qcloud.com"
+	DomainSuffix string
+	// SecurityToken is sent along with SecretId and SecretKey when they are
+	// a temporary credential
+	SecurityToken string
+	// AssumeRole exchanges the credential above for a temporary one
+	AssumeRole *AssumeRoleConfig
+	// MaxRetries is the number of retries of a request which failed for a
+	// transient reason, e.g. throttling, and RetryMaxDelay caps the delay
+	// between two of them
+	MaxRetries    int
+	RetryMaxDelay time.Duration
+	// RateLimits is the number of requests per second allowed to a module,
+	// e.g. "cvm" or "vpc", a module which is absent is not limited
+	RateLimits map[string]int
+	// DefaultTags are applied to every taggable resource, the tags of a
+	// resource override the ones with the same key
+	DefaultTags map[string]string
+}
+
+type AssumeRoleConfig struct {
+	RoleArn         string
+	SessionName     string
+	SessionDuration int
+	Policy          string
 }
 
 type TencentCloudClient struct {
+	region string
+	pool   *clientPool
+
 	commonConn *client.Client
 	cvmConn    *cvm.Client
 	cbsConn    *cbs.Client
 	ccsConn    *ccs.Client
 	lbConn     *lb.Client
 	vpcConn    *vpc.Client
+	// vpcV3Conn talks to the VPC API of version 2017-03-12, which manages
+	// security group policies and network interfaces
+	vpcV3Conn *vpcv3.Client
+	tagConn   *tag.Client
+	// clbConn talks to the CLB API of version 2018-03-17, which manages the
+	// security groups of load balancers
+	clbConn *clb.Client
+
+	// zones caches the zone names of the region by their numeric ids
+	zonesMu sync.Mutex
+	zones   map[string]string
+}
+
+// clientPool lazily creates and caches the clients of each region, they all
+// share the same config, credential and rate limiter.
+type clientPool struct {
+	mu         sync.Mutex
+	config     *Config
+	credential common.Credential
+	limiter    *rateLimiter
+	clients    map[string]*TencentCloudClient
 }
 
 func (c *Config) Client() (interface{}, error) {
-	var tcClient TencentCloudClient
-	tcClient.commonConn = client.NewClient(c.SecretId, c.SecretKey, c.Region)
-	tcClient.commonConn.Debug = true
+	credential, err := c.credential()
+	if err != nil {
+		return nil, err
+	}
+	pool := &clientPool{
+		config:     c,
+		credential: credential,
+		clients:    make(map[string]*TencentCloudClient),
+	}
+	if len(c.RateLimits) > 0 {
+		pool.limiter = newRateLimiter(c.RateLimits)
+	}
+	tcClient, err := pool.get(c.Region)
+	if err != nil {
+		return nil, err
+	}
+	return tcClient, nil
+}
 
-	cvmConn, err := cvm.NewClientWithSecretId(c.SecretId, c.SecretKey, c.Region)
+func (pool *clientPool) get(region string) (*TencentCloudClient, error) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if tcClient, ok := pool.clients[region]; ok {
+		return tcClient, nil
+	}
+	tcClient, err := pool.newClient(region)
+	if err != nil {
+		return nil, err
+	}
+	pool.clients[region] = tcClient
+	return tcClient, nil
+}
+
+func (pool *clientPool) newClient(region string) (*TencentCloudClient, error) {
+	c := pool.config
+	tcClient := &TencentCloudClient{
+		region: region,
+		pool:   pool,
+	}
+	tcClient.commonConn = client.NewClient(c.SecretId, c.SecretKey, region)
+	tcClient.commonConn.Debug = logging.IsDebugOrHigher()
+
+	cvmConn, err := cvm.NewClientWithSecretId(c.SecretId, c.SecretKey, region)
 	if err != nil {
 		return nil, err
 	}
 	tcClient.cvmConn = cvmConn
 
-	vpcConn, err := vpc.NewClientWithSecretId(c.SecretId, c.SecretKey, c.Region)
+	vpcConn, err := vpc.NewClientWithSecretId(c.SecretId, c.SecretKey, region)
 	if err != nil {
 		return nil, err
 	}
 	tcClient.vpcConn = vpcConn
 
-	cbsConn, err := cbs.NewClientWithSecretId(c.SecretId, c.SecretKey, c.Region)
+	vpcV3Conn, err := vpcv3.NewClientWithSecretId(c.SecretId, c.SecretKey, region)
+	if err != nil {
+		return nil, err
+	}
+	tcClient.vpcV3Conn = vpcV3Conn
+
+	cbsConn, err := cbs.NewClientWithSecretId(c.SecretId, c.SecretKey, region)
 	if err != nil {
 		return nil, err
 	}
 	tcClient.cbsConn = cbsConn
 
-	ccsConn, err := ccs.NewClientWithSecretId(c.SecretId, c.SecretKey, c.Region)
+	ccsConn, err := ccs.NewClientWithSecretId(c.SecretId, c.SecretKey, region)
 	if err != nil {
 		return nil, err
 	}
 	tcClient.ccsConn = ccsConn
 
-	lbConn, err := lb.NewClientWithSecretId(c.SecretId, c.SecretKey, c.Region)
+	lbConn, err := lb.NewClientWithSecretId(c.SecretId, c.SecretKey, region)
 	if err != nil {
 		return nil, err
 	}
 	tcClient.lbConn = lbConn
 
-	return &tcClient, nil
+	tagConn, err := tag.NewClientWithSecretId(c.SecretId, c.SecretKey, region)
+	if err != nil {
+		return nil, err
+	}
+	tcClient.tagConn = tagConn
+
+	clbConn, err := clb.NewClientWithSecretId(c.SecretId, c.SecretKey, region)
+	if err != nil {
+		return nil, err
+	}
+	tcClient.clbConn = clbConn
+
+	if c.DomainSuffix != "" {
+		tcClient.commonConn.WithDomainSuffix(c.DomainSuffix)
+	}
+	for service, endpoint := range c.Endpoints {
+		tcClient.commonConn.WithEndpoint(service, endpoint)
+	}
+	tcClient.commonConn.WithRetryPolicy(c.retryPolicy())
+	for _, conn := range tcClient.sdkConns() {
+		c.configureConn(conn)
+	}
+
+	if pool.limiter != nil {
+		tcClient.commonConn.WithLimiter(pool.limiter)
+		for _, conn := range tcClient.sdkConns() {
+			conn.WithLimiter(pool.limiter)
+		}
+	}
+
+	if pool.credential != nil {
+		tcClient.commonConn.WithCredential(pool.credential)
+		for _, conn := range tcClient.sdkConns() {
+			conn.WithCredential(pool.credential)
+		}
+	}
+
+	return tcClient, nil
+}
+
+// withRegion returns the client of the region, an empty region means the
+// region of the client itself.
+func (client *TencentCloudClient) withRegion(region string) (*TencentCloudClient, error) {
+	if region == "" || region == client.region {
+		return client, nil
+	}
+	return client.pool.get(region)
+}
+
+// sdkConns returns the shared client of every SDK connection, so options can
+// be applied to all of them at once.
+func (client *TencentCloudClient) sdkConns() []*common.Client {
+	return []*common.Client{
+		&client.cvmConn.Client,
+		&client.vpcConn.Client,
+		&client.vpcV3Conn.Client,
+		&client.cbsConn.Client,
+		&client.ccsConn.Client,
+		&client.lbConn.Client,
+		&client.tagConn.Client,
+		&client.clbConn.Client,
+	}
+}
+
+func (c *Config) retryPolicy() *common.RetryPolicy {
+	return common.NewRetryPolicy(c.MaxRetries, c.RetryMaxDelay)
+}
+
+func (c *Config) configureConn(conn *common.Client) {
+	// requests are only logged when TF_LOG is DEBUG or TRACE, redacted
+	conn.WithDebug(logging.IsDebugOrHigher())
+	conn.WithRetryPolicy(c.retryPolicy())
+	if c.DomainSuffix != "" {
+		conn.WithDomainSuffix(c.DomainSuffix)
+	}
+	for service, endpoint := range c.Endpoints {
+		conn.WithEndpoint(service, endpoint)
+	}
+}
+
+// credential returns nil when the secret id and key can be used as they are.
+func (c *Config) credential() (common.Credential, error) {
+	var credential common.Credential
+	if c.SecurityToken != "" {
+		credential = common.NewTokenCredential(c.SecretId, c.SecretKey, c.SecurityToken)
+	}
+	if c.AssumeRole == nil {
+		return credential, nil
+	}
+
+	stsConn, err := sts.NewClientWithSecretId(c.SecretId, c.SecretKey, c.Region)
+	if err != nil {
+		return nil, err
+	}
+	c.configureConn(&stsConn.Client)
+	if credential != nil {
+		stsConn.WithCredential(credential)
+	}
+	return newStsCredential(stsConn, *c.AssumeRole)
+}
+
+// loadSharedCredentials reads a profile from an INI style credentials file,
+// keys of the profile are named after the provider arguments, e.g.
+//
+//	[default]
+//	secret_id = AKIDxxxxxxxx
+//	secret_key = xxxxxxxx
+//	region = ap-guangzhou
+//
+// it is not an error if neither the path nor the profile is given and the
+// default file does not exist.
+func loadSharedCredentials(path, profile string) (map[string]string, error) {
+	explicit := path != "" || profile != ""
+	if path == "" {
+		path = defaultSharedCredentialsFile
+	}
+	if profile == "" {
+		profile = defaultProfile
+	}
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) && !explicit {
+		return nil, nil
+	}
+
+	file, err := ini.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("load shared credentials file %v error: %v", path, err)
+	}
+	section, err := file.GetSection(profile)
+	if err != nil {
+		return nil, fmt.Errorf("profile %v not found in shared credentials file %v", profile, path)
+	}
+	return section.KeysHash(), nil
 }
@@ -0,0 +1,172 @@
+package tencentcloud
+
+import (
+	"encoding/json"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceTencentCloudSecurityGroups() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTencentCloudSecurityGroupsRead,
+
+		Schema: map[string]*schema.Schema{
+			"security_group_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateStringLengthInRange(1, 60),
+			},
+
+			// Computed values
+			"security_groups": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"security_group_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"create_time": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"be_associate_count": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"ingress": dataSourceSecurityGroupPoliciesSchema(),
+						"egress":  dataSourceSecurityGroupPoliciesSchema(),
+						"instance_ids": &schema.Schema{
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// dataSourceSecurityGroupPoliciesSchema is the computed counterpart of
+// securityGroupPoliciesSchema.
+func dataSourceSecurityGroupPoliciesSchema() *schema.Schema {
+	template := &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"template_id": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"group_id": &schema.Schema{
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+	rule := map[string]*schema.Schema{
+		"address_template":  template,
+		"protocol_template": template,
+	}
+	for _, key := range []string{"cidr_ip", "ipv6_cidr_block", "source_sgid", "ip_protocol", "port_range", "policy", "description"} {
+		rule[key] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
+	}
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem:     &schema.Resource{Schema: rule},
+	}
+}
+
+func dataSourceTencentCloudSecurityGroupsRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*TencentCloudClient)
+	params := map[string]string{
+		"Action":    "DescribeSecurityGroupEx",
+		"projectId": strconv.Itoa(projectId),
+		"limit":     "100",
+	}
+	if v, ok := d.GetOk("security_group_id"); ok {
+		params["sgId"] = v.(string)
+	}
+	if v, ok := d.GetOk("name"); ok {
+		params["sgName"] = v.(string)
+	}
+
+	type securityGroup struct {
+		SgId             string `json:"sgId"`
+		SgName           string `json:"sgName"`
+		SgRemark         string `json:"sgRemark"`
+		BeAssociateCount int    `json:"beAssociateCount"`
+		CreateTime       string `json:"createTime"`
+	}
+	var sgs []securityGroup
+	for {
+		params["offset"] = strconv.Itoa(len(sgs))
+		log.Printf("[DEBUG] data_source_tc_security_groups read params:%v", params)
+		response, err := sendRequest(client.commonConn, "dfw", params)
+		if err != nil {
+			return err
+		}
+		var jsonresp struct {
+			Data struct {
+				TotalNum int             `json:"totalNum"`
+				Detail   []securityGroup `json:"detail"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(response), &jsonresp); err != nil {
+			return err
+		}
+		sgs = append(sgs, jsonresp.Data.Detail...)
+		if len(jsonresp.Data.Detail) == 0 || len(sgs) >= jsonresp.Data.TotalNum {
+			break
+		}
+	}
+
+	result := make([]map[string]interface{}, 0, len(sgs))
+	ids := make([]string, 0, len(sgs))
+	for _, sg := range sgs {
+		set, err := describeSecurityGroupPolicies(client.vpcV3Conn, sg.SgId)
+		if err != nil {
+			return err
+		}
+		instanceIds, err := getSecurityGroupAssociatedInstancesBySgId(client.commonConn, sg.SgId)
+		if err != nil {
+			return err
+		}
+		result = append(result, map[string]interface{}{
+			"security_group_id":  sg.SgId,
+			"name":               sg.SgName,
+			"description":        sg.SgRemark,
+			"create_time":        sg.CreateTime,
+			"be_associate_count": sg.BeAssociateCount,
+			"ingress":            flattenSecurityGroupPolicies(set.Ingress),
+			"egress":             flattenSecurityGroupPolicies(set.Egress),
+			"instance_ids":       instanceIds,
+		})
+		ids = append(ids, sg.SgId)
+	}
+
+	d.SetId(dataResourceIdsHash(ids))
+	return d.Set("security_groups", result)
+}
@@ -0,0 +1,70 @@
+package tencentcloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestUnitDataSourceTencentCloudSecurityGroups_basic(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: m.Providers(),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitDataSourceSecurityGroupsConfig),
+			},
+			{
+				Config: m.Config(testUnitDataSourceSecurityGroupsConfig + testUnitDataSourceSecurityGroupsConfigQuery),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tencentcloud_security_groups.web", "security_groups.#", "1"),
+					resource.TestCheckResourceAttrPair("data.tencentcloud_security_groups.web", "security_groups.0.security_group_id", "tencentcloud_security_group.web", "id"),
+					resource.TestCheckResourceAttr("data.tencentcloud_security_groups.web", "security_groups.0.description", "web servers"),
+					resource.TestCheckResourceAttr("data.tencentcloud_security_groups.web", "security_groups.0.ingress.#", "1"),
+					resource.TestCheckResourceAttr("data.tencentcloud_security_groups.web", "security_groups.0.ingress.0.port_range", "80,443"),
+					resource.TestCheckResourceAttr("data.tencentcloud_security_groups.web", "security_groups.0.egress.#", "0"),
+					resource.TestCheckResourceAttr("data.tencentcloud_security_groups.web", "security_groups.0.be_associate_count", "1"),
+					resource.TestCheckResourceAttrPair("data.tencentcloud_security_groups.web", "security_groups.0.instance_ids.0", "tencentcloud_instance.foo", "id"),
+					resource.TestCheckResourceAttr("data.tencentcloud_security_groups.all", "security_groups.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+const testUnitDataSourceSecurityGroupsConfig = `
+resource "tencentcloud_security_group" "web" {
+  name        = "ci-temp-test-web"
+  description = "web servers"
+
+  ingress {
+    cidr_ip     = "0.0.0.0/0"
+    ip_protocol = "tcp"
+    port_range  = "80,443"
+    policy      = "accept"
+  }
+}
+
+resource "tencentcloud_security_group" "db" {
+  name = "ci-temp-test-db"
+}
+
+resource "tencentcloud_instance" "foo" {
+  instance_name     = "tf_unit_test"
+  availability_zone = "ap-guangzhou-3"
+  image_id          = "img-mock"
+  security_groups   = ["${tencentcloud_security_group.web.id}"]
+}
+`
+
+const testUnitDataSourceSecurityGroupsConfigQuery = `
+data "tencentcloud_security_groups" "web" {
+  name = "${tencentcloud_security_group.web.name}"
+}
+
+data "tencentcloud_security_groups" "all" {
+  name = "ci-temp-test"
+}
+`
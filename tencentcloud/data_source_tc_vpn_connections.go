@@ -0,0 +1,181 @@
+package tencentcloud
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	vpc "github.com/zqfan/tencentcloud-sdk-go/services/vpc/unversioned"
+)
+
+func dataSourceTencentCloudVpnConnections() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTencentCloudVpnConnectionsRead,
+
+		Schema: map[string]*schema.Schema{
+			"vpc_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"vpn_gateway_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"customer_gateway_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"connection_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateStringLengthInRange(1, 60),
+			},
+
+			// Computed values
+			"connections": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"connection_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vpc_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vpn_gateway_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"customer_gateway_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"security_policy": &schema.Schema{
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"local_cidr_block": &schema.Schema{
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"remote_cidr_blocks": &schema.Schema{
+										Type:     schema.TypeSet,
+										Computed: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+										Set:      schema.HashString,
+									},
+								},
+							},
+						},
+						"ike_config": dataSourceVpnConnectionConfigSchema(
+							[]string{"proto_encry_algorithm", "proto_authen_algorithm", "exchange_mode", "local_identity", "remote_identity",
+								"local_address", "remote_address", "local_fqdn_name", "remote_fqdn_name", "dh_group_name"},
+							[]string{"sa_lifetime_seconds"}),
+						"ipsec_config": dataSourceVpnConnectionConfigSchema(
+							[]string{"encrypt_algorithm", "integrity_algorithm", "pfs_dh_group"},
+							[]string{"sa_lifetime_seconds", "sa_lifetime_traffic"}),
+						"state": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"net_status": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"create_time": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// dataSourceVpnConnectionConfigSchema is the computed counterpart of the
+// ike_config and ipsec_config blocks of tencentcloud_vpn_connection.
+func dataSourceVpnConnectionConfigSchema(stringKeys, intKeys []string) *schema.Schema {
+	config := make(map[string]*schema.Schema)
+	for _, key := range stringKeys {
+		config[key] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
+	}
+	for _, key := range intKeys {
+		config[key] = &schema.Schema{
+			Type:     schema.TypeInt,
+			Computed: true,
+		}
+	}
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem:     &schema.Resource{Schema: config},
+	}
+}
+
+func dataSourceTencentCloudVpnConnectionsRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*TencentCloudClient).vpcConn
+
+	descReq := vpc.NewDescribeVpnConnRequest()
+	if v, ok := d.GetOk("vpc_id"); ok {
+		descReq.VpcId = common.StringPtr(v.(string))
+	}
+	if v, ok := d.GetOk("vpn_gateway_id"); ok {
+		descReq.VpnGwId = common.StringPtr(v.(string))
+	}
+	if v, ok := d.GetOk("customer_gateway_id"); ok {
+		descReq.UserGwId = common.StringPtr(v.(string))
+	}
+	if v, ok := d.GetOk("connection_id"); ok {
+		descReq.VpnConnId = common.StringPtr(v.(string))
+	}
+	if v, ok := d.GetOk("name"); ok {
+		descReq.VpnConnName = common.StringPtr(v.(string))
+	}
+	connections, err := describeVpnConnections(conn, descReq)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+
+	// the pre-shared keys are left out on purpose, the attributes of a data
+	// source can not be marked as sensitive
+	result := make([]map[string]interface{}, 0, len(connections))
+	ids := make([]string, 0, len(connections))
+	for _, connection := range connections {
+		m := map[string]interface{}{
+			"connection_id":       *connection.VpnConnId,
+			"vpc_id":              *connection.VpcId,
+			"vpn_gateway_id":      *connection.VpnGwId,
+			"customer_gateway_id": *connection.UserGwId,
+			"name":                *connection.VpnConnName,
+			"security_policy":     flattenVpnSecurityPolicies(connection.SPDAcl),
+			"state":               vpnStates[*connection.VpnConnState],
+			"net_status":          common.StringValue(connection.NetStatus),
+			"create_time":         common.StringValue(connection.CreateTime),
+		}
+		if connection.IKEArg != nil {
+			m["ike_config"] = flattenVpnIkeConfig(connection.IKEArg)
+		}
+		if connection.IPSECArg != nil {
+			m["ipsec_config"] = flattenVpnIpsecConfig(connection.IPSECArg)
+		}
+		result = append(result, m)
+		ids = append(ids, *connection.VpnConnId)
+	}
+
+	d.SetId(dataResourceIdsHash(ids))
+	return d.Set("connections", result)
+}
@@ -0,0 +1,46 @@
+package tencentcloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestUnitDataSourceTencentCloudVpnConnections_basic(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+	m.AddVpc("vpc-mockvpn1", "10.0.0.0/16")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: m.Providers(),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitVpnConnectionConfigUpdate),
+			},
+			{
+				Config: m.Config(testUnitVpnConnectionConfigUpdate + testUnitDataSourceVpnConnectionsConfigQuery),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_connections.foo", "connections.#", "1"),
+					resource.TestCheckResourceAttrPair("data.tencentcloud_vpn_connections.foo", "connections.0.connection_id", "tencentcloud_vpn_connection.foo", "id"),
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_connections.foo", "connections.0.name", "ci-temp-test-vpnx-updated"),
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_connections.foo", "connections.0.security_policy.#", "2"),
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_connections.foo", "connections.0.ike_config.0.exchange_mode", "aggressive"),
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_connections.foo", "connections.0.ipsec_config.0.sa_lifetime_seconds", "7200"),
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_connections.foo", "connections.0.state", "available"),
+					resource.TestCheckNoResourceAttr("data.tencentcloud_vpn_connections.foo", "connections.0.pre_share_key"),
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_gateways.foo", "vpn_gateways.0.connection_count", "1"),
+				),
+			},
+		},
+	})
+}
+
+const testUnitDataSourceVpnConnectionsConfigQuery = `
+data "tencentcloud_vpn_connections" "foo" {
+  vpn_gateway_id = "${tencentcloud_vpn_gateway.foo.id}"
+}
+
+data "tencentcloud_vpn_gateways" "foo" {
+  vpn_gateway_id = "${tencentcloud_vpn_gateway.foo.id}"
+}
+`
@@ -0,0 +1,98 @@
+package tencentcloud
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	vpc "github.com/zqfan/tencentcloud-sdk-go/services/vpc/unversioned"
+)
+
+func dataSourceTencentCloudVpnCustomerGateways() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTencentCloudVpnCustomerGatewaysRead,
+
+		Schema: map[string]*schema.Schema{
+			"customer_gateway_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateStringLengthInRange(1, 60),
+			},
+			"public_ip_address": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateIp,
+			},
+
+			// Computed values
+			"customer_gateways": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"customer_gateway_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"public_ip_address": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"connection_count": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"create_time": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTencentCloudVpnCustomerGatewaysRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*TencentCloudClient).vpcConn
+
+	descReq := vpc.NewDescribeUserGwRequest()
+	if v, ok := d.GetOk("customer_gateway_id"); ok {
+		descReq.UserGwId = common.StringPtr(v.(string))
+	}
+	if v, ok := d.GetOk("name"); ok {
+		descReq.UserGwName = common.StringPtr(v.(string))
+	}
+	if v, ok := d.GetOk("public_ip_address"); ok {
+		descReq.UserGwAddr = common.StringPtr(v.(string))
+	}
+	gateways, err := describeCustomerGateways(conn, descReq)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+
+	result := make([]map[string]interface{}, 0, len(gateways))
+	ids := make([]string, 0, len(gateways))
+	for _, gateway := range gateways {
+		m := map[string]interface{}{
+			"customer_gateway_id": *gateway.UserGwId,
+			"name":                *gateway.UserGwName,
+			"public_ip_address":   *gateway.UserGwAddr,
+			"create_time":         common.StringValue(gateway.CreateTime),
+		}
+		if gateway.VpnConnNum != nil {
+			m["connection_count"] = *gateway.VpnConnNum
+		}
+		result = append(result, m)
+		ids = append(ids, *gateway.UserGwId)
+	}
+
+	d.SetId(dataResourceIdsHash(ids))
+	return d.Set("customer_gateways", result)
+}
@@ -0,0 +1,54 @@
+package tencentcloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestUnitDataSourceTencentCloudVpnCustomerGateways_basic(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: m.Providers(),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitDataSourceVpnCustomerGatewaysConfig),
+			},
+			{
+				Config: m.Config(testUnitDataSourceVpnCustomerGatewaysConfig + testUnitDataSourceVpnCustomerGatewaysConfigQuery),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_customer_gateways.foo", "customer_gateways.#", "1"),
+					resource.TestCheckResourceAttrPair("data.tencentcloud_vpn_customer_gateways.foo", "customer_gateways.0.customer_gateway_id", "tencentcloud_vpn_customer_gateway.foo", "id"),
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_customer_gateways.foo", "customer_gateways.0.name", "ci-temp-test-foo"),
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_customer_gateways.foo", "customer_gateways.0.connection_count", "0"),
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_customer_gateways.bar", "customer_gateways.#", "1"),
+					resource.TestCheckResourceAttrPair("data.tencentcloud_vpn_customer_gateways.bar", "customer_gateways.0.customer_gateway_id", "tencentcloud_vpn_customer_gateway.bar", "id"),
+				),
+			},
+		},
+	})
+}
+
+const testUnitDataSourceVpnCustomerGatewaysConfig = `
+resource "tencentcloud_vpn_customer_gateway" "foo" {
+  name              = "ci-temp-test-foo"
+  public_ip_address = "198.51.100.1"
+}
+
+resource "tencentcloud_vpn_customer_gateway" "bar" {
+  name              = "ci-temp-test-bar"
+  public_ip_address = "198.51.100.2"
+}
+`
+
+const testUnitDataSourceVpnCustomerGatewaysConfigQuery = `
+data "tencentcloud_vpn_customer_gateways" "foo" {
+  customer_gateway_id = "${tencentcloud_vpn_customer_gateway.foo.id}"
+}
+
+data "tencentcloud_vpn_customer_gateways" "bar" {
+  public_ip_address = "198.51.100.2"
+}
+`
@@ -0,0 +1,119 @@
+package tencentcloud
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	vpc "github.com/zqfan/tencentcloud-sdk-go/services/vpc/unversioned"
+)
+
+func dataSourceTencentCloudVpnGateways() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceTencentCloudVpnGatewaysRead,
+
+		Schema: map[string]*schema.Schema{
+			"vpc_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"vpn_gateway_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateStringLengthInRange(1, 60),
+			},
+
+			// Computed values
+			"vpn_gateways": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vpn_gateway_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vpc_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"bandwidth": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"public_ip_address": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"connection_count": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"create_time": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"expire_time": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTencentCloudVpnGatewaysRead(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*TencentCloudClient).vpcConn
+
+	descReq := vpc.NewDescribeVpnGwRequest()
+	if v, ok := d.GetOk("vpc_id"); ok {
+		descReq.VpcId = common.StringPtr(v.(string))
+	}
+	if v, ok := d.GetOk("vpn_gateway_id"); ok {
+		descReq.VpnGwId = common.StringPtr(v.(string))
+	}
+	if v, ok := d.GetOk("name"); ok {
+		descReq.VpnGwName = common.StringPtr(v.(string))
+	}
+	gateways, err := describeVpnGateways(conn, descReq)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+
+	result := make([]map[string]interface{}, 0, len(gateways))
+	ids := make([]string, 0, len(gateways))
+	for _, gateway := range gateways {
+		m := map[string]interface{}{
+			"vpn_gateway_id":    *gateway.VpnGwId,
+			"vpc_id":            *gateway.VpcId,
+			"name":              *gateway.VpnGwName,
+			"public_ip_address": common.StringValue(gateway.VpnGwAddress),
+			"state":             vpnStates[*gateway.State],
+			"create_time":       common.StringValue(gateway.CreateTime),
+			"expire_time":       common.StringValue(gateway.ExpireTime),
+		}
+		if gateway.Bandwidth != nil {
+			m["bandwidth"] = *gateway.Bandwidth
+		}
+		if gateway.VpnConnNum != nil {
+			m["connection_count"] = *gateway.VpnConnNum
+		}
+		result = append(result, m)
+		ids = append(ids, *gateway.VpnGwId)
+	}
+
+	d.SetId(dataResourceIdsHash(ids))
+	return d.Set("vpn_gateways", result)
+}
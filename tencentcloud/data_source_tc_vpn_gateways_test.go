@@ -0,0 +1,66 @@
+package tencentcloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestUnitDataSourceTencentCloudVpnGateways_basic(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+	m.AddVpc("vpc-mockvpn1", "10.0.0.0/16")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: m.Providers(),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitDataSourceVpnGatewaysConfig),
+			},
+			{
+				Config: m.Config(testUnitDataSourceVpnGatewaysConfig + testUnitDataSourceVpnGatewaysConfigQuery),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_gateways.foo", "vpn_gateways.#", "1"),
+					resource.TestCheckResourceAttrPair("data.tencentcloud_vpn_gateways.foo", "vpn_gateways.0.vpn_gateway_id", "tencentcloud_vpn_gateway.foo", "id"),
+					resource.TestCheckResourceAttrPair("data.tencentcloud_vpn_gateways.foo", "vpn_gateways.0.public_ip_address", "tencentcloud_vpn_gateway.foo", "public_ip_address"),
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_gateways.foo", "vpn_gateways.0.bandwidth", "5"),
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_gateways.foo", "vpn_gateways.0.state", "available"),
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_gateways.vpc", "vpn_gateways.#", "2"),
+					resource.TestCheckResourceAttr("data.tencentcloud_vpn_gateways.none", "vpn_gateways.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+const testUnitDataSourceVpnGatewaysConfig = `
+data "tencentcloud_vpc" "foo" {
+  id = "vpc-mockvpn1"
+}
+
+resource "tencentcloud_vpn_gateway" "foo" {
+  vpc_id = "${data.tencentcloud_vpc.foo.id}"
+  name   = "ci-temp-test-foo"
+  period = 1
+}
+
+resource "tencentcloud_vpn_gateway" "bar" {
+  vpc_id = "${data.tencentcloud_vpc.foo.id}"
+  name   = "ci-temp-test-bar"
+  period = 1
+}
+`
+
+const testUnitDataSourceVpnGatewaysConfigQuery = `
+data "tencentcloud_vpn_gateways" "foo" {
+  name = "${tencentcloud_vpn_gateway.foo.name}"
+}
+
+data "tencentcloud_vpn_gateways" "vpc" {
+  vpc_id = "${data.tencentcloud_vpc.foo.id}"
+}
+
+data "tencentcloud_vpn_gateways" "none" {
+  vpn_gateway_id = "vpngw-notexist"
+}
+`
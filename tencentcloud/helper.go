@@ -3,11 +3,14 @@ package tencentcloud
 import (
 	"bytes"
 	"fmt"
-
+	"net/http"
+	"reflect"
 	"strings"
 
 	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zqfan/tencentcloud-sdk-go/client"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
 	cvm "github.com/zqfan/tencentcloud-sdk-go/services/cvm/v20170312"
 )
 
@@ -75,11 +78,6 @@ func buildFiltersParamForSDK(filterList *schema.Set) (r []*cvm.Filter) {
 	return
 }
 
-func retryable(code string, msg string) bool {
-	msg = strings.ToLower(msg)
-	return code == "InternalError" && strings.Contains(msg, "retry")
-}
-
 // Takes the result of flatmap.Expand for an array of strings
 // and returns a []string
 func expandStringList(configured []interface{}) []string {
@@ -89,3 +87,82 @@ func expandStringList(configured []interface{}) []string {
 	}
 	return vs
 }
+
+// changesFillImported reports whether every change of d sets an attribute
+// which is empty in the state. It is the case for the arguments used only on
+// create after the resource is imported, since the API does not return them.
+func changesFillImported(d *schema.ResourceData, attrs map[string]*schema.Schema) bool {
+	for k := range attrs {
+		if !d.HasChange(k) {
+			continue
+		}
+		o, _ := d.GetChange(k)
+		if o != nil && !reflect.DeepEqual(o, reflect.Zero(reflect.TypeOf(o)).Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+// sendRequest sends params to the module with the legacy client, the error
+// carried by the response is returned as a *common.APIError, so it can be
+// examined with isNotFound and the like.
+func sendRequest(conn *client.Client, mod string, params map[string]string) (string, error) {
+	response, err := conn.SendRequest(mod, params)
+	if err != nil {
+		return "", err
+	}
+	return response, client.CheckResponse(mod, params["Action"], response)
+}
+
+// notFoundCodes are the v2 numeric codes of each module which mean the
+// resource does not exist, most v2 APIs tell it by the codeDesc instead. The
+// same number may mean something else in another module.
+var notFoundCodes = map[string]map[int]bool{
+	// security group does not exist
+	"dfw": {7001: true},
+	// route table does not exist
+	"vpc": {28004: true},
+	// cluster does not exist
+	"ccs": {CLUSTER_NOT_FOUND_CODE: true},
+}
+
+// isNotFound reports whether err means the resource does not exist, e.g. it
+// has been deleted out of band.
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*common.APIError)
+	if !ok {
+		return false
+	}
+	return strings.Contains(apiErr.Code, "NotFound") ||
+		strings.HasSuffix(apiErr.Code, "NotExist") ||
+		notFoundCodes[apiErr.Service][apiErr.CodeNumber]
+}
+
+// isThrottled reports whether err means the request exceeds the rate limit.
+func isThrottled(err error) bool {
+	apiErr, ok := err.(*common.APIError)
+	return ok && common.IsThrottlingCode(apiErr.Code)
+}
+
+// isUnauthorized reports whether err means the caller is not allowed to
+// request the action by CAM.
+func isUnauthorized(err error) bool {
+	apiErr, ok := err.(*common.APIError)
+	return ok && strings.Contains(apiErr.Code, "UnauthorizedOperation")
+}
+
+// isRetryable reports whether err is transient, the client retries such errors
+// already, so it is mostly useful when waiting for a resource.
+func isRetryable(err error) bool {
+	switch e := err.(type) {
+	case *common.APIError:
+		return common.IsRetryableCode(e.Code, e.Message) ||
+			e.HTTPStatus == http.StatusTooManyRequests || e.HTTPStatus >= http.StatusInternalServerError
+	case *client.NetworkError:
+		return true
+	case *client.HTTPStatusError:
+		return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+	}
+	return common.IsNetworkError(err)
+}
@@ -0,0 +1,2843 @@
+package tencentcloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// mockCloud is an offline emulator of the tencent cloud API, it serves both
+// the legacy v2 form-POST protocol used by client.Client.SendRequest and the
+// protocol used by common.Client.Send, a request carrying a Version param
+// gets a v3 style response, otherwise a v2 style response.
+//
+// The emulator is stateful, so resource.UnitTest cases can run a full
+// create, read, update and delete cycle without any credential:
+//
+//	m := newMockCloud()
+//	defer m.Close()
+//	resource.UnitTest(t, resource.TestCase{
+//		Providers: m.Providers(),
+//		Steps: []resource.TestStep{
+//			{Config: m.Config(testAccVpcConfig)},
+//		},
+//	})
+type mockCloud struct {
+	sync.Mutex
+
+	server *httptest.Server
+	seq    int
+
+	instances map[string]*mockInstance
+	vpcs      map[string]*mockVpc
+	subnets   map[string]*mockSubnet
+	eips      map[string]*mockEip
+	disks     map[string]*mockDisk
+	nats      map[string]*mockNat
+	peerings  map[string]*mockPeering
+	vpnGws    map[string]*mockVpnGw
+	userGws   map[string]*mockUserGw
+	vpnConns  map[string]*mockVpnConn
+	sgs       map[string]*mockSecurityGroup
+	bills     map[string]bool
+	tasks     map[int]bool
+
+	// enis and lbs are the security groups of the network interfaces and the
+	// load balancers by their ids, the tests create them directly
+	enis map[string][]string
+	lbs  map[string][]string
+
+	// tags are the tags of the resources by their six-segment names, e.g.
+	// qcs::cvm:ap-guangzhou:uin/:instance/ins-xxx
+	tags map[string]map[string]string
+
+	// clientTokens are the instance ids created by RunInstances with a
+	// ClientToken, a request with the same token creates nothing
+	clientTokens map[string][]string
+
+	// assumeRoles counts AssumeRole calls, lastToken is the Token param of
+	// the last request other than AssumeRole
+	assumeRoles int
+	lastToken   string
+
+	// throttles is the number of upcoming requests of an action to reject
+	// with RequestLimitExceeded, calls counts the requests of an action
+	throttles map[string]int
+	calls     map[string]int
+	// unavailables is the number of upcoming requests of an action which are
+	// done, but answered with 502 as if the response were lost
+	unavailables map[string]int
+	// denied are the actions the caller is not allowed to request
+	denied map[string]bool
+}
+
+type mockInstance struct {
+	id             string
+	name           string
+	zone           string
+	imageId        string
+	instanceType   string
+	chargeType     string
+	projectId      int
+	hostName       string
+	userData       string
+	renewFlag      string
+	prepaidPeriod  int
+	state          string
+	nextState      string
+	vpcId          string
+	subnetId       string
+	privateIp      string
+	publicIp       string
+	systemDiskType string
+	systemDiskId   string
+	systemDiskSize int
+	dataDisks      []map[string]interface{}
+	securityGroups []string
+	keyIds         []string
+
+	// stopChargingMode is the StoppedMode of the last StopInstances, an
+	// instance which is not stopped has none
+	stopChargingMode string
+}
+
+// mockSecurityGroup keeps its policies flattened as in the request params,
+// e.g. Protocol or AddressTemplate.AddressId, every change of the policies
+// bumps the version.
+type mockSecurityGroup struct {
+	id      string
+	name    string
+	remark  string
+	version int
+	ingress []map[string]string
+	egress  []map[string]string
+}
+
+type mockVpc struct {
+	id           string
+	region       string
+	name         string
+	cidrBlock    string
+	routeTableId string
+}
+
+type mockSubnet struct {
+	id        string
+	vpcId     string
+	name      string
+	cidrBlock string
+	zone      string
+}
+
+type mockEip struct {
+	id         string
+	ip         string
+	name       string
+	status     string
+	nextStatus string
+	instanceId string
+}
+
+type mockDisk struct {
+	id          string
+	storageType string
+	size        int
+	zone        string
+	name        string
+	instanceId  string
+}
+
+type mockNat struct {
+	id            string
+	vpcId         string
+	name          string
+	maxConcurrent int
+	bandwidth     int
+	eips          []string
+}
+
+// mockPeering is a vpc peering connection, a peer uin other than mockUin is
+// another account, which has to accept the connection.
+type mockPeering struct {
+	id         string
+	name       string
+	vpcId      string
+	peerVpcId  string
+	region     string
+	peerRegion string
+	peerUin    string
+	bandwidth  int
+	state      int
+}
+
+// mockVpnGw, mockUserGw and mockVpnConn count the vpn connections of the
+// gateways, which can not be deleted while they have any. A vpn gateway is
+// prepaid for period months and can not be deleted at all.
+type mockVpnGw struct {
+	id          string
+	vpcId       string
+	region      string
+	name        string
+	address     string
+	bandwidth   int
+	period      int
+	autoRenew   bool
+	connections int
+}
+
+type mockUserGw struct {
+	id          string
+	region      string
+	name        string
+	address     string
+	connections int
+}
+
+type mockVpnConn struct {
+	id           string
+	vpcId        string
+	region       string
+	vpnGwId      string
+	userGwId     string
+	name         string
+	preSharedKey string
+	spdAcl       map[string][]string
+	ike          map[string]interface{}
+	ipsec        map[string]interface{}
+}
+
+// mockUin is the account of the credentials of the emulator.
+const mockUin = "100000000001"
+
+// mockError is rendered as `{"code":4000,"codeDesc":...}` for the v2
+// protocol and as `{"Response":{"Error":{...}}}` for the v3 protocol.
+type mockError struct {
+	Code    string
+	Message string
+}
+
+type mockAction func(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError)
+
+var mockActions = map[string]mockAction{
+	// cvm
+	"RunInstances":              mockRunInstances,
+	"DescribeInstances":         mockDescribeInstances,
+	"DescribeInstancesStatus":   mockDescribeInstancesStatus,
+	"TerminateInstances":        mockTerminateInstances,
+	"ModifyInstancesAttribute":  mockModifyInstancesAttribute,
+	"StartInstances":            mockStartInstances,
+	"StopInstances":             mockStopInstances,
+	"ResetInstancesPassword":    mockResetInstancesPassword,
+	"ResetInstance":             mockResetInstance,
+	"ResetInstancesType":        mockResetInstancesType,
+	"ResizeInstanceDisks":       mockResizeInstanceDisks,
+	"ModifyInstancesChargeType": mockModifyInstancesChargeType,
+	"RenewInstances":            mockRenewInstances,
+	"ModifyInstancesRenewFlag":  mockModifyInstancesRenewFlag,
+	"ModifyInstancesProject":    mockModifyInstancesProject,
+	"DescribeZones":             mockDescribeZones,
+	// vpc
+	"CreateVpc":             mockCreateVpc,
+	"DescribeVpcEx":         mockDescribeVpcEx,
+	"ModifyVpcAttribute":    mockModifyVpcAttribute,
+	"DeleteVpc":             mockDeleteVpc,
+	"CreateSubnet":          mockCreateSubnet,
+	"DescribeSubnet":        mockDescribeSubnet,
+	"ModifySubnetAttribute": mockModifySubnetAttribute,
+	"DeleteSubnet":          mockDeleteSubnet,
+	"DescribeSubnetEx":      mockDescribeSubnetEx,
+	// eip
+	"AllocateAddresses":      mockAllocateAddresses,
+	"DescribeAddresses":      mockDescribeAddresses,
+	"ModifyAddressAttribute": mockModifyAddressAttribute,
+	"ReleaseAddresses":       mockReleaseAddresses,
+	"AssociateAddress":       mockAssociateAddress,
+	"DisassociateAddress":    mockDisassociateAddress,
+	// cbs
+	"CreateCbsStorages":          mockCreateCbsStorages,
+	"DescribeCbsStorages":        mockDescribeCbsStorages,
+	"ModifyCbsStorageAttributes": mockModifyCbsStorageAttributes,
+	"TerminateCbsStorages":       mockTerminateCbsStorages,
+	"AttachCbsStorages":          mockAttachCbsStorages,
+	"DetachCbsStorages":          mockDetachCbsStorages,
+	// nat
+	"CreateNatGateway":                mockCreateNatGateway,
+	"QueryNatGatewayProductionStatus": mockQueryNatGatewayProductionStatus,
+	"DescribeNatGateway":              mockDescribeNatGateway,
+	"ModifyNatGateway":                mockModifyNatGateway,
+	"UpgradeNatGateway":               mockUpgradeNatGateway,
+	"EipBindNatGateway":               mockEipBindNatGateway,
+	"EipUnBindNatGateway":             mockEipUnBindNatGateway,
+	"DeleteNatGateway":                mockDeleteNatGateway,
+	"DescribeVpcTaskResult":           mockDescribeVpcTaskResult,
+
+	"CreateVpcPeeringConnection":      mockCreateVpcPeeringConnection,
+	"CreateVpcPeeringConnectionEx":    mockCreateVpcPeeringConnectionEx,
+	"DescribeVpcPeeringConnections":   mockDescribeVpcPeeringConnections,
+	"DescribeVpcPeeringConnectionsEx": mockDescribeVpcPeeringConnectionsEx,
+	"AcceptVpcPeeringConnection":      mockAcceptVpcPeeringConnection,
+	"AcceptVpcPeeringConnectionEx":    mockAcceptVpcPeeringConnectionEx,
+	"ModifyVpcPeeringConnection":      mockModifyVpcPeeringConnection,
+	"ModifyVpcPeeringConnectionEx":    mockModifyVpcPeeringConnectionEx,
+	"DeleteVpcPeeringConnection":      mockDeleteVpcPeeringConnection,
+	"DeleteVpcPeeringConnectionEx":    mockDeleteVpcPeeringConnectionEx,
+
+	"CreateVpn":       mockCreateVpn,
+	"DescribeVpnGw":   mockDescribeVpnGw,
+	"ModifyVpnGw":     mockModifyVpnGw,
+	"AddUserGw":       mockAddUserGw,
+	"DescribeUserGw":  mockDescribeUserGw,
+	"ModifyUserGw":    mockModifyUserGw,
+	"DeleteUserGw":    mockDeleteUserGw,
+	"AddVpnConn":      mockAddVpnConn,
+	"DescribeVpnConn": mockDescribeVpnConn,
+	"ModifyVpnConn":   mockModifyVpnConn,
+	"DeleteVpnConn":   mockDeleteVpnConn,
+
+	// sts
+	"AssumeRole": mockAssumeRole,
+
+	// security group
+	"CreateSecurityGroup":              mockCreateSecurityGroup,
+	"DescribeSecurityGroupEx":          mockDescribeSecurityGroupEx,
+	"ModifySecurityGroupAttributes":    mockModifySecurityGroupAttributes,
+	"DeleteSecurityGroup":              mockDeleteSecurityGroup,
+	"DescribeInstancesOfSecurityGroup": mockDescribeInstancesOfSecurityGroup,
+	"DescribeSecurityGroupPolicies":    mockDescribeSecurityGroupPolicies,
+	"CreateSecurityGroupPolicies":      mockCreateSecurityGroupPolicies,
+	"DeleteSecurityGroupPolicies":      mockDeleteSecurityGroupPolicies,
+	"ModifySecurityGroupPolicies":      mockModifySecurityGroupPolicies,
+
+	"DescribeNetworkInterfaces":                  mockDescribeNetworkInterfaces,
+	"AssociateNetworkInterfaceSecurityGroups":    mockAssociateNetworkInterfaceSecurityGroups,
+	"DisassociateNetworkInterfaceSecurityGroups": mockDisassociateNetworkInterfaceSecurityGroups,
+	"DescribeLoadBalancers":                      mockDescribeLoadBalancers,
+	"SetSecurityGroupForLoadbalancers":           mockSetSecurityGroupForLoadbalancers,
+
+	// tag
+	"ModifyResourceTags":                mockModifyResourceTags,
+	"DescribeResourceTagsByResourceIds": mockDescribeResourceTagsByResourceIds,
+}
+
+func newMockCloud() *mockCloud {
+	m := &mockCloud{
+		instances:    make(map[string]*mockInstance),
+		vpcs:         make(map[string]*mockVpc),
+		subnets:      make(map[string]*mockSubnet),
+		eips:         make(map[string]*mockEip),
+		disks:        make(map[string]*mockDisk),
+		nats:         make(map[string]*mockNat),
+		peerings:     make(map[string]*mockPeering),
+		vpnGws:       make(map[string]*mockVpnGw),
+		userGws:      make(map[string]*mockUserGw),
+		vpnConns:     make(map[string]*mockVpnConn),
+		sgs:          make(map[string]*mockSecurityGroup),
+		bills:        make(map[string]bool),
+		tasks:        make(map[int]bool),
+		enis:         make(map[string][]string),
+		lbs:          make(map[string][]string),
+		tags:         make(map[string]map[string]string),
+		throttles:    make(map[string]int),
+		unavailables: make(map[string]int),
+
+		clientTokens: make(map[string][]string),
+		calls:        make(map[string]int),
+		denied:       make(map[string]bool),
+	}
+	m.server = httptest.NewServer(m)
+	return m
+}
+
+func (m *mockCloud) Close() {
+	m.server.Close()
+}
+
+// Providers returns providers for the test case, the tencentcloud provider
+// is pointed at the emulator by the endpoints block rendered in Config.
+func (m *mockCloud) Providers() map[string]terraform.ResourceProvider {
+	return map[string]terraform.ResourceProvider{
+		"tencentcloud": Provider(),
+	}
+}
+
+// Config prepends a provider block with fake credentials and all service
+// endpoints pointing at the emulator to the config.
+func (m *mockCloud) Config(config string) string {
+	return m.ConfigWithProvider("", config)
+}
+
+// ConfigWithProvider is like Config, but adds extra arguments to the
+// provider block.
+func (m *mockCloud) ConfigWithProvider(extra, config string) string {
+	var endpoints string
+	for _, service := range endpointServices {
+		endpoints += fmt.Sprintf("        %s = \"%s\"\n", service, m.server.URL)
+	}
+	return fmt.Sprintf(`
+provider "tencentcloud" {
+    secret_id = "mock-secret-id"
+    secret_key = "mock-secret-key"
+    region = "ap-guangzhou"
+    endpoints {
+%s    }
+%s
+}
+`, endpoints, extra) + config
+}
+
+func (m *mockCloud) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params := make(map[string]string)
+	for k := range r.Form {
+		params[k] = r.Form.Get(k)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	m.seq++
+	requestId := fmt.Sprintf("mock-request-%d", m.seq)
+	action := params["Action"]
+	if action != "AssumeRole" {
+		m.lastToken = params["Token"]
+	}
+	m.calls[action]++
+	var resp map[string]interface{}
+	var mErr *mockError
+	if m.throttles[action] > 0 {
+		m.throttles[action]--
+		mErr = &mockError{"RequestLimitExceeded", fmt.Sprintf("too many requests of action %v", action)}
+	} else if m.denied[action] {
+		mErr = &mockError{"AuthFailure.UnauthorizedOperation", fmt.Sprintf("not allowed to request action %v", action)}
+	} else if handler, ok := mockActions[action]; ok {
+		resp, mErr = handler(m, params)
+	} else {
+		mErr = &mockError{"InvalidAction", fmt.Sprintf("action %v is not supported by the emulator", action)}
+	}
+	if resp == nil {
+		resp = make(map[string]interface{})
+	}
+
+	var body interface{}
+	if params["Version"] != "" {
+		if mErr != nil {
+			resp = map[string]interface{}{
+				"Error": map[string]interface{}{
+					"Code":    mErr.Code,
+					"Message": mErr.Message,
+				},
+			}
+		}
+		resp["RequestId"] = requestId
+		body = map[string]interface{}{"Response": resp}
+	} else {
+		if mErr != nil {
+			resp = map[string]interface{}{
+				"code":     4000,
+				"codeDesc": mErr.Code,
+				"message":  mErr.Message,
+			}
+		} else {
+			resp["code"] = 0
+			resp["codeDesc"] = "Success"
+			resp["message"] = ""
+		}
+		body = resp
+	}
+
+	if m.unavailables[action] > 0 {
+		m.unavailables[action]--
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// Throttle rejects the next n requests of the action with RequestLimitExceeded.
+func (m *mockCloud) Throttle(action string, n int) {
+	m.Lock()
+	defer m.Unlock()
+	m.throttles[action] = n
+}
+
+// AddVpc adds a vpc in ap-guangzhou outside of Terraform, e.g. for the prepaid
+// resources which can not be deleted, since their vpc can not be deleted
+// either.
+func (m *mockCloud) AddVpc(id, cidrBlock string) {
+	m.Lock()
+	defer m.Unlock()
+	m.vpcs[id] = &mockVpc{
+		id:           id,
+		region:       "ap-guangzhou",
+		name:         id,
+		cidrBlock:    cidrBlock,
+		routeTableId: m.newId("rtb"),
+	}
+}
+
+// Unavailable answers the next n requests of the action with 502 after they are
+// done, like a gateway which loses the response of the service.
+func (m *mockCloud) Unavailable(action string, n int) {
+	m.Lock()
+	defer m.Unlock()
+	m.unavailables[action] = n
+}
+
+// Deny rejects the requests of the action with UnauthorizedOperation, like CAM
+// does for a user without the permission.
+func (m *mockCloud) Deny(action string) {
+	m.Lock()
+	defer m.Unlock()
+	m.denied[action] = true
+}
+
+// Calls returns the number of requests of the action, including throttled ones.
+func (m *mockCloud) Calls(action string) int {
+	m.Lock()
+	defer m.Unlock()
+	return m.calls[action]
+}
+
+func (m *mockCloud) newId(prefix string) string {
+	m.seq++
+	return fmt.Sprintf("%s-mock%04d", prefix, m.seq)
+}
+
+// Exists reports whether the emulator holds an object of the kind, e.g.
+// "instance", "vpc", "subnet", "eip", "disk", "nat", "peering", "vpngw",
+// "cgw" or "vpnx".
+func (m *mockCloud) Exists(kind, id string) bool {
+	m.Lock()
+	defer m.Unlock()
+
+	var ok bool
+	switch kind {
+	case "instance":
+		_, ok = m.instances[id]
+	case "vpc":
+		_, ok = m.vpcs[id]
+	case "subnet":
+		_, ok = m.subnets[id]
+	case "eip":
+		_, ok = m.eips[id]
+	case "disk":
+		_, ok = m.disks[id]
+	case "nat":
+		_, ok = m.nats[id]
+	case "peering":
+		_, ok = m.peerings[id]
+	case "vpngw":
+		_, ok = m.vpnGws[id]
+	case "cgw":
+		_, ok = m.userGws[id]
+	case "vpnx":
+		_, ok = m.vpnConns[id]
+	case "sg":
+		_, ok = m.sgs[id]
+	}
+	return ok
+}
+
+// Remove deletes an object of the kind out of band, as if it were deleted in
+// the console.
+func (m *mockCloud) Remove(kind, id string) {
+	m.Lock()
+	defer m.Unlock()
+
+	switch kind {
+	case "instance":
+		delete(m.instances, id)
+	case "vpc":
+		delete(m.vpcs, id)
+	case "subnet":
+		delete(m.subnets, id)
+	case "eip":
+		delete(m.eips, id)
+	case "disk":
+		delete(m.disks, id)
+	case "nat":
+		delete(m.nats, id)
+	case "peering":
+		delete(m.peerings, id)
+	case "vpngw":
+		delete(m.vpnGws, id)
+	case "cgw":
+		delete(m.userGws, id)
+	case "vpnx":
+		delete(m.vpnConns, id)
+	case "sg":
+		delete(m.sgs, id)
+	}
+}
+
+func testUnitCheckMockExists(m *mockCloud, kind, n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set for %s", n)
+		}
+		if !m.Exists(kind, rs.Primary.ID) {
+			return fmt.Errorf("%s %s not found in emulator", kind, rs.Primary.ID)
+		}
+		return nil
+	}
+}
+
+func testUnitCheckMockDestroy(m *mockCloud, kind, resourceType string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+			if m.Exists(kind, rs.Primary.ID) {
+				return fmt.Errorf("%s %s still exists", kind, rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+// testUnitCheckMockTags checks the tags of the resource in the emulator, which
+// include the default tags of the provider.
+func testUnitCheckMockTags(m *mockCloud, n, serviceType, resourcePrefix string, want map[string]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		name := tagResourceName(serviceType, resourcePrefix, rs.Primary.Attributes["region"], rs.Primary.ID)
+		if got := m.Tags(name); !reflect.DeepEqual(got, want) {
+			return fmt.Errorf("expect tags %v of %s, got %v", want, name, got)
+		}
+		return nil
+	}
+}
+
+// listParam collects flattened list params like `InstanceIds.0`,
+// `InstanceIds.1` in index order.
+func listParam(params map[string]string, name string) []string {
+	type item struct {
+		index int
+		value string
+	}
+	var items []item
+	for k, v := range params {
+		if !strings.HasPrefix(k, name+".") {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimPrefix(k, name+"."))
+		if err != nil {
+			continue
+		}
+		items = append(items, item{index, v})
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].index < items[j].index })
+	values := make([]string, 0, len(items))
+	for _, it := range items {
+		values = append(values, it.value)
+	}
+	return values
+}
+
+func intParam(params map[string]string, name string, defaultValue int) int {
+	if v, err := strconv.Atoi(params[name]); err == nil {
+		return v
+	}
+	return defaultValue
+}
+
+// zone
+
+// mockRegionZoneIds is the base of the numeric zone ids of a region, the n-th
+// zone of ap-guangzhou, i.e. ap-guangzhou-n, is 100000+n.
+var mockRegionZoneIds = map[string]int{
+	"ap-guangzhou": 100000,
+	"ap-shanghai":  200000,
+	"ap-beijing":   800000,
+}
+
+// mockZoneId returns the numeric id of a zone name, or 0 if it is unknown.
+func mockZoneId(zone string) int {
+	i := strings.LastIndex(zone, "-")
+	if i < 0 {
+		return 0
+	}
+	n, err := strconv.Atoi(zone[i+1:])
+	if err != nil {
+		return 0
+	}
+	if base, ok := mockRegionZoneIds[zone[:i]]; ok {
+		return base + n
+	}
+	return 0
+}
+
+func mockDescribeZones(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	zones := []map[string]interface{}{}
+	for n := 2; n <= 4; n++ {
+		zone := fmt.Sprintf("%v-%d", params["Region"], n)
+		zones = append(zones, map[string]interface{}{
+			"Zone":      zone,
+			"ZoneName":  zone,
+			"ZoneId":    strconv.Itoa(mockZoneId(zone)),
+			"ZoneState": "AVAILABLE",
+		})
+	}
+	return map[string]interface{}{
+		"TotalCount": len(zones),
+		"ZoneSet":    zones,
+	}, nil
+}
+
+// instance
+
+func (ins *mockInstance) toMap() map[string]interface{} {
+	var privateIps, publicIps []string
+	if ins.privateIp != "" {
+		privateIps = []string{ins.privateIp}
+	}
+	if ins.publicIp != "" {
+		publicIps = []string{ins.publicIp}
+	}
+	stopChargingMode := ins.stopChargingMode
+	if stopChargingMode == "" {
+		stopChargingMode = "NOT_APPLICABLE"
+	}
+	return map[string]interface{}{
+		"InstanceId":         ins.id,
+		"InstanceName":       ins.name,
+		"InstanceType":       ins.instanceType,
+		"InstanceChargeType": ins.chargeType,
+		"InstanceState":      ins.state,
+		"CPU":                1,
+		"Memory":             1,
+		"RestrictState":      "NORMAL",
+		"ImageId":            ins.imageId,
+		"RenewFlag":          ins.renewFlag,
+		"CreatedTime":        "2018-01-01T00:00:00Z",
+		"ExpiredTime":        "2018-01-01T00:00:00Z",
+		"StopChargingMode":   stopChargingMode,
+		"Placement": map[string]interface{}{
+			"Zone":      ins.zone,
+			"ProjectId": ins.projectId,
+			"HostIds":   []string{},
+		},
+		"SystemDisk": map[string]interface{}{
+			"DiskType": ins.systemDiskType,
+			"DiskId":   ins.systemDiskId,
+			"DiskSize": ins.systemDiskSize,
+		},
+		"DataDisks":          ins.dataDisks,
+		"PrivateIpAddresses": privateIps,
+		"PublicIpAddresses":  publicIps,
+		"InternetAccessible": map[string]interface{}{
+			"InternetChargeType":      "TRAFFIC_POSTPAID_BY_HOUR",
+			"InternetMaxBandwidthOut": 0,
+			"PublicIpAssigned":        ins.publicIp != "",
+		},
+		"VirtualPrivateCloud": map[string]interface{}{
+			"VpcId":              ins.vpcId,
+			"SubnetId":           ins.subnetId,
+			"AsVpcGateway":       false,
+			"PrivateIpAddresses": privateIps,
+		},
+		"SecurityGroupIds": ins.securityGroups,
+		"LoginSettings": map[string]interface{}{
+			"KeyIds": ins.keyIds,
+		},
+	}
+}
+
+// advance moves a transitional state, e.g. PENDING, to its next state after
+// it has been observed once.
+func (ins *mockInstance) advance() {
+	if ins.nextState != "" {
+		ins.state = ins.nextState
+		ins.nextState = ""
+	}
+}
+
+func (m *mockCloud) findInstances(params map[string]string) ([]*mockInstance, *mockError) {
+	var instances []*mockInstance
+	for _, id := range listParam(params, "InstanceIds") {
+		ins, ok := m.instances[id]
+		if !ok {
+			return nil, &mockError{"InvalidInstanceId.NotFound", fmt.Sprintf("instance %v not found", id)}
+		}
+		instances = append(instances, ins)
+	}
+	return instances, nil
+}
+
+func mockRunInstances(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	if params["Placement.Zone"] == "" || params["ImageId"] == "" {
+		return nil, &mockError{"MissingParameter", "Placement.Zone and ImageId are required"}
+	}
+	vpcId := params["VirtualPrivateCloud.VpcId"]
+	subnetId := params["VirtualPrivateCloud.SubnetId"]
+	if vpcId != "" {
+		if _, ok := m.vpcs[vpcId]; !ok {
+			return nil, &mockError{"InvalidParameterValue.VpcIdNotExist", fmt.Sprintf("vpc %v not found", vpcId)}
+		}
+		if _, ok := m.subnets[subnetId]; !ok {
+			return nil, &mockError{"InvalidParameterValue.SubnetIdNotExist", fmt.Sprintf("subnet %v not found", subnetId)}
+		}
+	}
+
+	token := params["ClientToken"]
+	if ids, ok := m.clientTokens[token]; ok && token != "" {
+		return map[string]interface{}{"InstanceIdSet": ids}, nil
+	}
+
+	count := intParam(params, "InstanceCount", 1)
+	var ids []string
+	for i := 0; i < count; i++ {
+		ins := &mockInstance{
+			id:             m.newId("ins"),
+			name:           params["InstanceName"],
+			zone:           params["Placement.Zone"],
+			imageId:        params["ImageId"],
+			instanceType:   params["InstanceType"],
+			chargeType:     params["InstanceChargeType"],
+			projectId:      intParam(params, "Placement.ProjectId", 0),
+			hostName:       params["HostName"],
+			userData:       params["UserData"],
+			renewFlag:      params["InstanceChargePrepaid.RenewFlag"],
+			prepaidPeriod:  intParam(params, "InstanceChargePrepaid.Period", 0),
+			state:          "PENDING",
+			nextState:      "RUNNING",
+			vpcId:          vpcId,
+			subnetId:       subnetId,
+			privateIp:      params["VirtualPrivateCloud.PrivateIpAddresses.0"],
+			systemDiskType: params["SystemDisk.DiskType"],
+			systemDiskId:   m.newId("disk"),
+			systemDiskSize: intParam(params, "SystemDisk.DiskSize", 50),
+			dataDisks:      []map[string]interface{}{},
+			securityGroups: listParam(params, "SecurityGroupIds"),
+			keyIds:         listParam(params, "LoginSettings.KeyIds"),
+		}
+		if ins.name == "" {
+			ins.name = "未命名"
+		}
+		if ins.privateIp == "" {
+			ins.privateIp = fmt.Sprintf("10.0.%d.%d", m.seq/250, m.seq%250+2)
+		}
+		if ins.instanceType == "" {
+			ins.instanceType = "S1.SMALL1"
+		}
+		if ins.chargeType == "" {
+			ins.chargeType = tencentCloudApiInstanceChargeTypePostPaidByHour
+		}
+		if ins.chargeType == tencentCloudApiInstanceChargeTypeSpotPaid && params["InstanceMarketOptions.MarketType"] != tencentCloudApiInstanceMarketTypeSpot {
+			return nil, &mockError{"InvalidParameterCombination", "InstanceMarketOptions is required for SPOTPAID instances"}
+		}
+		if ins.systemDiskType == "" {
+			ins.systemDiskType = tencentCloudApiDiskTypeCloudBasic
+		}
+		if params["InternetAccessible.PublicIpAssigned"] == "TRUE" {
+			ins.publicIp = fmt.Sprintf("100.64.%d.%d", m.seq/250, m.seq%250+2)
+		}
+		for j := 0; ; j++ {
+			prefix := fmt.Sprintf("DataDisks.%d.", j)
+			if _, ok := params[prefix+"DiskSize"]; !ok {
+				break
+			}
+			ins.dataDisks = append(ins.dataDisks, map[string]interface{}{
+				"DiskType":           params[prefix+"DiskType"],
+				"DiskId":             m.newId("disk"),
+				"DiskSize":           intParam(params, prefix+"DiskSize", 0),
+				"DeleteWithInstance": params[prefix+"DeleteWithInstance"] != "FALSE",
+				"SnapshotId":         params[prefix+"SnapshotId"],
+			})
+		}
+		m.instances[ins.id] = ins
+		ids = append(ids, ins.id)
+	}
+	if token != "" {
+		m.clientTokens[token] = ids
+	}
+	return map[string]interface{}{"InstanceIdSet": ids}, nil
+}
+
+func mockDescribeInstances(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	var instances []*mockInstance
+	ids := listParam(params, "InstanceIds")
+	if len(ids) == 0 {
+		for _, ins := range m.instances {
+			instances = append(instances, ins)
+		}
+	}
+	for _, id := range ids {
+		if ins, ok := m.instances[id]; ok {
+			instances = append(instances, ins)
+		}
+	}
+	set := make([]map[string]interface{}, 0, len(instances))
+	for _, ins := range instances {
+		set = append(set, ins.toMap())
+	}
+	return map[string]interface{}{
+		"TotalCount":  len(set),
+		"InstanceSet": set,
+	}, nil
+}
+
+func mockDescribeInstancesStatus(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	set := []map[string]interface{}{}
+	for _, id := range listParam(params, "InstanceIds") {
+		ins, ok := m.instances[id]
+		if !ok {
+			continue
+		}
+		set = append(set, map[string]interface{}{
+			"InstanceId":    ins.id,
+			"InstanceState": ins.state,
+		})
+		ins.advance()
+	}
+	return map[string]interface{}{
+		"TotalCount":        len(set),
+		"InstanceStatusSet": set,
+	}, nil
+}
+
+func mockTerminateInstances(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	instances, mErr := m.findInstances(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	for _, ins := range instances {
+		for _, disk := range m.disks {
+			if disk.instanceId == ins.id {
+				disk.instanceId = ""
+			}
+		}
+		for _, eip := range m.eips {
+			if eip.instanceId == ins.id {
+				eip.instanceId = ""
+				eip.status = tencentCloudApiEipStatusUnbind
+			}
+		}
+		// prepaid instances are isolated in the recycle bin
+		if ins.chargeType == tencentCloudApiInstanceChargeTypePrePaid && ins.state != "SHUTDOWN" {
+			ins.state, ins.nextState = "SHUTTING_DOWN", "SHUTDOWN"
+			continue
+		}
+		delete(m.instances, ins.id)
+	}
+	return nil, nil
+}
+
+func mockModifyInstancesAttribute(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	instances, mErr := m.findInstances(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	sgIds := listParam(params, "SecurityGroups")
+	for _, ins := range instances {
+		if name, ok := params["InstanceName"]; ok {
+			ins.name = name
+		}
+		if len(sgIds) > 0 {
+			ins.securityGroups = sgIds
+		}
+	}
+	return nil, nil
+}
+
+func mockStartInstances(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	instances, mErr := m.findInstances(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	for _, ins := range instances {
+		if ins.state != "STOPPED" {
+			return nil, &mockError{"UnsupportedOperation", fmt.Sprintf("instance `%s` which is in the state of `%s` can not be started", ins.id, ins.state)}
+		}
+		ins.state, ins.nextState = "STARTING", "RUNNING"
+		ins.stopChargingMode = ""
+	}
+	return nil, nil
+}
+
+func mockStopInstances(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	instances, mErr := m.findInstances(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	for _, ins := range instances {
+		if ins.state != "RUNNING" {
+			return nil, &mockError{"UnsupportedOperation", fmt.Sprintf("instance `%s` which is in the state of `%s` can not be stopped", ins.id, ins.state)}
+		}
+		stoppedMode := params["StoppedMode"]
+		if stoppedMode == "" {
+			stoppedMode = tencentCloudApiStoppedModeKeepCharging
+		}
+		if stoppedMode == tencentCloudApiStoppedModeStopCharging && ins.chargeType != tencentCloudApiInstanceChargeTypePostPaidByHour {
+			return nil, &mockError{"UnsupportedOperation.StoppedModeStopCharging", fmt.Sprintf("instance `%s` of charge type `%s` can not stop charging", ins.id, ins.chargeType)}
+		}
+		ins.state, ins.nextState = "STOPPING", "STOPPED"
+		ins.stopChargingMode = stoppedMode
+	}
+	return nil, nil
+}
+
+func mockResetInstancesPassword(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	instances, mErr := m.findInstances(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	for _, ins := range instances {
+		if ins.state != "STOPPED" {
+			return nil, &mockError{"UnsupportedOperation", fmt.Sprintf("instance `%s` should be stopped", ins.id)}
+		}
+	}
+	return nil, nil
+}
+
+func mockResetInstancesType(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	instances, mErr := m.findInstances(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	for _, ins := range instances {
+		if ins.state != "STOPPED" {
+			return nil, &mockError{"UnsupportedOperation", fmt.Sprintf("instance `%s` should be stopped", ins.id)}
+		}
+		ins.instanceType = params["InstanceType"]
+	}
+	return nil, nil
+}
+
+func mockResizeInstanceDisks(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	ins, ok := m.instances[params["InstanceId"]]
+	if !ok {
+		return nil, &mockError{"InvalidInstanceId.NotFound", fmt.Sprintf("instance %v not found", params["InstanceId"])}
+	}
+	if ins.state != "STOPPED" {
+		return nil, &mockError{"UnsupportedOperation", fmt.Sprintf("instance `%s` should be stopped", ins.id)}
+	}
+	if size := intParam(params, "SystemDisk.DiskSize", 0); size > 0 {
+		if size < ins.systemDiskSize {
+			return nil, &mockError{"InvalidParameterValue", "disk size can not be shrunk"}
+		}
+		ins.systemDiskSize = size
+	}
+	for j := 0; ; j++ {
+		prefix := fmt.Sprintf("DataDisks.%d.", j)
+		diskId, ok := params[prefix+"DiskId"]
+		if !ok {
+			break
+		}
+		found := false
+		for _, disk := range ins.dataDisks {
+			if disk["DiskId"] == diskId {
+				size := intParam(params, prefix+"DiskSize", 0)
+				if size < disk["DiskSize"].(int) {
+					return nil, &mockError{"InvalidParameterValue", "disk size can not be shrunk"}
+				}
+				disk["DiskSize"] = size
+				found = true
+			}
+		}
+		if !found {
+			return nil, &mockError{"InvalidDisk.NotFound", fmt.Sprintf("disk %v not found", diskId)}
+		}
+	}
+	return nil, nil
+}
+
+func mockModifyInstancesChargeType(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	instances, mErr := m.findInstances(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	if params["InstanceChargeType"] != tencentCloudApiInstanceChargeTypePrePaid {
+		return nil, &mockError{"InvalidParameterValue", "only PREPAID is supported"}
+	}
+	for _, ins := range instances {
+		if ins.chargeType != tencentCloudApiInstanceChargeTypePostPaidByHour {
+			return nil, &mockError{"UnsupportedOperation", fmt.Sprintf("instance `%s` is not postpaid", ins.id)}
+		}
+		ins.chargeType = tencentCloudApiInstanceChargeTypePrePaid
+		ins.prepaidPeriod = intParam(params, "InstanceChargePrepaid.Period", 0)
+		ins.renewFlag = params["InstanceChargePrepaid.RenewFlag"]
+		if ins.renewFlag == "" {
+			ins.renewFlag = tencentCloudApiInstanceChargeTypePrePaidRenewFlagNotifyAndManualRenew
+		}
+	}
+	return nil, nil
+}
+
+func mockRenewInstances(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	instances, mErr := m.findInstances(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	for _, ins := range instances {
+		if ins.chargeType != tencentCloudApiInstanceChargeTypePrePaid {
+			return nil, &mockError{"UnsupportedOperation", fmt.Sprintf("instance `%s` is not prepaid", ins.id)}
+		}
+		ins.prepaidPeriod += intParam(params, "InstanceChargePrepaid.Period", 0)
+	}
+	return nil, nil
+}
+
+func mockModifyInstancesRenewFlag(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	instances, mErr := m.findInstances(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	for _, ins := range instances {
+		ins.renewFlag = params["RenewFlag"]
+	}
+	return nil, nil
+}
+
+func mockModifyInstancesProject(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	instances, mErr := m.findInstances(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	for _, ins := range instances {
+		ins.projectId = intParam(params, "ProjectId", 0)
+	}
+	return nil, nil
+}
+
+func mockResetInstance(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	ins, ok := m.instances[params["InstanceId"]]
+	if !ok {
+		return nil, &mockError{"InvalidInstanceId.NotFound", fmt.Sprintf("instance %v not found", params["InstanceId"])}
+	}
+	ins.imageId = params["ImageId"]
+	if size := intParam(params, "SystemDisk.DiskSize", 0); size > 0 {
+		ins.systemDiskSize = size
+	}
+	ins.state, ins.nextState = "PENDING", "RUNNING"
+	return nil, nil
+}
+
+// vpc and subnet
+
+func (vpc *mockVpc) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"vpcId":       vpc.id,
+		"unVpcId":     vpc.id,
+		"vpcName":     vpc.name,
+		"cidrBlock":   vpc.cidrBlock,
+		"isDefault":   false,
+		"isMulticast": false,
+		"createTime":  "2018-01-01 00:00:00",
+	}
+}
+
+// findVpc looks up the vpc of params, a vpc is only visible in its own region.
+func (m *mockCloud) findVpc(params map[string]string) (*mockVpc, *mockError) {
+	vpc, ok := m.vpcs[params["vpcId"]]
+	if !ok || vpc.region != params["Region"] {
+		return nil, &mockError{"InvalidVpc.NotFound", fmt.Sprintf("vpc %v not found", params["vpcId"])}
+	}
+	return vpc, nil
+}
+
+// Region returns the region of a vpc, or an empty string if it does not exist.
+func (m *mockCloud) Region(vpcId string) string {
+	m.Lock()
+	defer m.Unlock()
+
+	if vpc, ok := m.vpcs[vpcId]; ok {
+		return vpc.region
+	}
+	return ""
+}
+
+func mockCreateVpc(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	if params["vpcName"] == "" || params["cidrBlock"] == "" {
+		return nil, &mockError{"InvalidParameter", "vpcName and cidrBlock are required"}
+	}
+	vpc := &mockVpc{
+		id:           m.newId("vpc"),
+		region:       params["Region"],
+		name:         params["vpcName"],
+		cidrBlock:    params["cidrBlock"],
+		routeTableId: m.newId("rtb"),
+	}
+	m.vpcs[vpc.id] = vpc
+	return map[string]interface{}{
+		"vpcId":     vpc.id,
+		"uniqVpcId": vpc.id,
+	}, nil
+}
+
+func mockDescribeVpcEx(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	data := []map[string]interface{}{}
+	for _, vpc := range m.vpcs {
+		if vpc.region != params["Region"] {
+			continue
+		}
+		if id := params["vpcId"]; id != "" && id != vpc.id {
+			continue
+		}
+		if name := params["vpcName"]; name != "" && name != vpc.name {
+			continue
+		}
+		data = append(data, vpc.toMap())
+	}
+	return map[string]interface{}{
+		"totalCount": len(data),
+		"data":       data,
+	}, nil
+}
+
+func mockModifyVpcAttribute(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	vpc, mErr := m.findVpc(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	if name, ok := params["vpcName"]; ok {
+		vpc.name = name
+	}
+	return nil, nil
+}
+
+func mockDeleteVpc(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	vpc, mErr := m.findVpc(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	for _, subnet := range m.subnets {
+		if subnet.vpcId == vpc.id {
+			return nil, &mockError{"InvalidVpc.CannotDelete", fmt.Sprintf("vpc %v still has subnets", vpc.id)}
+		}
+	}
+	for _, nat := range m.nats {
+		if nat.vpcId == vpc.id {
+			return nil, &mockError{"InvalidVpc.CannotDelete", fmt.Sprintf("vpc %v still has nat gateways", vpc.id)}
+		}
+	}
+	for _, peering := range m.peerings {
+		if peering.vpcId == vpc.id || peering.peerVpcId == vpc.id {
+			return nil, &mockError{"InvalidVpc.CannotDelete", fmt.Sprintf("vpc %v still has peering connections", vpc.id)}
+		}
+	}
+	for _, gw := range m.vpnGws {
+		if gw.vpcId == vpc.id {
+			return nil, &mockError{"InvalidVpc.CannotDelete", fmt.Sprintf("vpc %v still has vpn gateways", vpc.id)}
+		}
+	}
+	delete(m.vpcs, vpc.id)
+	return nil, nil
+}
+
+func mockCreateSubnet(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	vpc, mErr := m.findVpc(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	subnet := &mockSubnet{
+		id:        m.newId("subnet"),
+		vpcId:     vpc.id,
+		name:      params["subnetSet.0.subnetName"],
+		cidrBlock: params["subnetSet.0.cidrBlock"],
+		zone:      params["subnetSet.0.zoneId"],
+	}
+	m.subnets[subnet.id] = subnet
+	return map[string]interface{}{
+		"subnetSet": []map[string]interface{}{
+			{
+				"subnetId":   subnet.id,
+				"unSubnetId": subnet.id,
+				"subnetName": subnet.name,
+				"cidrBlock":  subnet.cidrBlock,
+			},
+		},
+	}, nil
+}
+
+func (m *mockCloud) findSubnet(params map[string]string) (*mockSubnet, *mockError) {
+	if _, ok := m.vpcs[params["vpcId"]]; !ok {
+		return nil, &mockError{"InvalidVpc.NotFound", fmt.Sprintf("vpc %v not found", params["vpcId"])}
+	}
+	subnet, ok := m.subnets[params["subnetId"]]
+	if !ok || subnet.vpcId != params["vpcId"] {
+		return nil, &mockError{"InvalidSubnet.NotFound", fmt.Sprintf("subnet %v not found", params["subnetId"])}
+	}
+	return subnet, nil
+}
+
+func mockDescribeSubnet(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	subnet, mErr := m.findSubnet(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	return map[string]interface{}{
+		"vpcId":        subnet.vpcId,
+		"unSubnetId":   subnet.id,
+		"subnetName":   subnet.name,
+		"cidrBlock":    subnet.cidrBlock,
+		"zoneId":       mockZoneId(subnet.zone),
+		"routeTableId": m.vpcs[subnet.vpcId].routeTableId,
+	}, nil
+}
+
+func mockDescribeSubnetEx(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	data := []map[string]interface{}{}
+	for _, subnet := range m.subnets {
+		if m.vpcs[subnet.vpcId].region != params["Region"] {
+			continue
+		}
+		if id := params["subnetId"]; id != "" && id != subnet.id {
+			continue
+		}
+		if id := params["vpcId"]; id != "" && id != subnet.vpcId {
+			continue
+		}
+		data = append(data, map[string]interface{}{
+			"vpcId":      subnet.vpcId,
+			"subnetId":   subnet.id,
+			"subnetName": subnet.name,
+			"cidrBlock":  subnet.cidrBlock,
+			"zoneId":     mockZoneId(subnet.zone),
+		})
+	}
+	return map[string]interface{}{
+		"totalCount": len(data),
+		"data":       data,
+	}, nil
+}
+
+func mockModifySubnetAttribute(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	subnet, mErr := m.findSubnet(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	if name, ok := params["subnetName"]; ok {
+		subnet.name = name
+	}
+	return nil, nil
+}
+
+func mockDeleteSubnet(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	subnet, mErr := m.findSubnet(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	for _, ins := range m.instances {
+		if ins.subnetId == subnet.id {
+			return nil, &mockError{"InvalidSubnet.CannotDelete", fmt.Sprintf("subnet %v still has instances", subnet.id)}
+		}
+	}
+	delete(m.subnets, subnet.id)
+	return nil, nil
+}
+
+// eip
+
+func (eip *mockEip) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"AddressId":        eip.id,
+		"AddressIp":        eip.ip,
+		"AddressName":      eip.name,
+		"AddressStatus":    eip.status,
+		"InstanceId":       eip.instanceId,
+		"BindedResourceId": eip.instanceId,
+		"IsArrears":        false,
+		"IsBlocked":        false,
+		"CreatedTime":      "2018-01-01T00:00:00Z",
+	}
+}
+
+func (eip *mockEip) advance() {
+	if eip.nextStatus != "" {
+		eip.status = eip.nextStatus
+		eip.nextStatus = ""
+	}
+}
+
+func (m *mockCloud) findEip(id string) (*mockEip, *mockError) {
+	eip, ok := m.eips[id]
+	if !ok {
+		return nil, &mockError{"InvalidAddressId.NotFound", fmt.Sprintf("eip %v not found", id)}
+	}
+	return eip, nil
+}
+
+func mockAllocateAddresses(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	count := intParam(params, "AddressCount", 1)
+	var ids []string
+	for i := 0; i < count; i++ {
+		eip := &mockEip{
+			id:         m.newId("eip"),
+			ip:         fmt.Sprintf("100.100.%d.%d", m.seq/250, m.seq%250+2),
+			status:     tencentCloudApiEipStatusCreating,
+			nextStatus: tencentCloudApiEipStatusUnbind,
+		}
+		m.eips[eip.id] = eip
+		ids = append(ids, eip.id)
+	}
+	return map[string]interface{}{"AddressSet": ids}, nil
+}
+
+func mockDescribeAddresses(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	ids := listParam(params, "AddressIds")
+	for i := 0; ; i++ {
+		name, ok := params[fmt.Sprintf("Filters.%d.Name", i)]
+		if !ok {
+			break
+		}
+		if name == "address-id" {
+			ids = append(ids, listParam(params, fmt.Sprintf("Filters.%d.Values", i))...)
+		}
+	}
+	var eips []*mockEip
+	if len(ids) == 0 {
+		for _, eip := range m.eips {
+			eips = append(eips, eip)
+		}
+	}
+	for _, id := range ids {
+		if eip, ok := m.eips[id]; ok {
+			eips = append(eips, eip)
+		}
+	}
+	set := []map[string]interface{}{}
+	for _, eip := range eips {
+		set = append(set, eip.toMap())
+		eip.advance()
+	}
+	return map[string]interface{}{
+		"TotalCount": len(set),
+		"AddressSet": set,
+	}, nil
+}
+
+func mockModifyAddressAttribute(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	eip, mErr := m.findEip(params["AddressId"])
+	if mErr != nil {
+		return nil, mErr
+	}
+	eip.name = params["AddressName"]
+	return nil, nil
+}
+
+func mockReleaseAddresses(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	for _, id := range listParam(params, "AddressIds") {
+		eip, mErr := m.findEip(id)
+		if mErr != nil {
+			return nil, mErr
+		}
+		if eip.status != tencentCloudApiEipStatusUnbind {
+			return nil, &mockError{"InvalidAddressState", fmt.Sprintf("eip %v is %v", id, eip.status)}
+		}
+		delete(m.eips, id)
+	}
+	return nil, nil
+}
+
+func mockAssociateAddress(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	eip, mErr := m.findEip(params["AddressId"])
+	if mErr != nil {
+		return nil, mErr
+	}
+	if eip.status != tencentCloudApiEipStatusUnbind {
+		return nil, &mockError{"InvalidAddressState", fmt.Sprintf("eip %v is %v", eip.id, eip.status)}
+	}
+	instanceId := params["InstanceId"]
+	if _, ok := m.instances[instanceId]; !ok && instanceId != "" {
+		return nil, &mockError{"InvalidInstanceId.NotFound", fmt.Sprintf("instance %v not found", instanceId)}
+	}
+	eip.instanceId = instanceId
+	eip.status, eip.nextStatus = tencentCloudApiEipStatusBinding, tencentCloudApiEipStatusBind
+	return nil, nil
+}
+
+func mockDisassociateAddress(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	eip, mErr := m.findEip(params["AddressId"])
+	if mErr != nil {
+		return nil, mErr
+	}
+	eip.instanceId = ""
+	eip.status, eip.nextStatus = tencentCloudApiEipStatusUnbinding, tencentCloudApiEipStatusUnbind
+	return nil, nil
+}
+
+// cbs
+
+func (disk *mockDisk) toMap() map[string]interface{} {
+	attached := 0
+	if disk.instanceId != "" {
+		attached = 1
+	}
+	return map[string]interface{}{
+		"storageId":     disk.id,
+		"storageType":   disk.storageType,
+		"storageSize":   disk.size,
+		"zone":          disk.zone,
+		"storageName":   disk.name,
+		"storageStatus": "normal",
+		"attached":      attached,
+		"uInstanceId":   disk.instanceId,
+		"diskType":      "data",
+	}
+}
+
+func (m *mockCloud) findDisks(params map[string]string) ([]*mockDisk, *mockError) {
+	var disks []*mockDisk
+	for _, id := range listParam(params, "storageIds") {
+		disk, ok := m.disks[id]
+		if !ok {
+			return nil, &mockError{"InvalidStorage.NotFound", fmt.Sprintf("storage %v not found", id)}
+		}
+		disks = append(disks, disk)
+	}
+	return disks, nil
+}
+
+func mockCreateCbsStorages(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	if params["storageType"] == "" || params["zone"] == "" {
+		return nil, &mockError{"InvalidParameter", "storageType and zone are required"}
+	}
+	count := intParam(params, "goodsNum", 1)
+	var ids []string
+	for i := 0; i < count; i++ {
+		disk := &mockDisk{
+			id:          m.newId("disk"),
+			storageType: params["storageType"],
+			size:        intParam(params, "storageSize", 10),
+			zone:        params["zone"],
+		}
+		disk.name = disk.id
+		m.disks[disk.id] = disk
+		ids = append(ids, disk.id)
+	}
+	return map[string]interface{}{
+		"dealNames":  []string{m.newId("deal")},
+		"storageIds": ids,
+	}, nil
+}
+
+func mockDescribeCbsStorages(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	set := []map[string]interface{}{}
+	ids := listParam(params, "storageIds")
+	if len(ids) == 0 {
+		for _, disk := range m.disks {
+			set = append(set, disk.toMap())
+		}
+	}
+	for _, id := range ids {
+		if disk, ok := m.disks[id]; ok {
+			set = append(set, disk.toMap())
+		}
+	}
+	return map[string]interface{}{
+		"totalCount": len(set),
+		"storageSet": set,
+	}, nil
+}
+
+func mockModifyCbsStorageAttributes(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	disk, ok := m.disks[params["storageId"]]
+	if !ok {
+		return nil, &mockError{"InvalidStorage.NotFound", fmt.Sprintf("storage %v not found", params["storageId"])}
+	}
+	disk.name = params["storageName"]
+	return nil, nil
+}
+
+func mockTerminateCbsStorages(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	disks, mErr := m.findDisks(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	for _, disk := range disks {
+		if disk.instanceId != "" {
+			return nil, &mockError{"InvalidStorage.Attached", fmt.Sprintf("storage %v is attached", disk.id)}
+		}
+	}
+	for _, disk := range disks {
+		delete(m.disks, disk.id)
+	}
+	return nil, nil
+}
+
+func mockAttachCbsStorages(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	disks, mErr := m.findDisks(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	if _, ok := m.instances[params["uInstanceId"]]; !ok {
+		return nil, &mockError{"InvalidInstanceId.NotFound", fmt.Sprintf("instance %v not found", params["uInstanceId"])}
+	}
+	detail := make(map[string]interface{})
+	for _, disk := range disks {
+		disk.instanceId = params["uInstanceId"]
+		detail[disk.id] = map[string]interface{}{"code": 0, "msg": ""}
+	}
+	return map[string]interface{}{"detail": detail}, nil
+}
+
+func mockDetachCbsStorages(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	disks, mErr := m.findDisks(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	detail := make(map[string]interface{})
+	for _, disk := range disks {
+		// detaching makes the instance go through PENDING
+		if ins, ok := m.instances[disk.instanceId]; ok {
+			ins.state, ins.nextState = "PENDING", "RUNNING"
+		}
+		disk.instanceId = ""
+		detail[disk.id] = map[string]interface{}{"code": 0, "msg": ""}
+	}
+	return map[string]interface{}{"detail": detail}, nil
+}
+
+// nat gateway
+
+func (nat *mockNat) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"natId":            nat.id,
+		"unVpcID":          nat.vpcId,
+		"natName":          nat.name,
+		"state":            0,
+		"maxConcurrent":    nat.maxConcurrent,
+		"bandwidth":        nat.bandwidth,
+		"eipCount":         len(nat.eips),
+		"eipSet":           nat.eips,
+		"productionStatus": 0,
+		"createTime":       "2018-01-01 00:00:00",
+	}
+}
+
+func (m *mockCloud) findNat(params map[string]string) (*mockNat, *mockError) {
+	nat, ok := m.nats[params["natId"]]
+	if !ok {
+		return nil, &mockError{"InvalidNatGatewayId.NotFound", fmt.Sprintf("nat gateway %v not found", params["natId"])}
+	}
+	return nat, nil
+}
+
+func (m *mockCloud) findEipByIp(ip string) *mockEip {
+	for _, eip := range m.eips {
+		if eip.ip == ip {
+			return eip
+		}
+	}
+	return nil
+}
+
+// bindNatEips binds the eips given by public ip, all or nothing.
+func (m *mockCloud) bindNatEips(nat *mockNat, ips []string) *mockError {
+	var eips []*mockEip
+	for _, ip := range ips {
+		eip := m.findEipByIp(ip)
+		if eip == nil {
+			return &mockError{"InvalidAddress.NotFound", fmt.Sprintf("eip %v not found", ip)}
+		}
+		if eip.status != tencentCloudApiEipStatusUnbind {
+			return &mockError{"InvalidAddressState", fmt.Sprintf("eip %v is %v", ip, eip.status)}
+		}
+		eips = append(eips, eip)
+	}
+	for _, eip := range eips {
+		eip.status = tencentCloudApiEipStatusBind
+		eip.instanceId = nat.id
+		nat.eips = append(nat.eips, eip.ip)
+	}
+	return nil
+}
+
+func (m *mockCloud) unbindNatEips(nat *mockNat, ips []string) {
+	var remain []string
+	for _, ip := range nat.eips {
+		unbind := false
+		for _, unbindIp := range ips {
+			if ip == unbindIp {
+				unbind = true
+			}
+		}
+		if !unbind {
+			remain = append(remain, ip)
+			continue
+		}
+		if eip := m.findEipByIp(ip); eip != nil {
+			eip.status = tencentCloudApiEipStatusUnbind
+			eip.instanceId = ""
+		}
+	}
+	nat.eips = remain
+}
+
+func (m *mockCloud) newTask() int {
+	m.seq++
+	m.tasks[m.seq] = true
+	return m.seq
+}
+
+func (m *mockCloud) newBill() string {
+	id := m.newId("bill")
+	m.bills[id] = true
+	return id
+}
+
+func mockCreateNatGateway(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	if _, ok := m.vpcs[params["vpcId"]]; !ok {
+		return nil, &mockError{"InvalidVpc.NotFound", fmt.Sprintf("vpc %v not found", params["vpcId"])}
+	}
+	nat := &mockNat{
+		id:            m.newId("nat"),
+		vpcId:         params["vpcId"],
+		name:          params["natName"],
+		maxConcurrent: intParam(params, "maxConcurrent", 1000000),
+		bandwidth:     intParam(params, "bandwidth", 100),
+	}
+	if mErr := m.bindNatEips(nat, listParam(params, "assignedEipSet")); mErr != nil {
+		return nil, mErr
+	}
+	m.nats[nat.id] = nat
+	return map[string]interface{}{
+		"billId":       m.newBill(),
+		"natGatewayId": nat.id,
+	}, nil
+}
+
+func mockQueryNatGatewayProductionStatus(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	if !m.bills[params["billId"]] {
+		return nil, &mockError{"InvalidBillId.NotFound", fmt.Sprintf("bill %v not found", params["billId"])}
+	}
+	return map[string]interface{}{
+		"data": map[string]interface{}{"status": 0},
+	}, nil
+}
+
+func mockDescribeNatGateway(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	data := []map[string]interface{}{}
+	for _, nat := range m.nats {
+		if id := params["natId"]; id != "" && id != nat.id {
+			continue
+		}
+		if vpcId := params["vpcId"]; vpcId != "" && vpcId != nat.vpcId {
+			continue
+		}
+		if name := params["natName"]; name != "" && name != nat.name {
+			continue
+		}
+		data = append(data, nat.toMap())
+	}
+	return map[string]interface{}{
+		"totalCount": len(data),
+		"data":       data,
+	}, nil
+}
+
+func mockModifyNatGateway(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	nat, mErr := m.findNat(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	if name, ok := params["natName"]; ok {
+		nat.name = name
+	}
+	nat.bandwidth = intParam(params, "bandwidth", nat.bandwidth)
+	return nil, nil
+}
+
+func mockUpgradeNatGateway(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	nat, mErr := m.findNat(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	maxConcurrent := intParam(params, "maxConcurrent", 0)
+	if maxConcurrent <= nat.maxConcurrent {
+		return nil, &mockError{"InvalidParameter", "maxConcurrent only supports upgrade"}
+	}
+	nat.maxConcurrent = maxConcurrent
+	return map[string]interface{}{"billId": m.newBill()}, nil
+}
+
+func mockEipBindNatGateway(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	nat, mErr := m.findNat(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	if mErr := m.bindNatEips(nat, listParam(params, "assignedEipSet")); mErr != nil {
+		return nil, mErr
+	}
+	return map[string]interface{}{"taskId": m.newTask()}, nil
+}
+
+func mockEipUnBindNatGateway(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	nat, mErr := m.findNat(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	m.unbindNatEips(nat, listParam(params, "assignedEipSet"))
+	return map[string]interface{}{"taskId": m.newTask()}, nil
+}
+
+func mockDeleteNatGateway(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	nat, mErr := m.findNat(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	m.unbindNatEips(nat, nat.eips)
+	delete(m.nats, nat.id)
+	return map[string]interface{}{"taskId": m.newTask()}, nil
+}
+
+func mockDescribeVpcTaskResult(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	taskId := intParam(params, "taskId", 0)
+	if !m.tasks[taskId] {
+		return nil, &mockError{"InvalidTaskId.NotFound", fmt.Sprintf("task %v not found", taskId)}
+	}
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"status": 0,
+			"output": map[string]interface{}{"errorCode": 0, "errorMsg": ""},
+		},
+	}, nil
+}
+
+// vpc peering connection
+
+func (peering *mockPeering) toMap() map[string]interface{} {
+	peerUin := peering.peerUin
+	if peerUin == "" {
+		peerUin = mockUin
+	}
+	return map[string]interface{}{
+		"peeringConnectionId":   peering.id,
+		"peeringConnectionName": peering.name,
+		"vpcId":                 peering.vpcId,
+		"unVpcId":               peering.vpcId,
+		"peerVpcId":             peering.peerVpcId,
+		"unPeerVpcId":           peering.peerVpcId,
+		"uin":                   mockUin,
+		"peerUin":               peerUin,
+		"region":                peering.region,
+		"peerRegion":            peering.peerRegion,
+		"bandwidth":             peering.bandwidth,
+		"state":                 peering.state,
+		"createTime":            "2018-01-01 00:00:00",
+	}
+}
+
+// findPeering looks up the peering connection of params, it is visible in the
+// regions of both of its vpcs and the cross-region ones only to the Ex
+// actions.
+func (m *mockCloud) findPeering(params map[string]string, crossRegion bool) (*mockPeering, *mockError) {
+	peering, ok := m.peerings[params["peeringConnectionId"]]
+	if !ok || (peering.region != params["Region"] && peering.peerRegion != params["Region"]) {
+		return nil, &mockError{"InvalidPeeringConnection.NotFound", fmt.Sprintf("peering connection %v not found", params["peeringConnectionId"])}
+	}
+	if (peering.region != peering.peerRegion) != crossRegion {
+		return nil, &mockError{"InvalidParameter", fmt.Sprintf("peering connection %v is not supported by action %v", peering.id, params["Action"])}
+	}
+	return peering, nil
+}
+
+func (m *mockCloud) createPeering(params map[string]string, peerRegion string) (*mockPeering, *mockError) {
+	vpc, mErr := m.findVpc(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	peerUin := params["peerUin"]
+	if peerUin == "" || peerUin == mockUin {
+		// the vpcs of another account are not emulated
+		peerVpc, ok := m.vpcs[params["peerVpcId"]]
+		if !ok || peerVpc.region != peerRegion {
+			return nil, &mockError{"InvalidVpc.NotFound", fmt.Sprintf("vpc %v not found", params["peerVpcId"])}
+		}
+	}
+	peering := &mockPeering{
+		id:         m.newId("pcx"),
+		name:       params["peeringConnectionName"],
+		vpcId:      vpc.id,
+		peerVpcId:  params["peerVpcId"],
+		region:     vpc.region,
+		peerRegion: peerRegion,
+		peerUin:    params["peerUin"],
+		state:      1,
+	}
+	if peerUin != "" && peerUin != mockUin {
+		peering.state = 0
+	}
+	m.peerings[peering.id] = peering
+	return peering, nil
+}
+
+func mockCreateVpcPeeringConnection(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	peering, mErr := m.createPeering(params, params["Region"])
+	if mErr != nil {
+		return nil, mErr
+	}
+	return map[string]interface{}{"peeringConnectionId": peering.id}, nil
+}
+
+func mockCreateVpcPeeringConnectionEx(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	if params["peerRegion"] == "" || params["peerRegion"] == params["Region"] {
+		return nil, &mockError{"InvalidParameter", "peerRegion must be another region"}
+	}
+	peering, mErr := m.createPeering(params, params["peerRegion"])
+	if mErr != nil {
+		return nil, mErr
+	}
+	peering.bandwidth = intParam(params, "bandwidth", 10)
+	return map[string]interface{}{
+		"taskId":                  m.newTask(),
+		"uniqPeeringConnectionId": peering.id,
+	}, nil
+}
+
+func (m *mockCloud) describePeerings(params map[string]string, crossRegion bool) map[string]interface{} {
+	data := []map[string]interface{}{}
+	for _, peering := range m.peerings {
+		if peering.region != params["Region"] && peering.peerRegion != params["Region"] {
+			continue
+		}
+		if (peering.region != peering.peerRegion) != crossRegion {
+			continue
+		}
+		if id := params["peeringConnectionId"]; id != "" && id != peering.id {
+			continue
+		}
+		if vpcId := params["vpcId"]; vpcId != "" && vpcId != peering.vpcId && vpcId != peering.peerVpcId {
+			continue
+		}
+		data = append(data, peering.toMap())
+	}
+	return map[string]interface{}{
+		"totalCount": len(data),
+		"data":       data,
+	}
+}
+
+func mockDescribeVpcPeeringConnections(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	return m.describePeerings(params, false), nil
+}
+
+func mockDescribeVpcPeeringConnectionsEx(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	return m.describePeerings(params, true), nil
+}
+
+// acceptPeering accepts a pending peering connection in the region of the
+// peer vpc.
+func (m *mockCloud) acceptPeering(params map[string]string, crossRegion bool) *mockError {
+	peering, mErr := m.findPeering(params, crossRegion)
+	if mErr != nil {
+		return mErr
+	}
+	if peering.peerRegion != params["Region"] {
+		return &mockError{"InvalidParameter", fmt.Sprintf("peering connection %v must be accepted in region %v", peering.id, peering.peerRegion)}
+	}
+	if peering.state != 0 {
+		return &mockError{"InvalidPeeringConnection.StateError", fmt.Sprintf("peering connection %v is not pending", peering.id)}
+	}
+	peering.state = 1
+	return nil
+}
+
+func mockAcceptVpcPeeringConnection(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	return nil, m.acceptPeering(params, false)
+}
+
+func mockAcceptVpcPeeringConnectionEx(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	if mErr := m.acceptPeering(params, true); mErr != nil {
+		return nil, mErr
+	}
+	return map[string]interface{}{"taskId": m.newTask()}, nil
+}
+
+func mockModifyVpcPeeringConnection(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	peering, mErr := m.findPeering(params, false)
+	if mErr != nil {
+		return nil, mErr
+	}
+	if name, ok := params["peeringConnectionName"]; ok {
+		peering.name = name
+	}
+	return nil, nil
+}
+
+func mockModifyVpcPeeringConnectionEx(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	peering, mErr := m.findPeering(params, true)
+	if mErr != nil {
+		return nil, mErr
+	}
+	if name, ok := params["peeringConnectionName"]; ok {
+		peering.name = name
+	}
+	if _, ok := params["bandwidth"]; !ok {
+		return nil, nil
+	}
+	peering.bandwidth = intParam(params, "bandwidth", peering.bandwidth)
+	return map[string]interface{}{"taskId": m.newTask()}, nil
+}
+
+func mockDeleteVpcPeeringConnection(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	peering, mErr := m.findPeering(params, false)
+	if mErr != nil {
+		return nil, mErr
+	}
+	delete(m.peerings, peering.id)
+	return nil, nil
+}
+
+func mockDeleteVpcPeeringConnectionEx(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	peering, mErr := m.findPeering(params, true)
+	if mErr != nil {
+		return nil, mErr
+	}
+	delete(m.peerings, peering.id)
+	return map[string]interface{}{"taskId": m.newTask()}, nil
+}
+
+// vpn
+
+func (gw *mockVpnGw) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"vpcId":        gw.vpcId,
+		"vpnGwId":      gw.id,
+		"vpnGwName":    gw.name,
+		"vpnGwAddress": gw.address,
+		"bandwidth":    gw.bandwidth,
+		"state":        1,
+		"vpnConnNum":   gw.connections,
+		"createTime":   "2018-01-01 00:00:00",
+		"expireTime":   time.Date(2018, time.Month(1+gw.period), 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02 15:04:05"),
+	}
+}
+
+func (gw *mockUserGw) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"userGwId":   gw.id,
+		"userGwName": gw.name,
+		"userGwAddr": gw.address,
+		"vpnConnNum": gw.connections,
+		"createTime": "2018-01-01 00:00:00",
+	}
+}
+
+func (conn *mockVpnConn) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"vpcId":        conn.vpcId,
+		"vpnGwId":      conn.vpnGwId,
+		"userGwId":     conn.userGwId,
+		"vpnConnId":    conn.id,
+		"vpnConnName":  conn.name,
+		"preSharedKey": conn.preSharedKey,
+		"SPDAcl":       conn.spdAcl,
+		"IKEArg":       conn.ike,
+		"IPSECArg":     conn.ipsec,
+		"vpnConnState": 1,
+		"netStatus":    "available",
+		"createTime":   "2018-01-01 00:00:00",
+	}
+}
+
+// mockVpnIkeDefaults and mockVpnIpsecDefaults are the options of a vpn
+// connection created without the IKESet or IPsecSet params.
+var mockVpnIkeDefaults = map[string]interface{}{
+	"propoEncryAlgorithm":  "3DES-CBC",
+	"propoAuthenAlgorithm": "MD5",
+	"exchangeMode":         "main",
+	"localIdentity":        "address",
+	"remoteIdentity":       "address",
+	"localAddress":         "",
+	"remoteAddress":        "",
+	"localFqdnName":        "",
+	"remoteFqdnName":       "",
+	"dhGroupName":          "group1",
+	"ikeSaLifetimeSeconds": 86400,
+}
+
+var mockVpnIpsecDefaults = map[string]interface{}{
+	"encryptAlgorithm":       "3DES-CBC",
+	"integrityAlgorith":      "MD5",
+	"ipsecSaLifetimeSeconds": 3600,
+	"ipsecSaLifetimeTraffic": 1843200,
+	"pfsDhGroup":             "NULL",
+}
+
+// vpnOptions overrides the options of base with the params under prefix,
+// e.g. IKESet.exchangeMode.
+func vpnOptions(params map[string]string, prefix string, base map[string]interface{}) map[string]interface{} {
+	options := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		options[k] = v
+		if _, ok := params[prefix+"."+k]; !ok {
+			continue
+		}
+		if n, isInt := v.(int); isInt {
+			options[k] = intParam(params, prefix+"."+k, n)
+		} else {
+			options[k] = params[prefix+"."+k]
+		}
+	}
+	return options
+}
+
+func (m *mockCloud) findVpnGw(params map[string]string) (*mockVpnGw, *mockError) {
+	gw, ok := m.vpnGws[params["vpnGwId"]]
+	if !ok || gw.region != params["Region"] {
+		return nil, &mockError{"InvalidVpnGw.NotFound", fmt.Sprintf("vpn gateway %v not found", params["vpnGwId"])}
+	}
+	return gw, nil
+}
+
+func (m *mockCloud) findUserGw(params map[string]string) (*mockUserGw, *mockError) {
+	gw, ok := m.userGws[params["userGwId"]]
+	if !ok || gw.region != params["Region"] {
+		return nil, &mockError{"InvalidUserGw.NotFound", fmt.Sprintf("customer gateway %v not found", params["userGwId"])}
+	}
+	return gw, nil
+}
+
+func (m *mockCloud) findVpnConn(params map[string]string) (*mockVpnConn, *mockError) {
+	conn, ok := m.vpnConns[params["vpnConnId"]]
+	if !ok || conn.region != params["Region"] {
+		return nil, &mockError{"InvalidVpnConn.NotFound", fmt.Sprintf("vpn connection %v not found", params["vpnConnId"])}
+	}
+	return conn, nil
+}
+
+func mockCreateVpn(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	vpc, mErr := m.findVpc(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	if params["vpnGwName"] == "" || params["bandwidth"] == "" || params["period"] == "" {
+		return nil, &mockError{"InvalidParameter", "vpnGwName, bandwidth and period are required"}
+	}
+	gw := &mockVpnGw{
+		id:        m.newId("vpngw"),
+		vpcId:     vpc.id,
+		region:    vpc.region,
+		name:      params["vpnGwName"],
+		bandwidth: intParam(params, "bandwidth", 0),
+		period:    intParam(params, "period", 0),
+		autoRenew: params["isAutoRenewals"] == "1",
+	}
+	gw.address = fmt.Sprintf("203.0.113.%d", m.seq%250+1)
+	m.vpnGws[gw.id] = gw
+	return map[string]interface{}{
+		"vpnGwId": gw.id,
+		"billId":  m.newBill(),
+	}, nil
+}
+
+func mockDescribeVpnGw(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	data := []map[string]interface{}{}
+	for _, gw := range m.vpnGws {
+		if gw.region != params["Region"] {
+			continue
+		}
+		if id := params["vpnGwId"]; id != "" && id != gw.id {
+			continue
+		}
+		if vpcId := params["vpcId"]; vpcId != "" && vpcId != gw.vpcId {
+			continue
+		}
+		if name := params["vpnGwName"]; name != "" && name != gw.name {
+			continue
+		}
+		data = append(data, gw.toMap())
+	}
+	return map[string]interface{}{
+		"totalCount": len(data),
+		"data":       data,
+	}, nil
+}
+
+func mockModifyVpnGw(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	gw, mErr := m.findVpnGw(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	if name, ok := params["vpnGwName"]; ok {
+		gw.name = name
+	}
+	return nil, nil
+}
+
+func mockAddUserGw(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	if params["userGwName"] == "" || params["userGwAddr"] == "" {
+		return nil, &mockError{"InvalidParameter", "userGwName and userGwAddr are required"}
+	}
+	gw := &mockUserGw{
+		id:      m.newId("cgw"),
+		region:  params["Region"],
+		name:    params["userGwName"],
+		address: params["userGwAddr"],
+	}
+	m.userGws[gw.id] = gw
+	return map[string]interface{}{"userGwId": gw.id}, nil
+}
+
+func mockDescribeUserGw(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	data := []map[string]interface{}{}
+	for _, gw := range m.userGws {
+		if gw.region != params["Region"] {
+			continue
+		}
+		if id := params["userGwId"]; id != "" && id != gw.id {
+			continue
+		}
+		if name := params["userGwName"]; name != "" && name != gw.name {
+			continue
+		}
+		if addr := params["userGwAddr"]; addr != "" && addr != gw.address {
+			continue
+		}
+		data = append(data, gw.toMap())
+	}
+	return map[string]interface{}{
+		"totalCount": len(data),
+		"data":       data,
+	}, nil
+}
+
+func mockModifyUserGw(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	gw, mErr := m.findUserGw(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	if name, ok := params["userGwName"]; ok {
+		gw.name = name
+	}
+	return nil, nil
+}
+
+func mockDeleteUserGw(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	gw, mErr := m.findUserGw(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	if gw.connections > 0 {
+		return nil, &mockError{"InvalidUserGw.CannotDelete", fmt.Sprintf("customer gateway %v still has vpn connections", gw.id)}
+	}
+	delete(m.userGws, gw.id)
+	return nil, nil
+}
+
+// setVpnConn applies the params shared by AddVpnConn and ModifyVpnConn.
+func setVpnConn(conn *mockVpnConn, params map[string]string) *mockError {
+	if name, ok := params["vpnConnName"]; ok {
+		conn.name = name
+	}
+	if key, ok := params["preSharedKey"]; ok {
+		conn.preSharedKey = key
+	}
+	if spdAcl, ok := params["SPDAcl"]; ok {
+		var acl map[string][]string
+		if err := json.Unmarshal([]byte(spdAcl), &acl); err != nil || len(acl) == 0 {
+			return &mockError{"InvalidParameter", fmt.Sprintf("invalid SPDAcl %v", spdAcl)}
+		}
+		conn.spdAcl = acl
+	}
+	conn.ike = vpnOptions(params, "IKESet", conn.ike)
+	conn.ipsec = vpnOptions(params, "IPsecSet", conn.ipsec)
+	return nil
+}
+
+func mockAddVpnConn(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	vpc, mErr := m.findVpc(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	vpnGw, mErr := m.findVpnGw(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	userGw, mErr := m.findUserGw(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	if vpnGw.vpcId != vpc.id {
+		return nil, &mockError{"InvalidParameter", fmt.Sprintf("vpn gateway %v is not in vpc %v", vpnGw.id, vpc.id)}
+	}
+	if params["vpnConnName"] == "" || params["preSharedKey"] == "" || params["SPDAcl"] == "" {
+		return nil, &mockError{"InvalidParameter", "vpnConnName, preSharedKey and SPDAcl are required"}
+	}
+	conn := &mockVpnConn{
+		id:       m.newId("vpnx"),
+		vpcId:    vpc.id,
+		region:   vpc.region,
+		vpnGwId:  vpnGw.id,
+		userGwId: userGw.id,
+		ike:      mockVpnIkeDefaults,
+		ipsec:    mockVpnIpsecDefaults,
+	}
+	if mErr := setVpnConn(conn, params); mErr != nil {
+		return nil, mErr
+	}
+	if conn.ike["localAddress"] == "" {
+		conn.ike["localAddress"] = vpnGw.address
+	}
+	if conn.ike["remoteAddress"] == "" {
+		conn.ike["remoteAddress"] = userGw.address
+	}
+	m.vpnConns[conn.id] = conn
+	vpnGw.connections++
+	userGw.connections++
+	return map[string]interface{}{
+		"vpnConnId": conn.id,
+		"taskId":    m.newTask(),
+	}, nil
+}
+
+func mockDescribeVpnConn(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	data := []map[string]interface{}{}
+	for _, conn := range m.vpnConns {
+		if conn.region != params["Region"] {
+			continue
+		}
+		if id := params["vpnConnId"]; id != "" && id != conn.id {
+			continue
+		}
+		if vpcId := params["vpcId"]; vpcId != "" && vpcId != conn.vpcId {
+			continue
+		}
+		if vpnGwId := params["vpnGwId"]; vpnGwId != "" && vpnGwId != conn.vpnGwId {
+			continue
+		}
+		if userGwId := params["userGwId"]; userGwId != "" && userGwId != conn.userGwId {
+			continue
+		}
+		if name := params["vpnConnName"]; name != "" && name != conn.name {
+			continue
+		}
+		data = append(data, conn.toMap())
+	}
+	return map[string]interface{}{
+		"totalCount": len(data),
+		"data":       data,
+	}, nil
+}
+
+func mockModifyVpnConn(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	conn, mErr := m.findVpnConn(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	if mErr := setVpnConn(conn, params); mErr != nil {
+		return nil, mErr
+	}
+	return map[string]interface{}{"taskId": m.newTask()}, nil
+}
+
+func mockDeleteVpnConn(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	conn, mErr := m.findVpnConn(params)
+	if mErr != nil {
+		return nil, mErr
+	}
+	delete(m.vpnConns, conn.id)
+	if gw, ok := m.vpnGws[conn.vpnGwId]; ok {
+		gw.connections--
+	}
+	if gw, ok := m.userGws[conn.userGwId]; ok {
+		gw.connections--
+	}
+	return map[string]interface{}{"taskId": m.newTask()}, nil
+}
+
+// sts
+
+func mockAssumeRole(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	if params["RoleArn"] == "" || params["RoleSessionName"] == "" {
+		return nil, &mockError{"InvalidParameter", "RoleArn and RoleSessionName are required"}
+	}
+	m.assumeRoles++
+	duration := intParam(params, "DurationSeconds", stsDefaultSessionDuration)
+	expiredTime := time.Now().Add(time.Duration(duration) * time.Second)
+	return map[string]interface{}{
+		"Credentials": map[string]interface{}{
+			"TmpSecretId":  fmt.Sprintf("mock-tmp-id-%d", m.assumeRoles),
+			"TmpSecretKey": fmt.Sprintf("mock-tmp-key-%d", m.assumeRoles),
+			"Token":        fmt.Sprintf("mock-token-%d", m.assumeRoles),
+		},
+		"ExpiredTime": expiredTime.Unix(),
+		"Expiration":  expiredTime.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// tag
+
+// Tags returns the tags of a resource by its six-segment name.
+// Untag deletes a tag of the resource behind the back of the provider.
+func (m *mockCloud) Untag(resource, key string) {
+	m.Lock()
+	defer m.Unlock()
+	delete(m.tags[resource], key)
+}
+
+func (m *mockCloud) Tags(resource string) map[string]string {
+	m.Lock()
+	defer m.Unlock()
+	tags := make(map[string]string)
+	for k, v := range m.tags[resource] {
+		tags[k] = v
+	}
+	return tags
+}
+
+func mockModifyResourceTags(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	resource := params["Resource"]
+	if !strings.HasPrefix(resource, "qcs::") {
+		return nil, &mockError{"InvalidParameter.ResourceFormat", fmt.Sprintf("invalid resource %v", resource)}
+	}
+	tags := m.tags[resource]
+	if tags == nil {
+		tags = make(map[string]string)
+		m.tags[resource] = tags
+	}
+	for i := 0; params[fmt.Sprintf("ReplaceTags.%d.TagKey", i)] != ""; i++ {
+		tags[params[fmt.Sprintf("ReplaceTags.%d.TagKey", i)]] = params[fmt.Sprintf("ReplaceTags.%d.TagValue", i)]
+	}
+	for i := 0; params[fmt.Sprintf("DeleteTags.%d.TagKey", i)] != ""; i++ {
+		delete(tags, params[fmt.Sprintf("DeleteTags.%d.TagKey", i)])
+	}
+	return nil, nil
+}
+
+func mockDescribeResourceTagsByResourceIds(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	var tags []map[string]interface{}
+	for _, id := range listParam(params, "ResourceIds") {
+		resource := fmt.Sprintf("qcs::%s:%s:uin/:%s/%s", params["ServiceType"], params["ResourceRegion"], params["ResourcePrefix"], id)
+		for k, v := range m.tags[resource] {
+			tags = append(tags, map[string]interface{}{
+				"TagKey":     k,
+				"TagValue":   v,
+				"ResourceId": id,
+			})
+		}
+	}
+	return map[string]interface{}{
+		"TotalCount": len(tags),
+		"Offset":     0,
+		"Limit":      intParam(params, "Limit", 15),
+		"Tags":       tags,
+	}, nil
+}
+
+// security group
+
+func mockCreateSecurityGroup(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	if params["sgName"] == "" {
+		return nil, &mockError{"InvalidParameter", "sgName is required"}
+	}
+	sg := &mockSecurityGroup{
+		id:      m.newId("sg"),
+		name:    params["sgName"],
+		remark:  params["sgRemark"],
+		version: 1,
+	}
+	m.sgs[sg.id] = sg
+	return map[string]interface{}{
+		"data": map[string]interface{}{"sgId": sg.id},
+	}, nil
+}
+
+// mockDescribeSecurityGroupEx lists the security groups in the order of their
+// ids, sgName matches a part of the name.
+func mockDescribeSecurityGroupEx(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	var ids []string
+	for id, sg := range m.sgs {
+		if params["sgId"] != "" && sg.id != params["sgId"] {
+			continue
+		}
+		if params["sgName"] != "" && !strings.Contains(sg.name, params["sgName"]) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	total := len(ids)
+	if offset := intParam(params, "offset", 0); offset < len(ids) {
+		ids = ids[offset:]
+	} else {
+		ids = nil
+	}
+	if limit := intParam(params, "limit", 20); limit < len(ids) {
+		ids = ids[:limit]
+	}
+
+	detail := []map[string]interface{}{}
+	for _, id := range ids {
+		sg := m.sgs[id]
+		count := 0
+		for _, ins := range m.instances {
+			for _, sgId := range ins.securityGroups {
+				if sgId == sg.id {
+					count++
+				}
+			}
+		}
+		detail = append(detail, map[string]interface{}{
+			"sgId":             sg.id,
+			"sgName":           sg.name,
+			"sgRemark":         sg.remark,
+			"beAssociateCount": count,
+			"createTime":       "2018-01-01 00:00:00",
+		})
+	}
+	return map[string]interface{}{
+		"data": map[string]interface{}{
+			"totalNum": total,
+			"detail":   detail,
+		},
+	}, nil
+}
+
+func (m *mockCloud) findSecurityGroup(sgId string) (*mockSecurityGroup, *mockError) {
+	sg, ok := m.sgs[sgId]
+	if !ok {
+		return nil, &mockError{"ResourceNotFound", fmt.Sprintf("security group %v not found", sgId)}
+	}
+	return sg, nil
+}
+
+func mockModifySecurityGroupAttributes(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	sg, mErr := m.findSecurityGroup(params["sgId"])
+	if mErr != nil {
+		return nil, mErr
+	}
+	if v, ok := params["sgName"]; ok {
+		sg.name = v
+	}
+	if v, ok := params["sgRemark"]; ok {
+		sg.remark = v
+	}
+	return nil, nil
+}
+
+func mockDeleteSecurityGroup(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	sg, mErr := m.findSecurityGroup(params["sgId"])
+	if mErr != nil {
+		return nil, mErr
+	}
+	delete(m.sgs, sg.id)
+	return nil, nil
+}
+
+func mockDescribeInstancesOfSecurityGroup(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	data := []map[string]interface{}{}
+	for _, ins := range m.instances {
+		for _, sgId := range ins.securityGroups {
+			if sgId == params["sgId"] {
+				data = append(data, map[string]interface{}{"instanceId": ins.id})
+			}
+		}
+	}
+	return map[string]interface{}{
+		"totalCount": len(data),
+		"data":       data,
+	}, nil
+}
+
+// mockPolicyFields are the flattened fields of a security group policy.
+var mockPolicyFields = []string{
+	"Protocol",
+	"Port",
+	"ServiceTemplate.ServiceId",
+	"ServiceTemplate.ServiceGroupId",
+	"CidrBlock",
+	"Ipv6CidrBlock",
+	"SecurityGroupId",
+	"AddressTemplate.AddressId",
+	"AddressTemplate.AddressGroupId",
+	"Action",
+	"PolicyDescription",
+}
+
+// mockPolicyParams collects the policies of the direction, i.e. Ingress or
+// Egress, from the params of SecurityGroupPolicySet, the protocol and the
+// action are stored in upper case and an empty protocol or port means ALL.
+func mockPolicyParams(params map[string]string, direction string) []map[string]string {
+	var policies []map[string]string
+	for i := 0; ; i++ {
+		prefix := fmt.Sprintf("SecurityGroupPolicySet.%s.%d.", direction, i)
+		policy := make(map[string]string)
+		for _, field := range mockPolicyFields {
+			if v := params[prefix+field]; v != "" {
+				policy[field] = v
+			}
+		}
+		if v, ok := params[prefix+"PolicyIndex"]; ok {
+			policy["PolicyIndex"] = v
+		}
+		if len(policy) == 0 {
+			return policies
+		}
+		policy["Protocol"] = strings.ToUpper(policy["Protocol"])
+		policy["Action"] = strings.ToUpper(policy["Action"])
+		if policy["ServiceTemplate.ServiceId"] == "" && policy["ServiceTemplate.ServiceGroupId"] == "" {
+			if policy["Protocol"] == "" {
+				policy["Protocol"] = "ALL"
+			}
+			if policy["Port"] == "" {
+				policy["Port"] = "ALL"
+			}
+		}
+		policies = append(policies, policy)
+	}
+}
+
+func mockPolicyEqual(a, b map[string]string) bool {
+	for _, field := range mockPolicyFields {
+		if a[field] != b[field] {
+			return false
+		}
+	}
+	return true
+}
+
+func mockPolicyToMap(policy map[string]string, index int) map[string]interface{} {
+	return map[string]interface{}{
+		"PolicyIndex": index,
+		"Protocol":    policy["Protocol"],
+		"Port":        policy["Port"],
+		"ServiceTemplate": map[string]interface{}{
+			"ServiceId":      policy["ServiceTemplate.ServiceId"],
+			"ServiceGroupId": policy["ServiceTemplate.ServiceGroupId"],
+		},
+		"CidrBlock":       policy["CidrBlock"],
+		"Ipv6CidrBlock":   policy["Ipv6CidrBlock"],
+		"SecurityGroupId": policy["SecurityGroupId"],
+		"AddressTemplate": map[string]interface{}{
+			"AddressId":      policy["AddressTemplate.AddressId"],
+			"AddressGroupId": policy["AddressTemplate.AddressGroupId"],
+		},
+		"Action":            policy["Action"],
+		"PolicyDescription": policy["PolicyDescription"],
+		"ModifyTime":        "2018-01-01 00:00:00",
+	}
+}
+
+// checkPolicyVersion fails the request if it carries a version other than
+// the current one of the security group.
+func (sg *mockSecurityGroup) checkPolicyVersion(params map[string]string) *mockError {
+	if v, ok := params["SecurityGroupPolicySet.Version"]; ok && v != strconv.Itoa(sg.version) {
+		return &mockError{"UnsupportedOperation.VersionMismatch", fmt.Sprintf("version %v of security group %v is out of date, the current one is %v", v, sg.id, sg.version)}
+	}
+	return nil
+}
+
+func mockDescribeSecurityGroupPolicies(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	sg, mErr := m.findSecurityGroup(params["SecurityGroupId"])
+	if mErr != nil {
+		return nil, mErr
+	}
+	ingress, egress := []map[string]interface{}{}, []map[string]interface{}{}
+	for i, policy := range sg.ingress {
+		ingress = append(ingress, mockPolicyToMap(policy, i))
+	}
+	for i, policy := range sg.egress {
+		egress = append(egress, mockPolicyToMap(policy, i))
+	}
+	return map[string]interface{}{
+		"SecurityGroupPolicySet": map[string]interface{}{
+			"Version": strconv.Itoa(sg.version),
+			"Ingress": ingress,
+			"Egress":  egress,
+		},
+	}, nil
+}
+
+func mockCreateSecurityGroupPolicies(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	sg, mErr := m.findSecurityGroup(params["SecurityGroupId"])
+	if mErr != nil {
+		return nil, mErr
+	}
+	if mErr := sg.checkPolicyVersion(params); mErr != nil {
+		return nil, mErr
+	}
+	ingress, egress := mockPolicyParams(params, "Ingress"), mockPolicyParams(params, "Egress")
+	if len(ingress)+len(egress) == 0 {
+		return nil, &mockError{"InvalidParameter", "no policy to create"}
+	}
+	for _, policy := range append(ingress, egress...) {
+		if policy["Action"] != "ACCEPT" && policy["Action"] != "DROP" {
+			return nil, &mockError{"InvalidParameterValue", fmt.Sprintf("invalid action %v", policy["Action"])}
+		}
+	}
+	sg.ingress = append(sg.ingress, ingress...)
+	sg.egress = append(sg.egress, egress...)
+	sg.version++
+	return nil, nil
+}
+
+func mockDeleteSecurityGroupPolicies(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	sg, mErr := m.findSecurityGroup(params["SecurityGroupId"])
+	if mErr != nil {
+		return nil, mErr
+	}
+	if mErr := sg.checkPolicyVersion(params); mErr != nil {
+		return nil, mErr
+	}
+	remove := func(policies []map[string]string, deleted []map[string]string) ([]map[string]string, *mockError) {
+		for _, d := range deleted {
+			found := -1
+			for i, policy := range policies {
+				if mockPolicyEqual(policy, d) {
+					found = i
+					break
+				}
+			}
+			if found < 0 {
+				return nil, &mockError{"InvalidParameterValue", fmt.Sprintf("policy %v not found in security group %v", d, sg.id)}
+			}
+			policies = append(policies[:found:found], policies[found+1:]...)
+		}
+		return policies, nil
+	}
+	ingress, mErr := remove(sg.ingress, mockPolicyParams(params, "Ingress"))
+	if mErr != nil {
+		return nil, mErr
+	}
+	egress, mErr := remove(sg.egress, mockPolicyParams(params, "Egress"))
+	if mErr != nil {
+		return nil, mErr
+	}
+	sg.ingress, sg.egress = ingress, egress
+	sg.version++
+	return nil, nil
+}
+
+// mockModifySecurityGroupPolicies replaces all the policies, version 0
+// deletes all of them regardless of the ones in the request.
+func mockModifySecurityGroupPolicies(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	sg, mErr := m.findSecurityGroup(params["SecurityGroupId"])
+	if mErr != nil {
+		return nil, mErr
+	}
+	if params["SecurityGroupPolicySet.Version"] == "0" {
+		sg.ingress, sg.egress = nil, nil
+		sg.version++
+		return nil, nil
+	}
+	if mErr := sg.checkPolicyVersion(params); mErr != nil {
+		return nil, mErr
+	}
+	ingress, egress := mockPolicyParams(params, "Ingress"), mockPolicyParams(params, "Egress")
+	for _, policy := range append(ingress, egress...) {
+		if _, ok := policy["PolicyIndex"]; ok {
+			return nil, &mockError{"InvalidParameter", "PolicyIndex is not supported"}
+		}
+		if policy["Action"] != "ACCEPT" && policy["Action"] != "DROP" {
+			return nil, &mockError{"InvalidParameterValue", fmt.Sprintf("invalid action %v", policy["Action"])}
+		}
+	}
+	sg.ingress, sg.egress = ingress, egress
+	sg.version++
+	return nil, nil
+}
+
+func mockDescribeNetworkInterfaces(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	set := []map[string]interface{}{}
+	for _, id := range listParam(params, "NetworkInterfaceIds") {
+		if groups, ok := m.enis[id]; ok {
+			set = append(set, map[string]interface{}{
+				"NetworkInterfaceId": id,
+				"GroupSet":           groups,
+				"State":              "AVAILABLE",
+			})
+		}
+	}
+	return map[string]interface{}{
+		"NetworkInterfaceSet": set,
+		"TotalCount":          len(set),
+	}, nil
+}
+
+// setSecurityGroups adds the security groups to the ones of a network
+// interface or a load balancer or removes them.
+func (m *mockCloud) setSecurityGroups(groups map[string][]string, id string, sgIds []string, add bool) *mockError {
+	current, ok := groups[id]
+	if !ok {
+		return &mockError{"ResourceNotFound", fmt.Sprintf("%v not found", id)}
+	}
+	for _, sgId := range sgIds {
+		if _, mErr := m.findSecurityGroup(sgId); mErr != nil {
+			return mErr
+		}
+		i := 0
+		for i < len(current) && current[i] != sgId {
+			i++
+		}
+		if add && i == len(current) {
+			current = append(current, sgId)
+		}
+		if !add && i < len(current) {
+			current = append(current[:i:i], current[i+1:]...)
+		}
+	}
+	groups[id] = current
+	return nil
+}
+
+func mockAssociateNetworkInterfaceSecurityGroups(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	for _, id := range listParam(params, "NetworkInterfaceIds") {
+		if mErr := m.setSecurityGroups(m.enis, id, listParam(params, "SecurityGroupIds"), true); mErr != nil {
+			return nil, mErr
+		}
+	}
+	return nil, nil
+}
+
+func mockDisassociateNetworkInterfaceSecurityGroups(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	for _, id := range listParam(params, "NetworkInterfaceIds") {
+		if mErr := m.setSecurityGroups(m.enis, id, listParam(params, "SecurityGroupIds"), false); mErr != nil {
+			return nil, mErr
+		}
+	}
+	return nil, nil
+}
+
+func mockDescribeLoadBalancers(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	set := []map[string]interface{}{}
+	for _, id := range listParam(params, "LoadBalancerIds") {
+		if groups, ok := m.lbs[id]; ok {
+			set = append(set, map[string]interface{}{
+				"LoadBalancerId": id,
+				"SecureGroups":   groups,
+			})
+		}
+	}
+	return map[string]interface{}{
+		"LoadBalancerSet": set,
+		"TotalCount":      len(set),
+	}, nil
+}
+
+func mockSetSecurityGroupForLoadbalancers(m *mockCloud, params map[string]string) (map[string]interface{}, *mockError) {
+	operation := params["OperationType"]
+	if operation != "ADD" && operation != "DEL" {
+		return nil, &mockError{"InvalidParameterValue", fmt.Sprintf("invalid OperationType %v", operation)}
+	}
+	for _, id := range listParam(params, "LoadBalancerIds") {
+		if mErr := m.setSecurityGroups(m.lbs, id, []string{params["SecurityGroup"]}, operation == "ADD"); mErr != nil {
+			return nil, mErr
+		}
+	}
+	return nil, nil
+}
@@ -1,7 +1,10 @@
 package tencentcloud
 
 import (
-	"os"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 )
@@ -10,29 +13,140 @@ const (
 	PROVIDER_SECRET_ID  = "TENCENTCLOUD_SECRET_ID"
 	PROVIDER_SECRET_KEY = "TENCENTCLOUD_SECRET_KEY"
 	PROVIDER_REGION     = "TENCENTCLOUD_REGION"
+
+	PROVIDER_DOMAIN_SUFFIX  = "TENCENTCLOUD_DOMAIN_SUFFIX"
+	PROVIDER_SECURITY_TOKEN = "TENCENTCLOUD_SECURITY_TOKEN"
+
+	PROVIDER_PROFILE                 = "TENCENTCLOUD_PROFILE"
+	PROVIDER_SHARED_CREDENTIALS_FILE = "TENCENTCLOUD_SHARED_CREDENTIALS_FILE"
 )
 
+// endpointServices are the services whose endpoint can be overridden in the
+// endpoints block, note that eip is served by the cvm client and snapshot by
+// the cbs client, but they have their own domains.
+var endpointServices = []string{
+	"cvm",
+	"vpc",
+	"cbs",
+	"ccs",
+	"lb",
+	"dfw",
+	"eip",
+	"snapshot",
+	"image",
+	"sts",
+	"tag",
+	"clb",
+}
+
 func Provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"secret_id": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc(PROVIDER_SECRET_ID, nil),
 				Description: "Secret ID of Tencent Cloud",
 			},
 			"secret_key": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc(PROVIDER_SECRET_KEY, nil),
 				Description: "Secret key of Tencent Cloud",
 			},
 			"region": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc(PROVIDER_REGION, nil),
 				Description: "Region of Tencent Cloud",
 			},
+			"profile": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(PROVIDER_PROFILE, nil),
+				Description: "Profile in the shared credentials file, defaults to default",
+			},
+			"shared_credentials_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(PROVIDER_SHARED_CREDENTIALS_FILE, nil),
+				Description: "Path of the shared credentials file, defaults to ~/.tencentcloud/credentials",
+			},
+			"security_token": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc(PROVIDER_SECURITY_TOKEN, nil),
+				Description: "Security token of a temporary credential",
+			},
+			"assume_role": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role_arn": &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "ARN of the role to assume",
+						},
+						"session_name": &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Session name of the temporary credential",
+						},
+						"session_duration": &schema.Schema{
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      stsDefaultSessionDuration,
+							ValidateFunc: validateIntegerInRange(1, stsMaxSessionDuration),
+							Description:  "Lifetime of the temporary credential in seconds",
+						},
+						"policy": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Policy to further restrict the permissions of the temporary credential",
+						},
+					},
+				},
+			},
+			"domain_suffix": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(PROVIDER_DOMAIN_SUFFIX, nil),
+				Description: "Root domain of the API endpoints, defaults to api.qcloud.com",
+			},
+			"endpoints": endpointsSchema(),
+			"max_retries": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultMaxRetries,
+				ValidateFunc: validateIntegerInRange(0, 100),
+				Description:  "Maximum number of retries of a throttled or failed API request, 0 disables retries",
+			},
+			"retry_max_delay": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultRetryMaxDelay,
+				ValidateFunc: validateIntegerInRange(1, 3600),
+				Description:  "Maximum delay in seconds between two retries of an API request",
+			},
+			"rate_limit": rateLimitSchema(),
+			"default_tags": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tags": &schema.Schema{
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Tags applied to every taggable resource, the tags of a resource override the ones with the same key",
+						},
+					},
+				},
+			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
@@ -44,56 +158,266 @@ func Provider() *schema.Provider {
 			"tencentcloud_subnet":                      dataSourceTencentCloudSubnet(),
 			"tencentcloud_route_table":                 dataSourceTencentCloudRouteTable(),
 			"tencentcloud_security_group":              dataSourceTencentCloudSecurityGroup(),
+			"tencentcloud_security_groups":             dataSourceTencentCloudSecurityGroups(),
 			"tencentcloud_nats":                        dataSourceTencentCloudNats(),
+			"tencentcloud_vpn_gateways":                dataSourceTencentCloudVpnGateways(),
+			"tencentcloud_vpn_customer_gateways":       dataSourceTencentCloudVpnCustomerGateways(),
+			"tencentcloud_vpn_connections":             dataSourceTencentCloudVpnConnections(),
 			"tencentcloud_container_clusters":          dataSourceTencentCloudContainerClusters(),
 			"tencentcloud_container_cluster_instances": dataSourceTencentCloudContainerClusterInstances(),
 		},
 
-		ResourcesMap: map[string]*schema.Resource{
-			"tencentcloud_key_pair":                   resourceTencentCloudKeyPair(),
-			"tencentcloud_eip":                        resourceTencentCloudEip(),
-			"tencentcloud_eip_association":            resourceTencentCloudEipAssociation(),
-			"tencentcloud_instance":                   resourceTencentCloudInstance(),
-			"tencentcloud_cbs_storage":                resourceTencentCloudCbsStorage(),
-			"tencentcloud_cbs_storage_attachment":     resourceTencentCloudCbsStorageAttachment(),
-			"tencentcloud_cbs_snapshot":               resourceTencentCloudCbsSnapshot(),
-			"tencentcloud_vpc":                        resourceTencentCloudVpc(),
-			"tencentcloud_subnet":                     resourceTencentCloudSubnet(),
-			"tencentcloud_route_table":                resourceTencentCloudRouteTable(),
-			"tencentcloud_route_entry":                resourceTencentCloudRouteEntry(),
-			"tencentcloud_security_group":             resourceTencentCloudSecurityGroup(),
-			"tencentcloud_security_group_rule":        resourceTencentCloudSecurityGroupRule(),
-			"tencentcloud_nat_gateway":                resourceTencentCloudNatGateway(),
-			"tencentcloud_dnat":                       resourceTencentCloudDnat(),
-			"tencentcloud_alb_server_attachment":      resourceTencentCloudAlbServerAttachment(),
-			"tencentcloud_container_cluster":          resourceTencentCloudContainerCluster(),
-			"tencentcloud_container_cluster_instance": resourceTencentCloudContainerClusterInstance(),
-		},
+		ResourcesMap: regionalResources(map[string]*schema.Resource{
+			"tencentcloud_key_pair":                        resourceTencentCloudKeyPair(),
+			"tencentcloud_eip":                             resourceTencentCloudEip(),
+			"tencentcloud_eip_association":                 resourceTencentCloudEipAssociation(),
+			"tencentcloud_instance":                        resourceTencentCloudInstance(),
+			"tencentcloud_cbs_storage":                     resourceTencentCloudCbsStorage(),
+			"tencentcloud_cbs_storage_attachment":          resourceTencentCloudCbsStorageAttachment(),
+			"tencentcloud_cbs_snapshot":                    resourceTencentCloudCbsSnapshot(),
+			"tencentcloud_vpc":                             resourceTencentCloudVpc(),
+			"tencentcloud_subnet":                          resourceTencentCloudSubnet(),
+			"tencentcloud_route_table":                     resourceTencentCloudRouteTable(),
+			"tencentcloud_route_entry":                     resourceTencentCloudRouteEntry(),
+			"tencentcloud_security_group":                  resourceTencentCloudSecurityGroup(),
+			"tencentcloud_security_group_rule":             resourceTencentCloudSecurityGroupRule(),
+			"tencentcloud_security_group_attachment":       resourceTencentCloudSecurityGroupAttachment(),
+			"tencentcloud_security_group_eni_attachment":   resourceTencentCloudSecurityGroupEniAttachment(),
+			"tencentcloud_security_group_lb_attachment":    resourceTencentCloudSecurityGroupLbAttachment(),
+			"tencentcloud_nat_gateway":                     resourceTencentCloudNatGateway(),
+			"tencentcloud_vpc_peering_connection":          resourceTencentCloudVpcPeeringConnection(),
+			"tencentcloud_vpc_peering_connection_accepter": resourceTencentCloudVpcPeeringConnectionAccepter(),
+			"tencentcloud_vpn_gateway":                     resourceTencentCloudVpnGateway(),
+			"tencentcloud_vpn_customer_gateway":            resourceTencentCloudVpnCustomerGateway(),
+			"tencentcloud_vpn_connection":                  resourceTencentCloudVpnConnection(),
+			"tencentcloud_dnat":                            resourceTencentCloudDnat(),
+			"tencentcloud_alb_server_attachment":           resourceTencentCloudAlbServerAttachment(),
+			"tencentcloud_container_cluster":               resourceTencentCloudContainerCluster(),
+			"tencentcloud_container_cluster_instance":      resourceTencentCloudContainerClusterInstance(),
+		}),
 
 		ConfigureFunc: providerConfigure,
 	}
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	secretId, ok := d.GetOk("secret_id")
-	if !ok {
-		secretId = os.Getenv(PROVIDER_SECRET_ID)
-	}
-	secretKey, ok := d.GetOk("secret_key")
-	if !ok {
-		secretKey = os.Getenv(PROVIDER_SECRET_KEY)
-	}
-	region, ok := d.GetOk("region")
-	if !ok {
-		region = os.Getenv(PROVIDER_REGION)
-		if region == "" {
-			region = "ap-guangzhou"
+	config, err := providerConfig(d)
+	if err != nil {
+		return nil, err
+	}
+	return config.Client()
+}
+
+// regionalResources adds the optional region argument to every resource of
+// the map, see regionalResource.
+func regionalResources(resources map[string]*schema.Resource) map[string]*schema.Resource {
+	for name, r := range resources {
+		resources[name] = regionalResource(r)
+	}
+	return resources
+}
+
+// regionalResource lets a resource live in another region than the provider,
+// its operations are called with the client of that region, which is created
+// on first use and shared by all resources of the same region.
+func regionalResource(r *schema.Resource) *schema.Resource {
+	if _, ok := r.Schema["region"]; ok {
+		panic(fmt.Sprintf("resource already has a region argument: %v", r))
+	}
+	r.Schema["region"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+		ForceNew:    true,
+		Description: "Region of the resource, defaults to the region of the provider",
+	}
+
+	wrap := func(f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+		if f == nil {
+			return nil
+		}
+		return func(d *schema.ResourceData, meta interface{}) error {
+			client, err := meta.(*TencentCloudClient).withRegion(d.Get("region").(string))
+			if err != nil {
+				return err
+			}
+			if err := f(d, client); err != nil {
+				return err
+			}
+			if d.Id() != "" {
+				d.Set("region", client.region)
+			}
+			return nil
 		}
 	}
+	r.Create = wrap(r.Create)
+	r.Read = wrap(r.Read)
+	r.Update = wrap(r.Update)
+	r.Delete = wrap(r.Delete)
+
+	if r.Importer != nil && r.Importer.State != nil {
+		importState := r.Importer.State
+		r.Importer.State = func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			// a resource of another region is imported as {id}@{region}
+			if id, region, ok := parseRegionalImportId(d.Id()); ok {
+				d.SetId(id)
+				d.Set("region", region)
+			}
+			client, err := meta.(*TencentCloudClient).withRegion(d.Get("region").(string))
+			if err != nil {
+				return nil, err
+			}
+			results, err := importState(d, client)
+			if err != nil {
+				return nil, err
+			}
+			for _, result := range results {
+				result.Set("region", client.region)
+			}
+			return results, nil
+		}
+	}
+	return r
+}
+
+// regionalImportIdRegexp matches the region suffix of an import id, it does not
+// match the suffix of an id which contains @ on its own, e.g. the ip and port
+// of a dnat.
+var regionalImportIdRegexp = regexp.MustCompile(`^(.+)@([a-z]+-[a-z]+(?:-[a-z]+)?)$`)
+
+func parseRegionalImportId(importId string) (id, region string, ok bool) {
+	match := regionalImportIdRegexp.FindStringSubmatch(importId)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
+
+func providerConfig(d *schema.ResourceData) (*Config, error) {
+	profile, err := loadSharedCredentials(d.Get("shared_credentials_file").(string), d.Get("profile").(string))
+	if err != nil {
+		return nil, err
+	}
+	// the provider block and env have been merged by DefaultFunc already,
+	// the profile comes last
+	getString := func(key string) string {
+		if v, ok := d.GetOk(key); ok {
+			return v.(string)
+		}
+		return profile[key]
+	}
+
 	config := Config{
-		SecretId:  secretId.(string),
-		SecretKey: secretKey.(string),
-		Region:    region.(string),
+		SecretId:      getString("secret_id"),
+		SecretKey:     getString("secret_key"),
+		Region:        getString("region"),
+		SecurityToken: getString("security_token"),
+		DomainSuffix:  getString("domain_suffix"),
+		Endpoints:     expandEndpoints(d.Get("endpoints").([]interface{})),
+		MaxRetries:    d.Get("max_retries").(int),
+		RetryMaxDelay: time.Duration(d.Get("retry_max_delay").(int)) * time.Second,
+		RateLimits:    expandRateLimits(d.Get("rate_limit").([]interface{})),
+		DefaultTags:   expandDefaultTags(d.Get("default_tags").([]interface{})),
 	}
-	return config.Client()
+	if config.SecretId == "" || config.SecretKey == "" {
+		return nil, errors.New("secret_id and secret_key must be set in the provider block, env or shared credentials file")
+	}
+	if config.Region == "" {
+		config.Region = "ap-guangzhou"
+	}
+	if v, ok := d.GetOk("assume_role"); ok {
+		assumeRole := v.([]interface{})[0].(map[string]interface{})
+		config.AssumeRole = &AssumeRoleConfig{
+			RoleArn:         assumeRole["role_arn"].(string),
+			SessionName:     assumeRole["session_name"].(string),
+			SessionDuration: assumeRole["session_duration"].(int),
+			Policy:          assumeRole["policy"].(string),
+		}
+	}
+	return &config, nil
+}
+
+func endpointsSchema() *schema.Schema {
+	endpoints := make(map[string]*schema.Schema)
+	for _, service := range endpointServices {
+		endpoints[service] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Endpoint of " + service + ", e.g. " + service + ".api.qcloud.com or http://127.0.0.1:8080",
+		}
+	}
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: endpoints,
+		},
+	}
+}
+
+func expandEndpoints(list []interface{}) map[string]string {
+	endpoints := make(map[string]string)
+	if len(list) == 0 || list[0] == nil {
+		return endpoints
+	}
+	m := list[0].(map[string]interface{})
+	for _, service := range endpointServices {
+		if v, ok := m[service].(string); ok && v != "" {
+			endpoints[service] = v
+		}
+	}
+	return endpoints
+}
+
+func expandDefaultTags(list []interface{}) map[string]string {
+	tags := make(map[string]string)
+	if len(list) == 0 || list[0] == nil {
+		return tags
+	}
+	m := list[0].(map[string]interface{})
+	for k, v := range m["tags"].(map[string]interface{}) {
+		tags[k] = v.(string)
+	}
+	return tags
+}
+
+func rateLimitSchema() *schema.Schema {
+	limits := make(map[string]*schema.Schema)
+	for _, module := range rateLimitModules {
+		limits[module] = &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      defaultRateLimit,
+			ValidateFunc: validateIntegerInRange(0, 10000),
+			Description:  "Requests per second allowed to " + module + ", 0 means no limit",
+		}
+	}
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: limits,
+		},
+	}
+}
+
+// expandRateLimits returns the limit of every module, the default applies to
+// all of them when the block is absent.
+func expandRateLimits(list []interface{}) map[string]int {
+	limits := make(map[string]int)
+	for _, module := range rateLimitModules {
+		limits[module] = defaultRateLimit
+	}
+	if len(list) == 0 || list[0] == nil {
+		return limits
+	}
+	m := list[0].(map[string]interface{})
+	for _, module := range rateLimitModules {
+		if v, ok := m[module].(int); ok {
+			limits[module] = v
+		}
+	}
+	return limits
 }
@@ -0,0 +1,104 @@
+package tencentcloud
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultRateLimit is the number of requests per second allowed to a module
+// when it is not configured in the rate_limit block.
+const defaultRateLimit = 20
+
+// rateLimitModules are the modules with their own token bucket.
+var rateLimitModules = []string{
+	"cvm",
+	"vpc",
+	"cbs",
+	"dfw",
+	"ccs",
+	"lb",
+	"tag",
+	"clb",
+}
+
+// rateLimitAliases maps the services which have their own domain to the
+// module whose rate limit they count against.
+var rateLimitAliases = map[string]string{
+	"eip":      "cvm",
+	"image":    "cvm",
+	"snapshot": "cbs",
+}
+
+// rateLimiter holds a token bucket per module, it is shared by the clients of
+// all regions and implements common.Limiter.
+type rateLimiter struct {
+	buckets map[string]*tokenBucket
+}
+
+// newRateLimiter creates a limiter with the requests per second of each
+// module, a module with a non positive limit is not limited.
+func newRateLimiter(limits map[string]int) *rateLimiter {
+	limiter := &rateLimiter{
+		buckets: make(map[string]*tokenBucket),
+	}
+	for module, limit := range limits {
+		if limit > 0 {
+			limiter.buckets[module] = newTokenBucket(limit)
+		}
+	}
+	return limiter
+}
+
+func (limiter *rateLimiter) Wait(service string) {
+	if module, ok := rateLimitAliases[service]; ok {
+		service = module
+	}
+	if bucket, ok := limiter.buckets[service]; ok {
+		bucket.Wait()
+	}
+}
+
+// tokenBucket allows rate requests per second on average, and bursts of up to
+// rate requests after a quiet period.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+
+	// now and sleep are time.Now and time.Sleep, replaced in tests
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+func newTokenBucket(rate int) *tokenBucket {
+	return &tokenBucket{
+		rate:   float64(rate),
+		tokens: float64(rate),
+		last:   time.Now(),
+		now:    time.Now,
+		sleep:  time.Sleep,
+	}
+}
+
+// Wait takes a token, and blocks until the token is due if the bucket is
+// empty. Tokens are handed out in the order of the calls.
+func (b *tokenBucket) Wait() {
+	b.mu.Lock()
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+	b.last = now
+	b.tokens--
+	var wait time.Duration
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if wait > 0 {
+		b.sleep(wait)
+	}
+}
@@ -0,0 +1,72 @@
+package tencentcloud
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock stands in for the clock of the token buckets, sleeping records
+// the wait without advancing the clock, so each call of Wait reports the wait
+// of its own token.
+type fakeClock struct {
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *fakeClock) install(limiter *rateLimiter) {
+	for _, bucket := range limiter.buckets {
+		bucket.last = c.now
+		bucket.now = func() time.Time { return c.now }
+		bucket.sleep = func(d time.Duration) { c.waits = append(c.waits, d) }
+	}
+}
+
+// immediate returns the number of calls of f which got a token at once.
+func (c *fakeClock) immediate(n int, f func()) int {
+	c.waits = nil
+	for i := 0; i < n; i++ {
+		f()
+	}
+	return n - len(c.waits)
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(map[string]int{"cvm": 20, "vpc": 0})
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	clock.install(limiter)
+
+	// the burst is served at once, the rest at the rate of the module, and
+	// eip counts against cvm
+	i := 0
+	wait := func() {
+		if i++; i%2 == 0 {
+			limiter.Wait("eip")
+		} else {
+			limiter.Wait("cvm")
+		}
+	}
+	if n := clock.immediate(30, wait); n != 20 {
+		t.Fatalf("expect a burst of 20 requests, got %v", n)
+	}
+	if len(clock.waits) != 10 || clock.waits[9] != 500*time.Millisecond {
+		t.Fatalf("expect the 30th request to wait 500ms at 20 per second, got waits %v", clock.waits)
+	}
+
+	// the tokens are refilled at the rate, up to the burst
+	clock.now = clock.now.Add(time.Second)
+	if n := clock.immediate(20, wait); n != 10 {
+		t.Fatalf("expect 10 tokens after a second of debt of 10, got %v", n)
+	}
+	clock.now = clock.now.Add(time.Hour)
+	if n := clock.immediate(30, wait); n != 20 {
+		t.Fatalf("expect the burst to be capped at 20, got %v", n)
+	}
+
+	// modules without a positive limit are not limited
+	if _, ok := limiter.buckets["vpc"]; ok {
+		t.Fatalf("expect vpc not to be limited")
+	}
+	if n := clock.immediate(100, func() { limiter.Wait("vpc"); limiter.Wait("dfw") }); n != 100 {
+		t.Fatalf("expect unlimited modules not to wait, %v of 100 requests waited", 100-n)
+	}
+}
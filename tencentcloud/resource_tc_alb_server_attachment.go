@@ -22,6 +22,9 @@ func resourceTencentCloudAlbServerAttachment() *schema.Resource {
 		Read:   resourceTencentCloudAlbServerAttachmentRead,
 		Delete: resourceTencentCloudAlbServerAttachmentDelete,
 		Update: resourceTencentCloudAlbServerAttachmentUpdate,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"loadbalancer_id": {
@@ -230,20 +233,33 @@ func resourceTencentCloudAlbServerAttachmentUpdate(d *schema.ResourceData, m int
 func resourceTencentCloudAlbServerAttachmentRead(d *schema.ResourceData, m interface{}) error {
 	client := m.(*TencentCloudClient).lbConn
 
+	loadBalancerId, listenerId, locationId, err := parseAlbServerAttachmentId(d.Id())
+	if err != nil {
+		return err
+	}
 	req := lb.NewDescribeForwardLBBackendsRequest()
-	req.LoadBalancerId = common.StringPtr(d.Get("loadbalancer_id").(string))
-	req.ListenerIds = common.StringPtrs([]string{d.Get("listener_id").(string)})
+	req.LoadBalancerId = common.StringPtr(loadBalancerId)
+	req.ListenerIds = common.StringPtrs([]string{listenerId})
 	resp, err := client.DescribeForwardLBBackends(req)
 	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] alb server attachment %v not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 	if len(resp.Data) == 0 {
+		log.Printf("[WARN] alb server attachment %v not found, removing from state", d.Id())
 		d.SetId("")
-		return errors.New("resource not exist")
+		return nil
 	}
+	d.Set("loadbalancer_id", loadBalancerId)
+	d.Set("listener_id", listenerId)
+	d.Set("location_id", locationId)
 	d.Set("protocol_type", *resp.Data[0].ProtocolType)
 	var dataSet []map[string]interface{}
-	locid := d.Get("location_id").(string)
+	locid := locationId
 	makemap := func(instanceId string, port, weight int) map[string]interface{} {
 		m := make(map[string]interface{})
 		m["instance_id"] = instanceId
@@ -279,6 +295,17 @@ func resourceTencentCloudAlbServerAttachmentRead(d *schema.ResourceData, m inter
 	return nil
 }
 
+// attachment id is in a format like: lb-m5vh60me:lbl-ojhtwo3k:loc-8mb5b2eq,
+// the location id is empty for a layer 4 listener.
+func parseAlbServerAttachmentId(id string) (loadBalancerId, listenerId, locationId string, err error) {
+	ids := strings.Split(id, ":")
+	if len(ids) != 3 || ids[0] == "" || ids[1] == "" {
+		err = fmt.Errorf("Invalid alb server attachment ID: %v", id)
+		return
+	}
+	return ids[0], ids[1], ids[2], nil
+}
+
 func lbRequestStatusCheck(m interface{}, requestId *int) error {
 	client := m.(*TencentCloudClient).lbConn
 
@@ -37,6 +37,14 @@ func resourceTencentCloudCbsSnapshot() *schema.Resource {
 		Read:   resourceTencentCloudCbsSnapshotRead,
 		Update: resourceTencentCloudCbsSnapshotUpdate,
 		Delete: resourceTencentCloudCbsSnapshotDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"snapshot_name": &schema.Schema{
@@ -144,50 +152,49 @@ func deleteSnapshot(snapshotId string, client *client.Client) *resource.RetryErr
 	} else {
 		return resource.NonRetryableError(fmt.Errorf("DeleteSnapshot failed, inner code:%v, message: %v", code, msg))
 	}
-	return nil
 }
 
-func waitingSnapshotReady(snapshotId string, client *client.Client) error {
-	return resource.Retry(5*time.Minute, func() *resource.RetryError {
-		snapshotInfo, _, err := describeSnapshot(snapshotId, client)
-		if err != nil {
-			return resource.NonRetryableError(err)
-		}
-		if snapshotInfo.SnapshotStatus == "creating" {
-			return resource.RetryableError(fmt.Errorf("waiting snapshot ready"))
-		}
-		return nil
-	})
+func waitingSnapshotReady(snapshotId string, client *client.Client, timeout time.Duration) error {
+	w := &waiter{
+		Name:    "snapshot " + snapshotId,
+		Pending: []string{"creating"},
+		Target:  []string{"normal"},
+		Refresh: func() (interface{}, string, error) {
+			snapshot, _, err := describeSnapshot(snapshotId, client)
+			if err != nil {
+				if err == errSnapshotNotFound {
+					return nil, "", nil
+				}
+				return nil, "", err
+			}
+			return snapshot, snapshot.SnapshotStatus, nil
+		},
+		Timeout: timeout,
+	}
+	_, err := w.Wait()
+	return err
 }
 
 func describeSnapshot(snapshotId string, client *client.Client) (*snapshotInfo, bool, error) {
 	var jsonresp struct {
-		Code        int
-		Message     string
-		CodeDesc    string
 		SnapshotSet []snapshotInfo
 	}
 	params := map[string]string{
 		"Action":        "DescribeSnapshots",
 		"snapshotIds.0": snapshotId,
 	}
-	response, err := client.SendRequest("snapshot", params)
+	response, err := sendRequest(client, "snapshot", params)
 	canRetryError := false
 	if err != nil {
-		return nil, canRetryError, err
+		if isNotFound(err) {
+			return nil, true, errSnapshotNotFound
+		}
+		return nil, isRetryable(err), err
 	}
 	err = json.Unmarshal([]byte(response), &jsonresp)
 	if err != nil {
 		return nil, canRetryError, err
 	}
-	if jsonresp.Code != 0 {
-		return nil, canRetryError, fmt.Errorf(
-			"DescribeSnapshots error, code:%v, message: %v, codeDesc: %v.",
-			jsonresp.Code,
-			jsonresp.Message,
-			jsonresp.CodeDesc,
-		)
-	}
 
 	if len(jsonresp.SnapshotSet) == 0 {
 		canRetryError = true
@@ -231,6 +238,9 @@ func resourceTencentCloudCbsSnapshotCreate(d *schema.ResourceData, m interface{}
 		)
 	}
 	d.SetId(jsonresp.SnapshotId)
+	if err := waitingSnapshotReady(d.Id(), client, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
 	return resourceTencentCloudCbsSnapshotRead(d, m)
 }
 
@@ -238,13 +248,14 @@ func resourceTencentCloudCbsSnapshotRead(d *schema.ResourceData, m interface{})
 	snapshot, _, err := describeSnapshot(d.Id(), m.(*TencentCloudClient).commonConn)
 	if err != nil {
 		if err == errSnapshotNotFound {
+			log.Printf("[WARN] cbs snapshot %v not found, removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
 		return err
 	}
 	d.Set("disk_type", snapshot.DiskType)
-	d.Set("percent", snapshot.Percent)
+	d.Set("pecent", snapshot.Percent)
 	d.Set("storage_size", snapshot.StorageSize)
 	d.Set("storage_id", snapshot.StorageId)
 	d.Set("snapshot_name", snapshot.SnapshotName)
@@ -269,7 +280,7 @@ func resourceTencentCloudCbsSnapshotUpdate(d *schema.ResourceData, m interface{}
 }
 
 func resourceTencentCloudCbsSnapshotDelete(d *schema.ResourceData, m interface{}) error {
-	err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+	err := resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		return deleteSnapshot(d.Id(), m.(*TencentCloudClient).commonConn)
 	})
 	d.SetId("")
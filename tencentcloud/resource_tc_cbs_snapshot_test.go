@@ -19,6 +19,11 @@ func TestAccTencentCloudCbsSnapshot_basic(t *testing.T) {
 					testAccCheckSnapshotExists("tencentcloud_cbs_snapshot.test"),
 				),
 			},
+			{
+				ResourceName:      "tencentcloud_cbs_snapshot.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
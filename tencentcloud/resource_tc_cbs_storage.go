@@ -63,6 +63,14 @@ func resourceTencentCloudCbsStorage() *schema.Resource {
 		Read:   resourceTencentCloudCbsStorageRead,
 		Update: resourceTencentCloudCbsStorageUpdate,
 		Delete: resourceTencentCloudCbsStorageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(3 * time.Minute),
+			Update: schema.DefaultTimeout(3 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"storage_type": &schema.Schema{
@@ -100,6 +108,7 @@ func resourceTencentCloudCbsStorage() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"tags": tagsSchema(),
 		},
 	}
 }
@@ -209,32 +218,24 @@ func terminateCbsStorage(storageId string, client *client.Client) *resource.Retr
 
 func describeCbsStorage(storageId string, client *client.Client) (*storageInfo, bool, error) {
 	var jsonresp struct {
-		Code       int    `json:"code"`
-		Message    string `json:"message"`
-		CodeDesc   string `json:"codeDesc"`
 		StorageSet []storageInfo
 	}
 	params := map[string]string{
 		"Action":       "DescribeCbsStorages",
 		"storageIds.0": storageId,
 	}
-	response, err := client.SendRequest("cbs", params)
+	response, err := sendRequest(client, "cbs", params)
 	canRetryError := false
 	if err != nil {
-		return nil, canRetryError, err
+		if isNotFound(err) {
+			return nil, true, errStorageNotFound
+		}
+		return nil, isRetryable(err), err
 	}
 	err = json.Unmarshal([]byte(response), &jsonresp)
 	if err != nil {
 		return nil, canRetryError, err
 	}
-	if jsonresp.Code != 0 {
-		return nil, canRetryError, fmt.Errorf(
-			"DescribeCbsStorages error, code:%v, message: %v, codeDesc: %v.",
-			jsonresp.Code,
-			jsonresp.Message,
-			jsonresp.CodeDesc,
-		)
-	}
 
 	if len(jsonresp.StorageSet) == 0 {
 		canRetryError = true
@@ -319,19 +320,26 @@ func resourceTencentCloudCbsStorageCreate(d *schema.ResourceData, m interface{})
 	}
 	storageId := jsonresp.StorageIds[0]
 	d.SetId(storageId)
-	time.Sleep(time.Second * 3)
-	resource.Retry(3*time.Minute, func() *resource.RetryError {
-		_, canRetryError, err := describeCbsStorage(d.Id(), m.(*TencentCloudClient).commonConn)
-		if err != nil {
-			if canRetryError == false {
-				return resource.NonRetryableError(err)
-			} else {
-				return resource.RetryableError(fmt.Errorf("Storage is creating..."))
+	// a new storage is not listed at once
+	w := &waiter{
+		Name:   "cbs storage " + storageId,
+		Target: []string{"normal"},
+		Refresh: func() (interface{}, string, error) {
+			storage, _, err := describeCbsStorage(storageId, m.(*TencentCloudClient).commonConn)
+			if err != nil {
+				if err == errStorageNotFound {
+					return nil, "", nil
+				}
+				return nil, "", err
 			}
-		}
-
-		return nil
-	})
+			return storage, storage.StorageStatus, nil
+		},
+		Timeout: d.Timeout(schema.TimeoutCreate),
+		Delay:   3 * time.Second,
+	}
+	if _, err := w.Wait(); err != nil {
+		return err
+	}
 	log.Printf("[DEBUG] CreateCbsStorages success - storageId: %#v.", storageId)
 	//TODO 由于CreateCbsStorages接口不支持创建时设置云盘名称，所以在创建完后需设置云盘名称
 	if storageName, ok := d.GetOk("storage_name"); ok {
@@ -340,6 +348,9 @@ func resourceTencentCloudCbsStorageCreate(d *schema.ResourceData, m interface{})
 			return err
 		}
 	}
+	if err := updateResourceTags(m.(*TencentCloudClient), d, "cvm", "volume"); err != nil {
+		return err
+	}
 	return resourceTencentCloudCbsStorageRead(d, m)
 }
 
@@ -347,6 +358,7 @@ func resourceTencentCloudCbsStorageRead(d *schema.ResourceData, m interface{}) e
 	storage, _, err := describeCbsStorage(d.Id(), m.(*TencentCloudClient).commonConn)
 	if err != nil {
 		if err == errStorageNotFound {
+			log.Printf("[WARN] cbs storage %v not found, removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
@@ -357,13 +369,17 @@ func resourceTencentCloudCbsStorageRead(d *schema.ResourceData, m interface{}) e
 	d.Set("availability_zone", storage.Zone)
 	d.Set("storage_name", storage.StorageName)
 	d.Set("storage_status", storage.StorageStatus)
-	d.Set("attached", storage.StorageStatus)
-	return nil
+	d.Set("attached", storage.Attached)
+	return readResourceTags(m.(*TencentCloudClient), d, "cvm", "volume")
 }
 
 func resourceTencentCloudCbsStorageUpdate(d *schema.ResourceData, m interface{}) error {
 	immutableItems := [...]string{"storage_size", "storage_type", "availability_zone", "period"}
 	for _, item := range immutableItems {
+		// period is not returned by the API, an imported storage has none
+		if o, _ := d.GetChange(item); item == "period" && o.(int) == 0 {
+			continue
+		}
 		if d.HasChange(item) {
 			return fmt.Errorf("[ERROR] %v does not support modification, please create a new disk instead.", item)
 		}
@@ -384,6 +400,14 @@ func resourceTencentCloudCbsStorageUpdate(d *schema.ResourceData, m interface{})
 		requestUpdate = true
 	}
 
+	if d.HasChange("tags") {
+		err := updateResourceTags(m.(*TencentCloudClient), d, "cvm", "volume")
+		if err != nil {
+			return err
+		}
+		requestUpdate = true
+	}
+
 	if d.HasChange("snapshot_id") {
 		_, snapshotIdInf := d.GetChange("snapshot_id")
 		snapshotId := snapshotIdInf.(string)
@@ -403,7 +427,7 @@ func resourceTencentCloudCbsStorageUpdate(d *schema.ResourceData, m interface{})
 }
 
 func resourceTencentCloudCbsStorageDelete(d *schema.ResourceData, m interface{}) error {
-	err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+	err := resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		return terminateCbsStorage(d.Id(), m.(*TencentCloudClient).commonConn)
 	})
 	d.SetId("")
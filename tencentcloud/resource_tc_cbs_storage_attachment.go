@@ -14,6 +14,9 @@ func resourceTencentCloudCbsStorageAttachment() *schema.Resource {
 		Create: resourceTencentCloudCbsStorageAttachmentCreate,
 		Read:   resourceTencentCloudCbsStorageAttachmentRead,
 		Delete: resourceTencentCloudCbsStorageAttachmentDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"storage_id": &schema.Schema{
@@ -82,13 +85,16 @@ func resourceTencentCloudCbsStorageAttachmentRead(d *schema.ResourceData, m inte
 		"storageIds.0": storageId,
 	}
 
-	response, err := client.SendRequest("cbs", params)
+	response, err := sendRequest(client, "cbs", params)
 	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] cbs storage %v not found, removing attachment from state", storageId)
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 	var jsonresp struct {
-		Code       int    `json:"code"`
-		Message    string `json:"message"`
 		StorageSet []struct {
 			Attached      int    `json:"attached"`
 			StorageStatus string `json:"storageStatus"`
@@ -99,11 +105,9 @@ func resourceTencentCloudCbsStorageAttachmentRead(d *schema.ResourceData, m inte
 	if err != nil {
 		return err
 	}
-	if jsonresp.Code != 0 {
-		return fmt.Errorf("[ERROR] code=%v msg=%s", jsonresp.Code, jsonresp.Message)
-	}
 	// storage no longer exists
 	if len(jsonresp.StorageSet) != 1 {
+		log.Printf("[WARN] cbs storage %v not found, removing attachment from state", storageId)
 		d.SetId("")
 		return nil
 	}
@@ -114,12 +118,16 @@ func resourceTencentCloudCbsStorageAttachmentRead(d *schema.ResourceData, m inte
 	if attached != 1 && status != "attaching" {
 		log.Printf("[DEBUG] storage=%v status=%v", storageId, status)
 		d.SetId("")
+		return nil
 	}
 	// storage has been attached but belongs to another instance
 	if attached == 1 && uInstanceId != instanceId {
 		log.Printf("[DEBUG] storage=%s has been attached to instance=%s", storageId, uInstanceId)
 		d.SetId("")
+		return nil
 	}
+	d.Set("storage_id", storageId)
+	d.Set("instance_id", instanceId)
 	return nil
 }
 
@@ -170,10 +178,10 @@ func resourceTencentCloudCbsStorageAttachmentDelete(d *schema.ResourceData, m in
 		}
 	}
 
-	if _, err := waitInstanceReachTargetStatus(client, []string{instanceId}, "PENDING"); err != nil {
+	if _, err := waitInstanceReachTargetStatus(m.(*TencentCloudClient).cvmConn, []string{instanceId}, "PENDING", defaultInstanceOperationTimeout); err != nil {
 		return err
 	}
-	if _, err := waitInstanceReachOneOfTargetStatusList(client, []string{instanceId}, []string{"RUNNING", "STOPPED"}); err != nil {
+	if _, err := waitInstanceReachOneOfTargetStatusList(m.(*TencentCloudClient).cvmConn, []string{instanceId}, []string{"RUNNING", "STOPPED"}, defaultInstanceOperationTimeout); err != nil {
 		return err
 	}
 
@@ -19,6 +19,11 @@ func TestAccTencentCloudCbsStorageAttachment_basic(t *testing.T) {
 					testAccCheckCbsStorageAttached("tencentcloud_cbs_storage.my_storage", "tencentcloud_instance.my_instance"),
 				),
 			},
+			{
+				ResourceName:      "tencentcloud_cbs_storage_attachment.my_attachment",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -50,10 +55,10 @@ func testAccCheckCbsStorageAttached(storageTag string, instanceTag string) resou
 		}
 		client := provider.Meta().(*TencentCloudClient).commonConn
 
-		if _, err := waitInstanceReachTargetStatus(client, []string{insRs.Primary.ID}, "PENDING"); err != nil {
+		if _, err := waitInstanceReachTargetStatus(provider.Meta().(*TencentCloudClient).cvmConn, []string{insRs.Primary.ID}, "PENDING", defaultInstanceOperationTimeout); err != nil {
 			return err
 		}
-		if _, err := waitInstanceReachOneOfTargetStatusList(client, []string{insRs.Primary.ID}, []string{"RUNNING", "STOPPED"}); err != nil {
+		if _, err := waitInstanceReachOneOfTargetStatusList(provider.Meta().(*TencentCloudClient).cvmConn, []string{insRs.Primary.ID}, []string{"RUNNING", "STOPPED"}, defaultInstanceOperationTimeout); err != nil {
 			return err
 		}
 
@@ -27,6 +27,46 @@ func TestAccTencentCloudCbsStorage_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("tencentcloud_cbs_storage.my_storage", "storage_name", "testAccCbsStorageTest-2"),
 				),
 			},
+			{
+				ResourceName:            "tencentcloud_cbs_storage.my_storage",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"period"},
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudCbsStorage_basic(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "disk", "tencentcloud_cbs_storage"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testAccCbsStorageConfig),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "disk", "tencentcloud_cbs_storage.my_storage"),
+					resource.TestCheckResourceAttr("tencentcloud_cbs_storage.my_storage", "storage_name", "testAccCbsStorageTest"),
+					resource.TestCheckResourceAttr("tencentcloud_cbs_storage.my_storage", "storage_size", "10"),
+				),
+			},
+			{
+				Config: m.Config(testAccCbsStorageConfigChanged),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "disk", "tencentcloud_cbs_storage.my_storage"),
+					resource.TestCheckResourceAttr("tencentcloud_cbs_storage.my_storage", "storage_name", "testAccCbsStorageTest-2"),
+				),
+			},
+			{
+				Config:                  m.Config(testAccCbsStorageConfigChanged),
+				ResourceName:            "tencentcloud_cbs_storage.my_storage",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"period"},
+			},
 		},
 	})
 }
@@ -2,6 +2,7 @@ package tencentcloud
 
 import (
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
@@ -21,6 +22,14 @@ func resourceTencentCloudContainerCluster() *schema.Resource {
 		Read:   resourceTencentCloudContainerClusterRead,
 		Update: resourceTencentCloudContainerClusterUpdate,
 		Delete: resourceTencentCloudContainerClusterDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+			Update: schema.DefaultTimeout(15 * time.Minute),
+			Delete: schema.DefaultTimeout(15 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"cluster_name": &schema.Schema{
@@ -82,10 +91,12 @@ func resourceTencentCloudContainerCluster() *schema.Resource {
 			"vpc_id": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"cluster_cidr": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"cluster_desc": &schema.Schema{
 				Type:     schema.TypeString,
@@ -147,12 +158,22 @@ func resourceTencentCloudContainerCluster() *schema.Resource {
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
+			"tags": tagsSchema(),
 		},
 	}
 }
 
 func resourceTencentCloudContainerClusterUpdate(d *schema.ResourceData, m interface{}) error {
-	return fmt.Errorf("the container cluster resource doesn't support update")
+	// the arguments of the nodes are unknown after import, take them as is
+	attrs := resourceTencentCloudContainerCluster().Schema
+	delete(attrs, "tags")
+	if !changesFillImported(d, attrs) {
+		return fmt.Errorf("the container cluster resource doesn't support update except tags")
+	}
+	if d.HasChange("tags") {
+		return updateResourceTags(m.(*TencentCloudClient), d, "ccs", "cluster")
+	}
+	return nil
 }
 
 func resourceTencentCloudContainerClusterCreate(d *schema.ResourceData, m interface{}) error {
@@ -346,35 +367,65 @@ func resourceTencentCloudContainerClusterCreate(d *schema.ResourceData, m interf
 
 	d.SetId(clusterInstanceId)
 
-	if err := waitClusterStatusReady(client, clusterInstanceId); err != nil {
-		return fmt.Errorf("tencentcloud_container_cluster cluster status error")
+	if err := waitClusterStatusReady(client, clusterInstanceId, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+
+	if err := updateResourceTags(m.(*TencentCloudClient), d, "ccs", "cluster"); err != nil {
+		return err
 	}
 
 	return resourceTencentCloudContainerClusterRead(d, m)
 }
 
-func waitClusterStatusReady(client *ccs.Client, id string) error {
+func waitClusterStatusReady(client *ccs.Client, id string, timeout time.Duration) error {
+	w := &waiter{
+		Name:    "cluster " + id,
+		Target:  []string{CLUSTER_LIFESTATE_RUNNING},
+		Refresh: clusterStatusRefreshFunc(client, id),
+		Timeout: timeout,
+	}
+	_, err := w.Wait()
+	return err
+}
+
+// waitClusterDeleted waits until the cluster is gone, its nodes are released
+// or removed from it in the meantime.
+func waitClusterDeleted(client *ccs.Client, id string, timeout time.Duration) error {
+	w := &waiter{
+		Name:    "cluster " + id,
+		Refresh: clusterStatusRefreshFunc(client, id),
+		Timeout: timeout,
+	}
+	_, err := w.Wait()
+	return err
+}
 
+func clusterStatusRefreshFunc(client *ccs.Client, id string) resource.StateRefreshFunc {
 	describeClusterReq := ccs.NewDescribeClusterRequest()
 	describeClusterReq.ClusterIds = []*string{&id}
 
-	err := resource.Retry(15*time.Minute, func() *resource.RetryError {
+	return func() (interface{}, string, error) {
 		response, err := client.DescribeCluster(describeClusterReq)
 		if err != nil {
-			return resource.NonRetryableError(err)
+			if isNotFound(err) {
+				return nil, "", nil
+			}
+			return nil, "", err
 		}
-		if len(response.Data.Clusters) != 1 {
-			return resource.NonRetryableError(fmt.Errorf("cluster create failed"))
+		if response.Code != nil && *response.Code == CLUSTER_NOT_FOUND_CODE {
+			return nil, "", nil
 		}
-		if response.Data.Clusters[0].Status == nil {
-			return resource.RetryableError(fmt.Errorf("status not found yet"))
+		if len(response.Data.Clusters) == 0 {
+			return nil, "", nil
 		}
-		if *response.Data.Clusters[0].Status == CLUSTER_LIFESTATE_RUNNING {
-			return nil
+		cluster := response.Data.Clusters[0]
+		// the status is not set for a while after the cluster is created
+		if cluster.Status == nil {
+			return cluster, "", nil
 		}
-		return resource.RetryableError(fmt.Errorf("cluster still creating...will retry"))
-	})
-	return err
+		return cluster, *cluster.Status, nil
+	}
 }
 
 // Read Cluster Info
@@ -389,6 +440,11 @@ func resourceTencentCloudContainerClusterRead(d *schema.ResourceData, m interfac
 
 	clusterResponse, err := client.DescribeCluster(describeClusterReq)
 	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] container cluster %v not found, removing from state", clusterInstanceId)
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -433,10 +489,12 @@ func resourceTencentCloudContainerClusterRead(d *schema.ResourceData, m interfac
 			d.Set("cluster_cidr", *clusterResponse.Data.Clusters[0].ClusterCIDR)
 		}
 	} else {
+		log.Printf("[WARN] container cluster %v not found, removing from state", clusterInstanceId)
 		d.SetId("")
+		return nil
 	}
 
-	return nil
+	return readResourceTags(m.(*TencentCloudClient), d, "ccs", "cluster")
 }
 
 func resourceTencentCloudContainerClusterDelete(d *schema.ResourceData, m interface{}) error {
@@ -454,6 +512,10 @@ func resourceTencentCloudContainerClusterDelete(d *schema.ResourceData, m interf
 	response, err := client.DeleteCluster(deleteClusterReq)
 
 	if err != nil {
+		// resource not existed, return done
+		if isNotFound(err) {
+			return nil
+		}
 		return err
 	}
 
@@ -474,5 +536,5 @@ func resourceTencentCloudContainerClusterDelete(d *schema.ResourceData, m interf
 		)
 	}
 
-	return nil
+	return waitClusterDeleted(client, clusterInstanceId, d.Timeout(schema.TimeoutDelete))
 }
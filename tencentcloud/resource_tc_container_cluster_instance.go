@@ -2,12 +2,16 @@ package tencentcloud
 
 import (
 	"fmt"
+	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/zqfan/tencentcloud-sdk-go/common"
 	ccs "github.com/zqfan/tencentcloud-sdk-go/services/ccs/unversioned"
+	cvm "github.com/zqfan/tencentcloud-sdk-go/services/cvm/v20170312"
 )
 
 const (
@@ -20,11 +24,20 @@ func resourceTencentCloudContainerClusterInstance() *schema.Resource {
 		Read:   resourceTencentCloudContainerClusterInstancesRead,
 		Update: resourceTencentCloudContainerClusterInstancesUpdate,
 		Delete: resourceTencentCloudContainerClusterInstancesDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceTencentCloudContainerClusterInstancesImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+			Update: schema.DefaultTimeout(15 * time.Minute),
+			Delete: schema.DefaultTimeout(15 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"cluster_id": {
 				Type:     schema.TypeString,
 				Required: true,
+				ForceNew: true,
 			},
 			"cpu": &schema.Schema{
 				Type:     schema.TypeInt,
@@ -45,6 +58,7 @@ func resourceTencentCloudContainerClusterInstance() *schema.Resource {
 			"require_wan_ip": &schema.Schema{
 				Type:     schema.TypeInt,
 				Optional: true,
+				Computed: true,
 			},
 			"subnet_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -73,6 +87,7 @@ func resourceTencentCloudContainerClusterInstance() *schema.Resource {
 			"cvm_type": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
 			},
 			"period": &schema.Schema{
 				Type:     schema.TypeInt,
@@ -89,6 +104,7 @@ func resourceTencentCloudContainerClusterInstance() *schema.Resource {
 			"sg_id": &schema.Schema{
 				Type:     schema.TypeString,
 				Optional: true,
+				Computed: true,
 			},
 			"mount_target": &schema.Schema{
 				Type:     schema.TypeString,
@@ -136,6 +152,11 @@ func resourceTencentCloudContainerClusterInstancesRead(d *schema.ResourceData, m
 
 	response, err := client.DescribeClusterInstances(describeClusterInstancesReq)
 	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] container cluster instance %v not found, removing from state", instanceId)
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
@@ -164,6 +185,9 @@ func resourceTencentCloudContainerClusterInstancesRead(d *schema.ResourceData, m
 			if node.InstanceId != nil {
 				d.Set("instance_id", *node.InstanceId)
 			}
+			if node.ZoneId != nil {
+				d.Set("zone_id", strconv.Itoa(*node.ZoneId))
+			}
 			if node.IsNormal != nil {
 				d.Set("is_normal", *node.IsNormal)
 			}
@@ -177,16 +201,112 @@ func resourceTencentCloudContainerClusterInstancesRead(d *schema.ResourceData, m
 	}
 
 	if found == false {
+		log.Printf("[WARN] container cluster instance %v not found, removing from state", instanceId)
 		d.SetId("")
+		return nil
 	}
 
+	// the node is a CVM instance, the arguments it is created with are read from there
+	instance, err := describeInstanceById(m.(*TencentCloudClient).cvmConn, instanceId)
+	if err != nil {
+		if err == errInstanceNotFound {
+			log.Printf("[WARN] container cluster instance %v is not a CVM instance yet", instanceId)
+			return nil
+		}
+		return err
+	}
+	setClusterInstanceCvmAttributes(d, instance)
+
 	return nil
 }
 
 func resourceTencentCloudContainerClusterInstancesUpdate(d *schema.ResourceData, m interface{}) error {
+	// the arguments of the node which are not returned by the API are unknown
+	// after import, take them as is
+	if changesFillImported(d, resourceTencentCloudContainerClusterInstance().Schema) {
+		return nil
+	}
 	return fmt.Errorf("the container cluster instances resource doesn't support update")
 }
 
+// clusterInstanceBandwidthTypes maps the internet charge types of CVM to the
+// bandwidth types of the container service.
+var clusterInstanceBandwidthTypes = map[string]string{
+	"BANDWIDTH_POSTPAID_BY_HOUR": "PayByHour",
+	"TRAFFIC_POSTPAID_BY_HOUR":   "PayByTraffic",
+}
+
+// clusterInstanceCvmTypes maps the instance charge types of CVM to the cvm
+// types of the container service.
+var clusterInstanceCvmTypes = map[string]string{
+	"POSTPAID_BY_HOUR": "PayByHour",
+	"PREPAID":          "PayByMonth",
+}
+
+// setClusterInstanceCvmAttributes sets the arguments of a cluster node which
+// are kept by its CVM instance. The password, the period, the instance type
+// family and the mount options are not returned by any API.
+func setClusterInstanceCvmAttributes(d *schema.ResourceData, instance *cvm.Instance) {
+	if instance.InternetAccessible != nil {
+		if instance.InternetAccessible.InternetMaxBandwidthOut != nil {
+			d.Set("bandwidth", *instance.InternetAccessible.InternetMaxBandwidthOut)
+		}
+		if instance.InternetAccessible.InternetChargeType != nil {
+			if t, ok := clusterInstanceBandwidthTypes[*instance.InternetAccessible.InternetChargeType]; ok {
+				d.Set("bandwidth_type", t)
+			}
+		}
+	}
+	requireWanIp := 0
+	if len(instance.PublicIpAddresses) > 0 {
+		requireWanIp = 1
+	}
+	d.Set("require_wan_ip", requireWanIp)
+	if instance.VirtualPrivateCloud != nil {
+		if instance.VirtualPrivateCloud.SubnetId != nil {
+			d.Set("subnet_id", *instance.VirtualPrivateCloud.SubnetId)
+		}
+		isVpcGateway := 0
+		if instance.VirtualPrivateCloud.AsVpcGateway != nil && *instance.VirtualPrivateCloud.AsVpcGateway {
+			isVpcGateway = 1
+		}
+		d.Set("is_vpc_gateway", isVpcGateway)
+	}
+	if instance.SystemDisk != nil && instance.SystemDisk.DiskSize != nil {
+		d.Set("root_size", *instance.SystemDisk.DiskSize)
+	}
+	storageSize := 0
+	if len(instance.DataDisks) > 0 && instance.DataDisks[0].DiskSize != nil {
+		storageSize = *instance.DataDisks[0].DiskSize
+	}
+	d.Set("storage_size", storageSize)
+	keyId := ""
+	if instance.LoginSettings != nil && len(instance.LoginSettings.KeyIds) > 0 {
+		keyId = *instance.LoginSettings.KeyIds[0]
+	}
+	d.Set("key_id", keyId)
+	if len(instance.SecurityGroupIds) > 0 {
+		d.Set("sg_id", *instance.SecurityGroupIds[0])
+	}
+	if instance.InstanceChargeType != nil {
+		if t, ok := clusterInstanceCvmTypes[*instance.InstanceChargeType]; ok {
+			d.Set("cvm_type", t)
+		}
+	}
+}
+
+// The instance is imported by an id like cls-xxxxxxxx:ins-xxxxxxxx since it
+// can not be found without its cluster.
+func resourceTencentCloudContainerClusterInstancesImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	ids := strings.Split(d.Id(), ":")
+	if len(ids) != 2 || ids[0] == "" || ids[1] == "" {
+		return nil, fmt.Errorf("Invalid container cluster instance ID: %v, expected {cluster_id}:{instance_id}", d.Id())
+	}
+	d.Set("cluster_id", ids[0])
+	d.SetId(ids[1])
+	return []*schema.ResourceData{d}, nil
+}
+
 // CreateClusterNode one node per time
 func resourceTencentCloudContainerClusterInstancesCreate(d *schema.ResourceData, m interface{}) error {
 	client := m.(*TencentCloudClient).ccsConn
@@ -332,40 +452,72 @@ func resourceTencentCloudContainerClusterInstancesCreate(d *schema.ResourceData,
 	nodeId := response.Data.InstanceIds[0]
 	d.SetId(*nodeId)
 
-	if err := waitClusterInstanceRunning(client, clusterId, *nodeId); err != nil {
-		return fmt.Errorf("Cluster Instance %s is abnormal, create fail", nodeId)
+	if err := waitClusterInstanceRunning(client, clusterId, *nodeId, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
 	}
 
 	return resourceTencentCloudContainerClusterInstancesRead(d, m)
 }
 
-func waitClusterInstanceRunning(conn *ccs.Client, clusterId, nodeId string) error {
+const (
+	clusterInstanceStateNormal       = "NORMAL"
+	clusterInstanceStateAbnormal     = "ABNORMAL"
+	clusterInstanceStateInitializing = "INITIALIZING"
+)
+
+func waitClusterInstanceRunning(conn *ccs.Client, clusterId, nodeId string, timeout time.Duration) error {
+	w := &waiter{
+		Name:    fmt.Sprintf("cluster %v instance %v", clusterId, nodeId),
+		Pending: []string{clusterInstanceStateInitializing},
+		Target:  []string{clusterInstanceStateNormal},
+		Failed:  []string{clusterInstanceStateAbnormal},
+		Refresh: clusterInstanceStateRefreshFunc(conn, clusterId, nodeId),
+		Timeout: timeout,
+	}
+	_, err := w.Wait()
+	return err
+}
+
+func waitClusterInstanceDeleted(conn *ccs.Client, clusterId, nodeId string, timeout time.Duration) error {
+	w := &waiter{
+		Name:    fmt.Sprintf("cluster %v instance %v", clusterId, nodeId),
+		Refresh: clusterInstanceStateRefreshFunc(conn, clusterId, nodeId),
+		Timeout: timeout,
+	}
+	_, err := w.Wait()
+	return err
+}
+
+// clusterInstanceStateRefreshFunc maps IsNormal of a node to a state, 0 means
+// abnormal, 1 means normal and the others mean the node is being initialized.
+func clusterInstanceStateRefreshFunc(conn *ccs.Client, clusterId, nodeId string) resource.StateRefreshFunc {
 	req := ccs.NewDescribeClusterInstancesRequest()
 	req.ClusterId = &clusterId
-	err := resource.Retry(15*time.Minute, func() *resource.RetryError {
+
+	return func() (interface{}, string, error) {
 		resp, err := conn.DescribeClusterInstances(req)
 		if err != nil {
-			return resource.RetryableError(err)
-		}
-		if _, ok := err.(*common.APIError); ok {
-			return resource.NonRetryableError(err)
+			if isNotFound(err) {
+				return nil, "", nil
+			}
+			return nil, "", err
 		}
 		for _, node := range resp.Data.Nodes {
 			if *node.InstanceId != nodeId {
 				continue
 			}
-			if *node.IsNormal == 0 {
-				return resource.NonRetryableError(fmt.Errorf("Instance status = 0"))
+			switch {
+			case node.IsNormal == nil:
+				return node, clusterInstanceStateInitializing, nil
+			case *node.IsNormal == 0:
+				return node, clusterInstanceStateAbnormal, nil
+			case *node.IsNormal == 1:
+				return node, clusterInstanceStateNormal, nil
 			}
-			if *node.IsNormal == 1 {
-				return nil
-			}
-			return resource.RetryableError(fmt.Errorf("Instance status = %s", *node.IsNormal))
+			return node, clusterInstanceStateInitializing, nil
 		}
-		// not found
-		return nil
-	})
-	return err
+		return nil, "", nil
+	}
 }
 
 func resourceTencentCloudContainerClusterInstancesDelete(d *schema.ResourceData, m interface{}) error {
@@ -424,5 +576,5 @@ func resourceTencentCloudContainerClusterInstancesDelete(d *schema.ResourceData,
 		)
 	}
 
-	return nil
+	return waitClusterInstanceDeleted(client, d.Get("cluster_id").(string), nodeId, d.Timeout(schema.TimeoutDelete))
 }
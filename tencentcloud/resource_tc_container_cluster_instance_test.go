@@ -1,9 +1,11 @@
 package tencentcloud
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
 )
 
 func TestAccTencentCloudContainerClusterInstance_basic(t *testing.T) {
@@ -18,10 +20,34 @@ func TestAccTencentCloudContainerClusterInstance_basic(t *testing.T) {
 					checkContainerClusterInstancesAllNormal("tencentcloud_container_cluster.foo"),
 				),
 			},
+			{
+				ResourceName:      "tencentcloud_container_cluster_instance.bar_instance",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: testAccContainerClusterInstanceImportStateIdFunc("tencentcloud_container_cluster_instance.bar_instance"),
+				// these arguments are used only on create and not returned by any API
+				ImportStateVerifyIgnore: []string{
+					"password",
+					"period",
+					"instance_type",
+					"mount_target",
+					"docker_graph_path",
+				},
+			},
 		},
 	})
 }
 
+func testAccContainerClusterInstanceImportStateIdFunc(n string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", n)
+		}
+		return fmt.Sprintf("%s:%s", rs.Primary.Attributes["cluster_id"], rs.Primary.ID), nil
+	}
+}
+
 const testAccTencentCloudContainerClusterInstanceConfig_basic = `
 resource "tencentcloud_vpc" "my_vpc" {
   cidr_block = "10.6.0.0/16"
@@ -23,6 +23,36 @@ func TestAccTencentCloudContainerCluster_basic(t *testing.T) {
 					checkContainerClusterInstancesAllNormal("tencentcloud_container_cluster.foo"),
 				),
 			},
+			{
+				ResourceName:      "tencentcloud_container_cluster.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the arguments of the nodes are used only on create and not returned by the cluster
+				ImportStateVerifyIgnore: []string{
+					"cpu",
+					"mem",
+					"os_name",
+					"bandwidth",
+					"bandwidth_type",
+					"require_wan_ip",
+					"subnet_id",
+					"is_vpc_gateway",
+					"storage_size",
+					"root_size",
+					"goods_num",
+					"password",
+					"key_id",
+					"cvm_type",
+					"period",
+					"zone_id",
+					"instance_type",
+					"sg_id",
+					"mount_target",
+					"docker_graph_path",
+					"instance_name",
+					"cluster_version",
+				},
+			},
 		},
 	})
 }
@@ -31,6 +31,11 @@ func TestAccTencentCloudDnat_basic(t *testing.T) {
 					testAccCheckDnatExists("tencentcloud_dnat.dev_dnat", &dnatId),
 				),
 			},
+			{
+				ResourceName:      "tencentcloud_dnat.dev_dnat",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -2,9 +2,9 @@ package tencentcloud
 
 import (
 	"errors"
+	"log"
 	"time"
 
-	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/zqfan/tencentcloud-sdk-go/common"
 	cvm "github.com/zqfan/tencentcloud-sdk-go/services/cvm/v20170312"
@@ -22,12 +22,10 @@ const (
 )
 
 var (
-	errCreateEIPFailed   = errors.New("create eip failed")
-	errEIPStillUnbinding = errors.New("eip still unbinding")
-	errEIPStillCreating  = errors.New("eip still creating")
-	errEIPStillDeleting  = errors.New("eip still deleting")
-	errEIPNotUnbind      = errors.New("eip should be unbind")
-	errEIPInvalidName    = errors.New("eip name is invlid")
+	errCreateEIPFailed  = errors.New("create eip failed")
+	errEIPStillDeleting = errors.New("eip still deleting")
+	errEIPNotUnbind     = errors.New("eip should be unbind")
+	errEIPInvalidName   = errors.New("eip name is invlid")
 )
 
 func resourceTencentCloudEip() *schema.Resource {
@@ -57,6 +55,8 @@ func resourceTencentCloudEip() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+
+			"tags": tagsSchema(),
 		},
 	}
 }
@@ -89,6 +89,9 @@ func resourceTencentCloudEipCreate(d *schema.ResourceData, meta interface{}) err
 	}
 
 	d.SetId(*eipId)
+	if err := updateResourceTags(meta.(*TencentCloudClient), d, "cvm", "eip"); err != nil {
+		return err
+	}
 	return resourceTencentCloudEipRead(d, meta)
 }
 
@@ -99,6 +102,7 @@ func resourceTencentCloudEipRead(d *schema.ResourceData, meta interface{}) error
 	eip, _, err := findEipById(cvmConn, eipId)
 	if err != nil {
 		if err == errEIPNotFound {
+			log.Printf("[WARN] eip %v not found, removing from state", eipId)
 			d.SetId("")
 			return nil
 		}
@@ -110,7 +114,7 @@ func resourceTencentCloudEipRead(d *schema.ResourceData, meta interface{}) error
 	if eip.AddressName != nil {
 		d.Set("name", *eip.AddressName)
 	}
-	return nil
+	return readResourceTags(meta.(*TencentCloudClient), d, "cvm", "eip")
 }
 
 func resourceTencentCloudEipUpdate(d *schema.ResourceData, meta interface{}) error {
@@ -128,6 +132,13 @@ func resourceTencentCloudEipUpdate(d *schema.ResourceData, meta interface{}) err
 		}
 	}
 
+	if d.HasChange("tags") {
+		err := updateResourceTags(meta.(*TencentCloudClient), d, "cvm", "eip")
+		if err != nil {
+			return err
+		}
+	}
+
 	return resourceTencentCloudEipRead(d, meta)
 }
 
@@ -136,25 +147,15 @@ func resourceTencentCloudEipDelete(d *schema.ResourceData, meta interface{}) err
 	eipId := d.Id()
 
 	// NOTE wait until eip is unbind
-	return resource.Retry(3*time.Minute, func() *resource.RetryError {
-		eip, _, err := findEipById(cvmConn, eipId)
-		if err != nil {
-			return resource.NonRetryableError(err)
-		}
-
-		status := *eip.AddressStatus
-		if status == tencentCloudApiEipStatusUnbind {
-			req := cvm.NewReleaseAddressesRequest()
-			req.AddressIds = []*string{
-				common.StringPtr(eipId),
-			}
-			_, err = cvmConn.ReleaseAddresses(req)
-			if err != nil {
-				return resource.NonRetryableError(err)
-			}
-			return nil
-		}
+	_, err := waitForEipStatus(cvmConn, eipId, []string{tencentCloudApiEipStatusUnbind}, 3*time.Minute)
+	if err != nil {
+		return err
+	}
 
-		return resource.RetryableError(errEIPStillDeleting)
-	})
+	req := cvm.NewReleaseAddressesRequest()
+	req.AddressIds = []*string{
+		common.StringPtr(eipId),
+	}
+	_, err = cvmConn.ReleaseAddresses(req)
+	return err
 }
@@ -3,11 +3,10 @@ package tencentcloud
 import (
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
-	"github.com/athom/goset"
-	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/zqfan/tencentcloud-sdk-go/common"
 	cvm "github.com/zqfan/tencentcloud-sdk-go/services/cvm/v20170312"
@@ -18,6 +17,9 @@ func resourceTencentCloudEipAssociation() *schema.Resource {
 		Create: resourceTencentCloudEipAssociationCreate,
 		Read:   resourceTencentCloudEipAssociationRead,
 		Delete: resourceTencentCloudEipAssociationDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"eip_id": &schema.Schema{
@@ -92,6 +94,9 @@ func resourceTencentCloudEipAssociationCreate(d *schema.ResourceData, meta inter
 
 		associationId := fmt.Sprintf("%v::%v", eipId, instanceId)
 		d.SetId(associationId)
+		if err := waitForEipBinded(cvmConn, eipId); err != nil {
+			return err
+		}
 		return resourceTencentCloudEipAssociationRead(d, meta)
 	}
 
@@ -120,6 +125,9 @@ func resourceTencentCloudEipAssociationCreate(d *schema.ResourceData, meta inter
 
 	associationId := fmt.Sprintf("%v::%v::%v", eipId, networkInterfaceId, privateIp)
 	d.SetId(associationId)
+	if err := waitForEipBinded(cvmConn, eipId); err != nil {
+		return err
+	}
 	return resourceTencentCloudEipAssociationRead(d, meta)
 }
 
@@ -129,6 +137,28 @@ func resourceTencentCloudEipAssociationRead(d *schema.ResourceData, meta interfa
 	if err != nil {
 		return err
 	}
+
+	// the association is gone if the eip is released or bound elsewhere
+	cvmConn := meta.(*TencentCloudClient).cvmConn
+	eip, _, err := findEipById(cvmConn, association.eipId)
+	if err != nil {
+		if err == errEIPNotFound {
+			log.Printf("[WARN] eip %v of association %v not found, removing from state", association.eipId, associationId)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	bound := eip.InstanceId != nil && *eip.InstanceId == association.instanceId
+	if len(association.instanceId) == 0 {
+		bound = eip.NetworkInterfaceId != nil && *eip.NetworkInterfaceId == association.networkInterfaceId
+	}
+	if !bound {
+		log.Printf("[WARN] eip association %v not found, removing from state", associationId)
+		d.SetId("")
+		return nil
+	}
+
 	d.Set("eip_id", association.eipId)
 	// associate with instance
 	if len(association.instanceId) > 0 {
@@ -151,27 +181,26 @@ func resourceTencentCloudEipAssociationDelete(d *schema.ResourceData, meta inter
 	eipId := association.eipId
 
 	// NOTE wait until eip is bind
-	return resource.Retry(3*time.Minute, func() *resource.RetryError {
-		eip, _, err := findEipById(cvmConn, eipId)
-		if err != nil {
-			return resource.NonRetryableError(err)
-		}
+	eip, err := waitForEipStatus(cvmConn, eipId, []string{
+		tencentCloudApiEipStatusBind,
+		tencentCloudApiEipStatusBindEni,
+		tencentCloudApiEipStatusUnbind,
+	}, 3*time.Minute)
+	if err != nil {
+		return err
+	}
+	if *eip.AddressStatus == tencentCloudApiEipStatusUnbind {
+		return nil
+	}
 
-		status := *eip.AddressStatus
-		if goset.IsIncluded([]string{
-			tencentCloudApiEipStatusBind,
-			tencentCloudApiEipStatusBindEni,
-		}, status) {
-			req := cvm.NewDisassociateAddressRequest()
-			req.AddressId = common.StringPtr(eipId)
-			_, err = cvmConn.DisassociateAddress(req)
-			if err != nil {
-				return resource.NonRetryableError(err)
-			}
-			return nil
-		}
-		return resource.RetryableError(errEIPStillUnbinding)
-	})
+	req := cvm.NewDisassociateAddressRequest()
+	req.AddressId = common.StringPtr(eipId)
+	_, err = cvmConn.DisassociateAddress(req)
+	if err != nil {
+		return err
+	}
+	_, err = waitForEipStatus(cvmConn, eipId, []string{tencentCloudApiEipStatusUnbind}, 3*time.Minute)
+	return err
 }
 
 type association struct {
@@ -30,6 +30,11 @@ func TestAccTencentCloudEipAssociationWithInstance(t *testing.T) {
 					resource.TestCheckNoResourceAttr("tencentcloud_eip_association.foo", "private_ip"),
 				),
 			},
+			{
+				ResourceName:      "tencentcloud_eip_association.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -60,7 +65,7 @@ func TestAccTencentCloudEipAssociationWithNetworkInterface(t *testing.T) {
 }
 
 func testAccCheckEipAssociationDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*TencentCloudClient).commonConn
+	client := testAccProvider.Meta().(*TencentCloudClient).cvmConn
 	cvmConn := testAccProvider.Meta().(*TencentCloudClient).cvmConn
 
 	var assId string
@@ -98,7 +103,7 @@ func testAccCheckEipAssociationDestroy(s *terraform.State) error {
 	if len(instanceId) > 0 {
 		instanceIds := []string{instanceId}
 
-		_, err = waitInstanceReachTargetStatus(client, instanceIds, "STOPPED")
+		_, err = waitInstanceReachTargetStatus(client, instanceIds, "STOPPED", defaultInstanceOperationTimeout)
 		if err != nil {
 			if err.Error() == instanceNotFoundErrorMsg(instanceIds) {
 				return nil
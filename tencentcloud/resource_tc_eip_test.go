@@ -48,6 +48,34 @@ func TestAccTencentCloudEip_basic(t *testing.T) {
 	})
 }
 
+func TestUnitTencentCloudEip_basic(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "eip", "tencentcloud_eip"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testAccEipBasicWithName),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "eip", "tencentcloud_eip.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_eip.foo", "name", "gateway_eip"),
+					resource.TestCheckResourceAttr("tencentcloud_eip.foo", "status", "UNBIND"),
+					resource.TestCheckResourceAttrSet("tencentcloud_eip.foo", "public_ip"),
+				),
+			},
+			{
+				Config: m.Config(testAccEipBasicWithNewName),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "eip", "tencentcloud_eip.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_eip.foo", "name", "new_name"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckEipDestroy(s *terraform.State) error {
 	cvmConn := testAccProvider.Meta().(*TencentCloudClient).cvmConn
 	var eipId string
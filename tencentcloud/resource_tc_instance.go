@@ -1,18 +1,33 @@
 package tencentcloud
 
 import (
-	"encoding/json"
+	"encoding/base64"
 	"fmt"
 	"log"
+	"math"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	cvm "github.com/zqfan/tencentcloud-sdk-go/services/cvm/v20170312"
 )
 
 const (
 	tencentCloudApiInstanceChargeTypePrePaid        = "PREPAID"
 	tencentCloudApiInstanceChargeTypePostPaidByHour = "POSTPAID_BY_HOUR"
+	tencentCloudApiInstanceChargeTypeSpotPaid       = "SPOTPAID"
+)
+
+// instancePrepaidRenewalsImported is the renewals of an imported instance,
+// which are not returned by the API. The next apply takes the renewals of the
+// configuration as they are, instead of renewing the instance.
+const instancePrepaidRenewalsImported = -1
+
+const (
+	tencentCloudApiInstanceMarketTypeSpot = "spot"
+
+	tencentCloudApiSpotInstanceTypeOneTime = "one-time"
 )
 
 const (
@@ -28,6 +43,11 @@ const (
 	tencentCloudApiInstanceChargeTypePrePaidRenewFlagDisableNotifyAndManualRenew = "DISABLE_NOTIFY_AND_MANUAL_RENEW"
 )
 
+const (
+	tencentCloudApiStoppedModeKeepCharging = "KEEP_CHARGING"
+	tencentCloudApiStoppedModeStopCharging = "STOP_CHARGING"
+)
+
 const (
 	tencentCloudApiDiskTypeLocalBaisc = "LOCAL_BASIC"
 	tencentCloudApiDiskTypeLocalSSD   = "LOCAL_SSD"
@@ -39,6 +59,7 @@ var (
 	availableInstanceChargeTypes = []string{
 		tencentCloudApiInstanceChargeTypePrePaid,
 		tencentCloudApiInstanceChargeTypePostPaidByHour,
+		tencentCloudApiInstanceChargeTypeSpotPaid,
 	}
 	availableInternetChargeTypes = []string{
 		tencentCloudApiInternetChargeTypeBandwithPrepaid,
@@ -52,6 +73,10 @@ var (
 		tencentCloudApiDiskTypeCloudBasic,
 		tencentCloudApiDiskTypeCloudSSD,
 	}
+	availableInstanceStoppedModes = []string{
+		tencentCloudApiStoppedModeKeepCharging,
+		tencentCloudApiStoppedModeStopCharging,
+	}
 	availableInstanceChargeTypePrePaidPeriodValues    = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 24, 36}
 	availableInstanceChargeTypePrePaidRenewFlagValues = []string{
 		tencentCloudApiInstanceChargeTypePrePaidRenewFlagNotifyAndAutoRenew,
@@ -63,13 +88,9 @@ var (
 var (
 	// TODO remove me when related feature implemented
 	unsupportedUpdateFields = []string{
-		"instance_charge_type_prepaid_period",
-		"instance_charge_type_prepaid_renew_flag",
 		"internet_charge_type",
 		"internet_max_bandwidth_out",
 		"allocate_public_ip",
-		"system_disk_size",
-		"data_disks",
 	}
 )
 
@@ -79,6 +100,15 @@ func resourceTencentCloudInstance() *schema.Resource {
 		Read:   resourceTencentCloudInstanceRead,
 		Update: resourceTencentCloudInstanceUpdate,
 		Delete: resourceTencentCloudInstanceDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceTencentCloudInstanceImport,
+		},
+		CustomizeDiff: resourceTencentCloudInstanceCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"image_id": &schema.Schema{
@@ -98,14 +128,14 @@ func resourceTencentCloudInstance() *schema.Resource {
 			"instance_type": &schema.Schema{
 				Type:         schema.TypeString,
 				Optional:     true,
-				ForceNew:     true,
+				Computed:     true,
 				ValidateFunc: validateInstanceType,
 			},
 			// payment
 			"instance_charge_type": &schema.Schema{
 				Type:         schema.TypeString,
 				Optional:     true,
-				ForceNew:     true,
+				Computed:     true,
 				ValidateFunc: validateInstanceChargeType,
 			},
 			"instance_charge_type_prepaid_period": &schema.Schema{
@@ -113,20 +143,46 @@ func resourceTencentCloudInstance() *schema.Resource {
 				Optional:     true,
 				ValidateFunc: validateInstanceChargeTypePrePaidPeriod,
 			},
+			"instance_charge_type_prepaid_renewals": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validateIntegerInRange(0, math.MaxInt32),
+			},
+			"instance_charge_type_prepaid_renew_period": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validateInstanceChargeTypePrePaidPeriod,
+			},
 			"instance_charge_type_prepaid_renew_flag": &schema.Schema{
 				Type:         schema.TypeString,
 				Optional:     true,
+				Computed:     true,
 				ValidateFunc: validateInstanceChargeTypePrePaidRenewFlag,
 			},
+			"spot_instance_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateAllowedStringValue([]string{tencentCloudApiSpotInstanceTypeOneTime}),
+			},
+			"spot_max_price": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
 			// network
 			"internet_charge_type": &schema.Schema{
 				Type:         schema.TypeString,
 				Optional:     true,
+				Computed:     true,
 				ValidateFunc: validateInternetChargeType,
 			},
 			"internet_max_bandwidth_out": &schema.Schema{
 				Type:         schema.TypeInt,
 				Optional:     true,
+				Computed:     true,
 				ValidateFunc: validateInternetMaxBandwidthOut,
 			},
 			"allocate_public_ip": &schema.Schema{
@@ -161,11 +217,10 @@ func resourceTencentCloudInstance() *schema.Resource {
 				ForceNew:     true,
 				ValidateFunc: validateDiskType,
 			},
-			// TODO finish me when integrating CBS
-			//"system_disk_id": &schema.Schema{
-			//	Type:     schema.TypeString,
-			//	Optional: true,
-			//},
+			"system_disk_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"system_disk_size": &schema.Schema{
 				Type:         schema.TypeInt,
 				Optional:     true,
@@ -181,18 +236,30 @@ func resourceTencentCloudInstance() *schema.Resource {
 						"data_disk_type": {
 							Type:         schema.TypeString,
 							Optional:     true,
+							Computed:     true,
+							ForceNew:     true,
 							ValidateFunc: validateDiskType,
 						},
-						// TODO finish me when integrating CBS
-						//"data_disk_id": &schema.Schema{
-						//	Type:     schema.TypeString,
-						//	Optional: true,
-						//},
+						"data_disk_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
 						"data_disk_size": &schema.Schema{
 							Type:         schema.TypeInt,
 							Optional:     true,
 							ValidateFunc: validateDiskSize,
 						},
+						"snapshot_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+						},
+						"delete_with_instance": &schema.Schema{
+							Type:     schema.TypeBool,
+							Optional: true,
+							ForceNew: true,
+							Default:  true,
+						},
 					},
 				},
 			},
@@ -216,45 +283,149 @@ func resourceTencentCloudInstance() *schema.Resource {
 				Optional:  true,
 				Sensitive: true,
 			},
+			"hostname": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			// user data
+			"user_data": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"user_data_raw"},
+				ValidateFunc:  validateUserData,
+			},
+			"user_data_raw": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"user_data"},
+			},
+			// placement
+			"project_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"placement_group_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			// running state
+			"running_flag": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"stopped_mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateAllowedStringValue(availableInstanceStoppedModes),
+			},
 
 			// Computed values.
 			"instance_status": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
 			},
-			"private_ip": &schema.Schema{
+			"stop_charging_mode": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"private_ip": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIp,
+			},
 			"public_ip": &schema.Schema{
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"tags": tagsSchema(),
 		},
 	}
 }
 
-func resourceTencentCloudInstanceCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*TencentCloudClient).commonConn
+func resourceTencentCloudInstanceImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	d.Set("instance_charge_type_prepaid_renewals", instancePrepaidRenewalsImported)
+	d.Set("instance_charge_type_prepaid_renew_period", 1)
+	return []*schema.ResourceData{d}, nil
+}
 
-	params := map[string]string{
-		"Version":        "2017-03-12",
-		"Action":         "RunInstances",
-		"Placement.Zone": d.Get("availability_zone").(string),
-		"ImageId":        d.Get("image_id").(string),
+// resourceTencentCloudInstanceCustomizeDiff rejects the changes which can not
+// be applied, so that the plan fails before anything is changed. The charge
+// type can only be converted from postpaid to prepaid. The prepaid period is
+// used only when the instance becomes prepaid, and a renewal can not be
+// undone.
+func resourceTencentCloudInstanceCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
 	}
-
-	if instanceType, ok := d.GetOk("instance_type"); ok {
-		insType := instanceType.(string)
-		if len(insType) > 0 {
-			params["InstanceType"] = insType
+	oldChargeType, newChargeType := d.GetChange("instance_charge_type")
+	if d.HasChange("instance_charge_type") {
+		if oldChargeType.(string) != tencentCloudApiInstanceChargeTypePostPaidByHour || newChargeType.(string) != tencentCloudApiInstanceChargeTypePrePaid {
+			return fmt.Errorf(
+				"tencentcloud_instance instance_charge_type can only be changed from %v to %v, got %v to %v",
+				tencentCloudApiInstanceChargeTypePostPaidByHour,
+				tencentCloudApiInstanceChargeTypePrePaid,
+				oldChargeType, newChargeType,
+			)
+		}
+		if _, ok := d.GetOk("instance_charge_type_prepaid_period"); !ok {
+			return fmt.Errorf(
+				"tencentcloud_instance instance_charge_type_prepaid_period is need when instance_charge_type is %v",
+				tencentCloudApiInstanceChargeTypePrePaid,
+			)
 		}
 	}
-	if instanceName, ok := d.GetOk("instance_name"); ok {
-		insName := instanceName.(string)
-		if len(insName) > 0 {
-			params["InstanceName"] = insName
+	prepaid := oldChargeType.(string) == tencentCloudApiInstanceChargeTypePrePaid && newChargeType.(string) == tencentCloudApiInstanceChargeTypePrePaid
+	// the prepaid period is not returned by the API, so it is 0 after import
+	if o, n := d.GetChange("instance_charge_type_prepaid_period"); prepaid && o.(int) != 0 && n.(int) < o.(int) {
+		return fmt.Errorf(
+			"tencentcloud_instance instance_charge_type_prepaid_period can not be decreased from %v to %v, "+
+				"it is used only on create, renew the instance with instance_charge_type_prepaid_renewals", o, n)
+	}
+	if o, n := d.GetChange("instance_charge_type_prepaid_renewals"); o.(int) == instancePrepaidRenewalsImported {
+		return nil
+	} else if n.(int) > o.(int) {
+		if newChargeType.(string) != tencentCloudApiInstanceChargeTypePrePaid {
+			return fmt.Errorf(
+				"tencentcloud_instance instance_charge_type_prepaid_renewals only works when instance_charge_type is %v",
+				tencentCloudApiInstanceChargeTypePrePaid)
 		}
+	} else if n.(int) < o.(int) {
+		return fmt.Errorf("tencentcloud_instance instance_charge_type_prepaid_renewals can not be decreased from %v to %v", o, n)
+	}
+	return nil
+}
+
+func resourceTencentCloudInstanceCreate(d *schema.ResourceData, m interface{}) error {
+	cvmConn := m.(*TencentCloudClient).cvmConn
+
+	req := cvm.NewRunInstancesRequest()
+	req.Placement = &cvm.Placement{
+		Zone: common.StringPtr(d.Get("availability_zone").(string)),
+	}
+	if v, ok := d.GetOk("project_id"); ok {
+		req.Placement.ProjectId = common.IntPtr(v.(int))
+	}
+	if v, ok := d.GetOk("placement_group_id"); ok {
+		req.DisasterRecoverGroupIds = []*string{common.StringPtr(v.(string))}
+	}
+	req.ImageId = common.StringPtr(d.Get("image_id").(string))
+	req.InstanceCount = common.IntPtr(1)
+	// the token makes the retries of RunInstances create only one instance
+	req.ClientToken = common.StringPtr(resource.UniqueId())
+
+	if v, ok := d.GetOk("instance_type"); ok {
+		req.InstanceType = common.StringPtr(v.(string))
+	}
+	if v, ok := d.GetOk("instance_name"); ok {
+		req.InstanceName = common.StringPtr(v.(string))
 	}
 
 	if instanceChargeType, ok := d.GetOk("instance_charge_type"); ok {
@@ -267,51 +438,63 @@ func resourceTencentCloudInstanceCreate(d *schema.ResourceData, m interface{}) e
 					tencentCloudApiInstanceChargeTypePrePaid,
 				)
 			}
-			periodStr := fmt.Sprintf("%v", period.(int))
-			params["InstanceChargePrepaid.Period"] = periodStr
+			req.InstanceChargePrepaid = &cvm.InstanceChargePrepaid{
+				Period: common.IntPtr(period.(int)),
+			}
 			if renewFlag, ok := d.GetOk("instance_charge_type_prepaid_renew_flag"); ok {
-				params["InstanceChargePrepaid.RenewFlag"] = renewFlag.(string)
+				req.InstanceChargePrepaid.RenewFlag = common.StringPtr(renewFlag.(string))
+			}
+		}
+		if insChargeType == tencentCloudApiInstanceChargeTypeSpotPaid {
+			req.InstanceMarketOptions = &cvm.InstanceMarketOptionsRequest{
+				MarketType:  common.StringPtr(tencentCloudApiInstanceMarketTypeSpot),
+				SpotOptions: &cvm.SpotMarketOptions{},
+			}
+			if v, ok := d.GetOk("spot_instance_type"); ok {
+				req.InstanceMarketOptions.SpotOptions.SpotInstanceType = common.StringPtr(v.(string))
+			}
+			if v, ok := d.GetOk("spot_max_price"); ok {
+				req.InstanceMarketOptions.SpotOptions.MaxPrice = common.StringPtr(v.(string))
+			}
+		}
+		req.InstanceChargeType = common.StringPtr(insChargeType)
+	}
+	if req.InstanceMarketOptions == nil {
+		for _, field := range []string{"spot_instance_type", "spot_max_price"} {
+			if _, ok := d.GetOk(field); ok {
+				return fmt.Errorf(
+					"tencentcloud_instance %v only works when instance_charge_type is %v",
+					field,
+					tencentCloudApiInstanceChargeTypeSpotPaid,
+				)
 			}
 		}
-
-		params["InstanceChargeType"] = insChargeType
 	}
 
 	// network releated
-	if internetChargeType, ok := d.GetOk("internet_charge_type"); ok {
-		netChargeType := internetChargeType.(string)
-		params["InternetAccessible.InternetChargeType"] = netChargeType
+	req.InternetAccessible = &cvm.InternetAccessible{}
+	if v, ok := d.GetOk("allocate_public_ip"); ok {
+		req.InternetAccessible.PublicIpAssigned = common.BoolPtr(v.(bool))
 	}
-	if internetMaxBandwidthOut, ok := d.GetOk("internet_max_bandwidth_out"); ok {
-		maxBandwidthOut := internetMaxBandwidthOut.(int)
-		params["InternetAccessible.InternetMaxBandwidthOut"] = fmt.Sprintf("%v", maxBandwidthOut)
+	if v, ok := d.GetOk("internet_charge_type"); ok {
+		req.InternetAccessible.InternetChargeType = common.StringPtr(v.(string))
 	}
-	if allocatePublicIP, ok := d.GetOk("allocate_public_ip"); ok {
-		if allocatePublicIP.(bool) {
-			params["InternetAccessible.PublicIpAssigned"] = "TRUE"
-		} else {
-			params["InternetAccessible.PublicIpAssigned"] = "FALSE"
-		}
+	if v, ok := d.GetOk("internet_max_bandwidth_out"); ok {
+		req.InternetAccessible.InternetMaxBandwidthOut = common.IntPtr(v.(int))
 	}
 
 	// security groups
 	if v, ok := d.GetOk("security_groups"); ok {
-		sgIds := v.(*schema.Set).List()
-		if len(sgIds) > 0 {
-			for i, sgId := range sgIds {
-				paramKey := fmt.Sprintf("SecurityGroupIds.%v", i)
-				params[paramKey] = sgId.(string)
-			}
-		}
+		req.SecurityGroupIds = common.StringPtrs(expandStringList(v.(*schema.Set).List()))
 	}
 
 	// storage
-	if systemDiskType, ok := d.GetOk("system_disk_type"); ok {
-		params["SystemDisk.DiskType"] = systemDiskType.(string)
+	req.SystemDisk = &cvm.SystemDisk{}
+	if v, ok := d.GetOk("system_disk_type"); ok {
+		req.SystemDisk.DiskType = common.StringPtr(v.(string))
 	}
-	if systemDiskSize, ok := d.GetOk("system_disk_size"); ok {
-		diskSize := systemDiskSize.(int)
-		params["SystemDisk.DiskSize"] = fmt.Sprintf("%v", diskSize)
+	if v, ok := d.GetOk("system_disk_size"); ok {
+		req.SystemDisk.DiskSize = common.IntPtr(v.(int))
 	}
 	var dataDisksAttr []map[string]interface{}
 	if dataDisks, ok := d.GetOk("data_disks"); ok {
@@ -319,247 +502,198 @@ func resourceTencentCloudInstanceCreate(d *schema.ResourceData, m interface{}) e
 		if len(dataDiskList) > 1 {
 			return fmt.Errorf("tencentcloud_instance currently only one data disk is supported during instance creation")
 		}
-		for i, dataDisk := range dataDiskList {
+		for _, dataDisk := range dataDiskList {
 			dd := dataDisk.(map[string]interface{})
+			disk := &cvm.DataDisks{}
 			if v, ok := dd["data_disk_type"].(string); ok && v != "" {
-				paramKey := fmt.Sprintf("DataDisks.%v.DiskType", i)
-				params[paramKey] = v
+				disk.DiskType = common.StringPtr(v)
 			}
 			if v, ok := dd["data_disk_size"].(int); ok {
-				paramKey := fmt.Sprintf("DataDisks.%v.DiskSize", i)
-				paramValue := fmt.Sprintf("%v", v)
-				params[paramKey] = paramValue
+				disk.DiskSize = common.IntPtr(v)
+			}
+			if v, ok := dd["snapshot_id"].(string); ok && v != "" {
+				disk.SnapshotId = common.StringPtr(v)
 			}
+			if v, ok := dd["delete_with_instance"].(bool); ok {
+				disk.DeleteWithInstance = common.BoolPtr(v)
+			}
+			req.DataDisks = append(req.DataDisks, disk)
 			dataDisksAttr = append(dataDisksAttr, dd)
 		}
 	}
 
 	// enhance services
-	if v, ok := d.GetOk("disable_security_service"); ok {
-		disable := v.(bool)
-		if disable {
-			params["EnhancedService.SecurityService.Enabled"] = "FALSE"
-		}
-	}
-	if v, ok := d.GetOk("disable_monitor_service"); ok {
-		disable := v.(bool)
-		if disable {
-			params["EnhancedService.MonitorService.Enabled"] = "FALSE"
-		}
-	}
+	req.EnhancedService = instanceEnhancedService(d)
 
 	// login confidential
+	req.LoginSettings = &cvm.LoginSettings{}
 	if v, ok := d.GetOk("key_name"); ok {
-		keyId := v.(string)
-		params["LoginSettings.KeyIds.0"] = keyId
+		req.LoginSettings.KeyIds = []*string{common.StringPtr(v.(string))}
 	}
 	if v, ok := d.GetOk("password"); ok {
-		passwd := v.(string)
-		params["LoginSettings.Password"] = passwd
+		req.LoginSettings.Password = common.StringPtr(v.(string))
+	}
+	if v, ok := d.GetOk("hostname"); ok {
+		req.HostName = common.StringPtr(v.(string))
+	}
+
+	// user data
+	if v, ok := d.GetOk("user_data"); ok {
+		req.UserData = common.StringPtr(v.(string))
+	}
+	if v, ok := d.GetOk("user_data_raw"); ok {
+		req.UserData = common.StringPtr(base64.StdEncoding.EncodeToString([]byte(v.(string))))
 	}
 
 	// vpc
+	req.VirtualPrivateCloud = &cvm.VirtualPrivateCloud{}
 	if v, ok := d.GetOk("vpc_id"); ok {
-		vpcId := v.(string)
-		params["VirtualPrivateCloud.VpcId"] = vpcId
+		req.VirtualPrivateCloud.VpcId = common.StringPtr(v.(string))
 	}
 	if v, ok := d.GetOk("subnet_id"); ok {
-		subnetId := v.(string)
-		params["VirtualPrivateCloud.SubnetId"] = subnetId
+		req.VirtualPrivateCloud.SubnetId = common.StringPtr(v.(string))
+	}
+	if v, ok := d.GetOk("private_ip"); ok {
+		req.VirtualPrivateCloud.PrivateIpAddresses = []*string{common.StringPtr(v.(string))}
 	}
 
-	response, err := client.SendRequest("cvm", params)
+	resp, err := cvmConn.RunInstances(req)
 	if err != nil {
 		return err
 	}
-	var jsonresp struct {
-		Response struct {
-			Error struct {
-				Code    string `json:"Code"`
-				Message string `json:"Message"`
-			}
-			InstanceIdSet []string
-			RequestId     string
-		}
+	if len(resp.Response.InstanceIdSet) == 0 {
+		return fmt.Errorf("tencentcloud_instance no instance id returned")
 	}
-	err = json.Unmarshal([]byte(response), &jsonresp)
+	instanceId := *resp.Response.InstanceIdSet[0]
+	d.SetId(instanceId)
+	d.Set("data_disks", dataDisksAttr)
+
+	instanceStatusMap, err := waitInstanceReachTargetStatus(cvmConn, []string{instanceId}, "RUNNING", d.Timeout(schema.TimeoutCreate))
 	if err != nil {
 		return err
 	}
-	if jsonresp.Response.Error.Code != "" {
-		return fmt.Errorf(
-			"tencentcloud_instance got error, code:%v, message:%v",
-			jsonresp.Response.Error.Code,
-			jsonresp.Response.Error.Message,
-		)
-	}
-	if len(jsonresp.Response.InstanceIdSet) == 0 {
-		return fmt.Errorf("tencentcloud_instance no instance id returned")
-	}
+	d.Set("instance_status", instanceStatusMap[instanceId])
 
-	var instanceStatusMap map[string]string
-	instanceStatusMap, err = waitInstanceReachTargetStatus(client, jsonresp.Response.InstanceIdSet, "RUNNING")
-	if err != nil {
+	if err := updateResourceTags(m.(*TencentCloudClient), d, "cvm", "instance"); err != nil {
 		return err
 	}
 
-	instanceId := jsonresp.Response.InstanceIdSet[0]
-	d.SetId(instanceId)
-	d.Set("instance_status", instanceStatusMap[instanceId])
-	d.Set("data_disks", dataDisksAttr)
+	if !d.Get("running_flag").(bool) {
+		err = modifyInstanceRunningState(cvmConn, instanceId, false, d.Get("stopped_mode").(string), d.Timeout(schema.TimeoutCreate))
+		if err != nil {
+			return err
+		}
+	}
 	return resourceTencentCloudInstanceRead(d, m)
 }
 
 func resourceTencentCloudInstanceRead(d *schema.ResourceData, m interface{}) error {
 	instanceId := d.Id()
-	params := map[string]string{
-		"Version": "2017-03-12",
-		"Action":  "DescribeInstances",
-	}
-	params["InstanceIds.0"] = instanceId
+	cvmConn := m.(*TencentCloudClient).cvmConn
 
-	client := m.(*TencentCloudClient).commonConn
-	response, err := client.SendRequest("cvm", params)
-	if err != nil {
-		return err
+	instance, err := describeInstanceById(cvmConn, instanceId)
+	if err == errInstanceNotFound {
+		log.Printf("[WARN] instance %v not found, removing from state", instanceId)
+		d.SetId("")
+		return nil
 	}
-	var jsonresp struct {
-		Response struct {
-			Error struct {
-				Code    string `json:"Code"`
-				Message string `json:"Message"`
-			}
-			TotalCount  int
-			InstanceSet []struct {
-				Placement struct {
-					Zone      string   `json:"Zone"`
-					ProjectId int      `json:"ProjectId"`
-					HostIds   []string `json:"HostIds"`
-				} `json:"Placement"`
-
-				InstanceId   string `json:"InstanceId"`
-				InstanceType string `json:"InstanceType"`
-				CPU          int    `json:"CPU"`
-				Memory       int    `json:"Memory"`
-
-				RestrictState      string `json:"RestrictState"`
-				InstanceName       string `json:"InstanceName"`
-				InstanceChargeType string `json:"InstanceChargeType"`
-
-				SystemDisk struct {
-					DiskType string `json:"DiskType"`
-					DiskId   string `json:"DiskId"`
-					DiskSize int    `json:"DiskSize"`
-				} `json:"SystemDisk"`
-
-				DataDisks []struct {
-					DiskType string `json:"DiskType"`
-					DiskId   string `json:"DiskId"`
-					DiskSize int    `json:"DiskSize"`
-				} `json:"DataDisks"`
-
-				PrivateIpAddresses []string `json:"PrivateIpAddresses"`
-				PublicIpAddresses  []string `json:"PublicIpAddresses"`
-
-				InternetAccessible struct {
-					InternetChargeType      string `json:"InternetChargeType"`
-					InternetMaxBandwidthOut int    `json:"InternetMaxBandwidthOut"`
-					PublicIpAssigned        bool   `json:"PublicIpAssigned"`
-				} `json:"InternetAccessible"`
-
-				VirtualPrivateCloud struct {
-					VpcId              string   `json:"VpcId"`
-					SubnetId           string   `json:"SubnetId"`
-					AsVpcGateway       bool     `json:"AsVpcGateway"`
-					PrivateIpAddresses []string `json:"PrivateIpAddresses"`
-				}
-
-				SecurityGroupIds []string `json:"SecurityGroupIds"`
-
-				LoginSettings struct {
-					KeyIds []string `json:"KeyIds"`
-				} `json:"LoginSettings"`
-
-				ImageId string `json:"ImageId"`
-
-				RenewFlag   string    `json:"RenewFlag"`
-				CreatedTime time.Time `json:"CreatedTime"`
-				ExpiredTime time.Time `json:"ExpiredTime"`
-			} `json:"InstanceSet"`
-			RequestId string
-		}
-	}
-	err = json.Unmarshal([]byte(response), &jsonresp)
 	if err != nil {
 		return err
 	}
-	if jsonresp.Response.Error.Code != "" {
-		return fmt.Errorf(
-			"tencentcloud_instance got error, code:%v, message:%v",
-			jsonresp.Response.Error.Code,
-			jsonresp.Response.Error.Message,
-		)
+
+	d.Set("image_id", instance.ImageId)
+	d.Set("instance_name", instance.InstanceName)
+	d.Set("instance_type", instance.InstanceType)
+	d.Set("instance_charge_type", instance.InstanceChargeType)
+	if instance.InstanceChargeType != nil && *instance.InstanceChargeType == tencentCloudApiInstanceChargeTypePrePaid {
+		d.Set("instance_charge_type_prepaid_renew_flag", instance.RenewFlag)
+	}
+	if instance.InstanceState != nil {
+		d.Set("instance_status", instance.InstanceState)
+		// a transitional state tells nothing about the wanted state
+		switch *instance.InstanceState {
+		case "RUNNING":
+			d.Set("running_flag", true)
+		case "STOPPED":
+			d.Set("running_flag", false)
+		}
 	}
-	if len(jsonresp.Response.InstanceSet) == 0 {
-		d.SetId("")
-		return nil
+	d.Set("stop_charging_mode", instance.StopChargingMode)
+	if instance.Placement != nil {
+		d.Set("availability_zone", instance.Placement.Zone)
+		d.Set("project_id", instance.Placement.ProjectId)
 	}
-	privateIPs := jsonresp.Response.InstanceSet[0].PrivateIpAddresses
-	if len(privateIPs) > 0 {
-		d.Set("private_ip", privateIPs[0])
+	if instance.InternetAccessible != nil {
+		d.Set("internet_charge_type", instance.InternetAccessible.InternetChargeType)
+		d.Set("internet_max_bandwidth_out", instance.InternetAccessible.InternetMaxBandwidthOut)
+		d.Set("allocate_public_ip", instance.InternetAccessible.PublicIpAssigned)
 	}
-	publicIPs := jsonresp.Response.InstanceSet[0].PublicIpAddresses
-	if len(publicIPs) > 0 {
-		d.Set("public_ip", publicIPs[0])
+
+	if len(instance.PrivateIpAddresses) > 0 {
+		d.Set("private_ip", instance.PrivateIpAddresses[0])
+	}
+	if len(instance.PublicIpAddresses) > 0 {
+		d.Set("public_ip", instance.PublicIpAddresses[0])
+	}
+	if instance.SystemDisk != nil {
+		d.Set("system_disk_type", instance.SystemDisk.DiskType)
+		d.Set("system_disk_id", instance.SystemDisk.DiskId)
+		d.Set("system_disk_size", instance.SystemDisk.DiskSize)
 	}
-	systemDiskType := jsonresp.Response.InstanceSet[0].SystemDisk.DiskType
-	d.Set("system_disk_type", systemDiskType)
-	systemDiskSize := jsonresp.Response.InstanceSet[0].SystemDisk.DiskSize
-	d.Set("system_disk_size", systemDiskSize)
 
+	// the snapshot a disk is created from is not returned by the API
+	oldDataDisks := d.Get("data_disks").([]interface{})
 	var dataDiskList []map[string]interface{}
-	dataDisks := jsonresp.Response.InstanceSet[0].DataDisks
-	for _, dataDisk := range dataDisks {
-		m := make(map[string]interface{})
-		diskType := dataDisk.DiskType
-		diskSize := dataDisk.DiskSize
-		m["data_disk_type"] = diskType
-		m["data_disk_size"] = diskSize
+	for i, dataDisk := range instance.DataDisks {
+		m := map[string]interface{}{
+			"delete_with_instance": true,
+		}
+		if dataDisk.DiskType != nil {
+			m["data_disk_type"] = *dataDisk.DiskType
+		}
+		if dataDisk.DiskId != nil {
+			m["data_disk_id"] = *dataDisk.DiskId
+		}
+		if dataDisk.DiskSize != nil {
+			m["data_disk_size"] = *dataDisk.DiskSize
+		}
+		if dataDisk.DeleteWithInstance != nil {
+			m["delete_with_instance"] = *dataDisk.DeleteWithInstance
+		}
+		if dataDisk.SnapshotId != nil {
+			m["snapshot_id"] = *dataDisk.SnapshotId
+		} else if i < len(oldDataDisks) {
+			if old, ok := oldDataDisks[i].(map[string]interface{}); ok {
+				m["snapshot_id"] = old["snapshot_id"]
+			}
+		}
 		dataDiskList = append(dataDiskList, m)
 	}
-	//d.Set("data_disks", dataDiskList)
+	d.Set("data_disks", dataDiskList)
 
-	securityGroupIds := jsonresp.Response.InstanceSet[0].SecurityGroupIds
-	if len(securityGroupIds) > 0 {
-		d.Set("security_groups", securityGroupIds)
+	if len(instance.SecurityGroupIds) > 0 {
+		d.Set("security_groups", common.StringValues(instance.SecurityGroupIds))
 	}
 
-	loginSettings := jsonresp.Response.InstanceSet[0].LoginSettings
-	keyIds := loginSettings.KeyIds
-	if len(keyIds) > 0 {
-		keyName := keyIds[0]
-		d.Set("key_name", keyName)
-	}
-	if v, ok := d.GetOk("password"); ok {
-		passwd := v.(string)
-		d.Set("password", passwd)
+	if instance.LoginSettings != nil && len(instance.LoginSettings.KeyIds) > 0 {
+		d.Set("key_name", instance.LoginSettings.KeyIds[0])
 	}
 
-	virtualPrivateCloud := jsonresp.Response.InstanceSet[0].VirtualPrivateCloud
-	vpcId := virtualPrivateCloud.VpcId
-	if len(vpcId) > 0 {
-		d.Set("vpc_id", vpcId)
-	}
-	subnetId := virtualPrivateCloud.SubnetId
-	if len(subnetId) > 0 {
-		d.Set("subnet_id", subnetId)
+	if vpc := instance.VirtualPrivateCloud; vpc != nil {
+		if vpc.VpcId != nil && *vpc.VpcId != "" {
+			d.Set("vpc_id", vpc.VpcId)
+		}
+		if vpc.SubnetId != nil && *vpc.SubnetId != "" {
+			d.Set("subnet_id", vpc.SubnetId)
+		}
 	}
 
-	return nil
+	return readResourceTags(m.(*TencentCloudClient), d, "cvm", "instance")
 }
 
 func resourceTencentCloudInstanceUpdate(d *schema.ResourceData, m interface{}) (err error) {
 	client := m.(*TencentCloudClient).commonConn
+	cvmConn := m.(*TencentCloudClient).cvmConn
 	instanceId := d.Id()
 
 	for _, field := range unsupportedUpdateFields {
@@ -580,10 +714,140 @@ func resourceTencentCloudInstanceUpdate(d *schema.ResourceData, m interface{}) (
 		d.SetPartial("instance_name")
 		oldInstanceName, newInstanceName := d.GetChange("instance_name")
 		log.Printf("[DEBUG] tencentcloud_instance rename instance_name from %v to %v", oldInstanceName, newInstanceName)
-		err = renameInstancesName(client, []string{instanceId}, newInstanceName.(string))
+		err = renameInstancesName(cvmConn, []string{instanceId}, newInstanceName.(string))
+		if err != nil {
+			return err
+		}
+	}
+
+	// the conversion is checked by resourceTencentCloudInstanceCustomizeDiff
+	if d.HasChange("instance_charge_type") {
+		n := d.Get("instance_charge_type")
+		period := d.Get("instance_charge_type_prepaid_period")
+		renewFlag := d.Get("instance_charge_type_prepaid_renew_flag").(string)
+		log.Printf("[DEBUG] tencentcloud_instance convert instance to %v for %v months", n, period)
+
+		err = modifyInstanceChargeTypeToPrepaid(cvmConn, instanceId, period.(int), renewFlag, d.Timeout(schema.TimeoutUpdate))
 		if err != nil {
 			return err
 		}
+		d.SetPartial("instance_charge_type")
+		d.SetPartial("instance_charge_type_prepaid_period")
+		d.SetPartial("instance_charge_type_prepaid_renew_flag")
+	} else if d.Get("instance_charge_type").(string) == tencentCloudApiInstanceChargeTypePrePaid {
+		if d.HasChange("instance_charge_type_prepaid_renew_flag") {
+			renewFlag := d.Get("instance_charge_type_prepaid_renew_flag").(string)
+			log.Printf("[DEBUG] tencentcloud_instance modify renew flag to %v", renewFlag)
+			err = modifyInstanceRenewFlag(cvmConn, instanceId, renewFlag)
+			if err != nil {
+				return err
+			}
+			d.SetPartial("instance_charge_type_prepaid_renew_flag")
+		}
+		// the prepaid period is used only when the instance becomes prepaid
+		d.SetPartial("instance_charge_type_prepaid_period")
+	}
+
+	if o, n := d.GetChange("instance_charge_type_prepaid_renewals"); o.(int) == instancePrepaidRenewalsImported {
+		log.Printf("[DEBUG] tencentcloud_instance take the renewals %v of the imported instance", n)
+		d.SetPartial("instance_charge_type_prepaid_renewals")
+	} else if d.HasChange("instance_charge_type_prepaid_renewals") {
+		period := d.Get("instance_charge_type_prepaid_renew_period").(int)
+		for i := o.(int); i < n.(int); i++ {
+			log.Printf("[DEBUG] tencentcloud_instance renew instance for %v months", period)
+			err = renewInstance(cvmConn, instanceId, period)
+			if err != nil {
+				return err
+			}
+			// a failed renewal is retried on the next apply, the done ones are not
+			d.Set("instance_charge_type_prepaid_renewals", i+1)
+			d.SetPartial("instance_charge_type_prepaid_renewals")
+		}
+	}
+	d.SetPartial("instance_charge_type_prepaid_renew_period")
+
+	if d.HasChange("project_id") {
+		projectId := d.Get("project_id").(int)
+		log.Printf("[DEBUG] tencentcloud_instance move instance to project %v", projectId)
+		err = modifyInstanceProject(cvmConn, instanceId, projectId)
+		if err != nil {
+			return err
+		}
+		d.SetPartial("project_id")
+	}
+
+	if d.HasChange("tags") {
+		err = updateResourceTags(m.(*TencentCloudClient), d, "cvm", "instance")
+		if err != nil {
+			return err
+		}
+		d.SetPartial("tags")
+	}
+
+	if d.HasChange("instance_type") {
+		oldType, newType := d.GetChange("instance_type")
+		log.Printf("[DEBUG] tencentcloud_instance resize instance_type from %v to %v", oldType, newType)
+
+		_, err := waitInstanceReachOneOfTargetStatusList(
+			cvmConn,
+			[]string{instanceId},
+			[]string{
+				"STOPPED",
+				"RUNNING",
+			},
+			d.Timeout(schema.TimeoutUpdate),
+		)
+		if err != nil {
+			return err
+		}
+
+		err = resetInstanceType(cvmConn, instanceId, newType.(string), d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return err
+		}
+		d.SetPartial("instance_type")
+	}
+
+	if d.HasChange("data_disks") || (d.HasChange("system_disk_size") && !isUpdatingImage) {
+		// the system disk is resized by the reinstallation when the image
+		// is changed at the same time
+		systemDiskSize := 0
+		if d.HasChange("system_disk_size") && !isUpdatingImage {
+			o, n := d.GetChange("system_disk_size")
+			if n.(int) < o.(int) {
+				return fmt.Errorf("tencentcloud_instance system_disk_size can not be shrunk from %v to %v", o, n)
+			}
+			systemDiskSize = n.(int)
+		}
+		dataDisks, err := instanceResizedDataDisks(d)
+		if err != nil {
+			return err
+		}
+		log.Printf("[DEBUG] tencentcloud_instance resize disks, system disk: %v, data disks: %v", systemDiskSize, len(dataDisks))
+
+		if systemDiskSize > 0 || len(dataDisks) > 0 {
+			_, err = waitInstanceReachOneOfTargetStatusList(
+				cvmConn,
+				[]string{instanceId},
+				[]string{
+					"STOPPED",
+					"RUNNING",
+				},
+				d.Timeout(schema.TimeoutUpdate),
+			)
+			if err != nil {
+				return err
+			}
+
+			err = resizeInstanceDisks(cvmConn, instanceId, systemDiskSize, dataDisks, d.Timeout(schema.TimeoutUpdate))
+			if err != nil {
+				return err
+			}
+		}
+		d.SetPartial("data_disks")
+		if !isUpdatingImage {
+			d.SetPartial("system_disk_size")
+		}
 	}
 
 	if d.HasChange("key_name") {
@@ -595,18 +859,19 @@ func resourceTencentCloudInstanceUpdate(d *schema.ResourceData, m interface{}) (
 		log.Printf("[DEBUG] tencentcloud_instance rebind key pair, old key: %v, new key: %v", oldKey, newKey)
 
 		_, err := waitInstanceReachOneOfTargetStatusList(
-			client,
+			cvmConn,
 			[]string{instanceId},
 			[]string{
 				"STOPPED",
 				"RUNNING",
 			},
+			d.Timeout(schema.TimeoutUpdate),
 		)
 		if err != nil {
 			return err
 		}
 
-		err = bindKeyPiar(client, instanceId, newKey.(string))
+		err = bindKeyPiar(client, cvmConn, instanceId, newKey.(string), d.Timeout(schema.TimeoutUpdate))
 		if err != nil {
 			return err
 		}
@@ -619,7 +884,7 @@ func resourceTencentCloudInstanceUpdate(d *schema.ResourceData, m interface{}) (
 		}
 		_, newValue := d.GetChange("password")
 		log.Printf("[DEBUG] tencentcloud_instance reset password\n")
-		err = resetInstancePassword(client, instanceId, newValue.(string))
+		err = resetInstancePassword(cvmConn, instanceId, newValue.(string), d.Timeout(schema.TimeoutUpdate))
 		if err != nil {
 			return err
 		}
@@ -634,7 +899,7 @@ func resourceTencentCloudInstanceUpdate(d *schema.ResourceData, m interface{}) (
 			return fmt.Errorf("tencentcloud_instance security groups are not allow to be empty")
 		}
 
-		err = bindInstanceWithSgIds(client, d.Id(), sgIds)
+		err = bindInstanceWithSgIds(cvmConn, d.Id(), sgIds)
 		if err != nil {
 			return err
 		}
@@ -647,57 +912,81 @@ LABEL_REINSTALL:
 		oldValue, newValue := d.GetChange("image_id")
 		log.Printf("[DEBUG] tencentcloud_instance reinstall image from %v to %v\n", oldValue, newValue)
 
-		err = resetInstanceSystem(client, d, instanceId, newValue.(string))
+		err = resetInstanceSystem(cvmConn, d, instanceId, newValue.(string), d.Timeout(schema.TimeoutUpdate))
 		if err != nil {
 			return err
 		}
 	}
 
+	// the instance may be started by the operations above, so the running
+	// state is checked after all of them
+	running := d.Get("running_flag").(bool)
+	err = modifyInstanceRunningState(cvmConn, instanceId, running, d.Get("stopped_mode").(string), d.Timeout(schema.TimeoutUpdate))
+	if err != nil {
+		return err
+	}
+	d.SetPartial("running_flag")
+	d.SetPartial("stopped_mode")
+
 	d.Partial(false)
 
 	return resourceTencentCloudInstanceRead(d, m)
 }
 
-func resourceTencentCloudInstanceDelete(d *schema.ResourceData, m interface{}) error {
-	v, ok := d.GetOk("instance_charge_type")
-	if ok {
-		instanceChargeType := v.(string)
-		if instanceChargeType == tencentCloudApiInstanceChargeTypePrePaid {
-			return fmt.Errorf("prepaid instance %v is not allowed to be deleted in terraform", d.Id())
+// instanceResizedDataDisks returns the data disks whose size is increased,
+// the data disks can only be expanded in place, they can't be added, removed
+// or shrunk.
+func instanceResizedDataDisks(d *schema.ResourceData) ([]*cvm.DataDisks, error) {
+	o, n := d.GetChange("data_disks")
+	oldDisks, newDisks := o.([]interface{}), n.([]interface{})
+	if len(oldDisks) != len(newDisks) {
+		return nil, fmt.Errorf("tencentcloud_instance data disks can not be added or removed, use tencentcloud_cbs_storage_attachment instead")
+	}
+
+	var dataDisks []*cvm.DataDisks
+	for i := range oldDisks {
+		oldDisk, newDisk := oldDisks[i].(map[string]interface{}), newDisks[i].(map[string]interface{})
+		oldSize, newSize := oldDisk["data_disk_size"].(int), newDisk["data_disk_size"].(int)
+		if newSize < oldSize {
+			return nil, fmt.Errorf("tencentcloud_instance data disk %v can not be shrunk from %v to %v", oldDisk["data_disk_id"], oldSize, newSize)
+		}
+		if newSize > oldSize {
+			dataDisks = append(dataDisks, &cvm.DataDisks{
+				DiskId:   common.StringPtr(oldDisk["data_disk_id"].(string)),
+				DiskSize: common.IntPtr(newSize),
+			})
 		}
 	}
+	return dataDisks, nil
+}
 
-	client := m.(*TencentCloudClient).commonConn
+func resourceTencentCloudInstanceDelete(d *schema.ResourceData, m interface{}) error {
+	cvmConn := m.(*TencentCloudClient).cvmConn
 
-	params := map[string]string{
-		"Version":       "2017-03-12",
-		"Action":        "TerminateInstances",
-		"InstanceIds.0": d.Id(),
+	req := cvm.NewTerminateInstancesRequest()
+	req.InstanceIds = []*string{common.StringPtr(d.Id())}
+	if _, err := cvmConn.TerminateInstances(req); err != nil {
+		return fmt.Errorf("delete instance %v error: %v", d.Id(), err)
 	}
 
-	resource.Retry(3*time.Minute, func() *resource.RetryError {
-		response, err := client.SendRequest("cvm", params)
-		if err != nil {
-			return resource.NonRetryableError(err)
-		}
-		var jsonresp struct {
-			Response struct {
-				Error struct {
-					Code    string `json:"Code"`
-					Message string `json:"Message"`
-				}
-				RequestId string
-			}
-		}
-		err = json.Unmarshal([]byte(response), &jsonresp)
+	// a prepaid instance is isolated in the recycle bin at first, it is
+	// released when it expires or is destroyed again in the console
+	if d.Get("instance_charge_type").(string) == tencentCloudApiInstanceChargeTypePrePaid {
+		isolated, err := waitInstanceIsolated(cvmConn, d.Id(), d.Timeout(schema.TimeoutDelete))
 		if err != nil {
-			return resource.NonRetryableError(err)
+			return err
 		}
-		if jsonresp.Response.Error.Code == "InternalError" {
-			return resource.RetryableError(fmt.Errorf(jsonresp.Response.Error.Message))
+		if isolated {
+			log.Printf("[WARN] prepaid instance %v is isolated in the recycle bin and will be released later, "+
+				"the remaining period is refunded according to the refund policy of CVM", d.Id())
 		}
+		d.SetId("")
 		return nil
-	})
+	}
+
+	if err := waitInstanceTerminated(cvmConn, d.Id(), d.Timeout(schema.TimeoutDelete)); err != nil {
+		return err
+	}
 	d.SetId("")
 	return nil
 }
@@ -1,9 +1,12 @@
 package tencentcloud
 
 import (
+	"encoding/base64"
 	"fmt"
+	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
@@ -30,6 +33,12 @@ func TestAccTencentCloudInstance_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "data_disks.0.data_disk_size", "50"),
 				),
 			},
+			{
+				ResourceName:            "tencentcloud_instance.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password", "disable_security_service", "disable_monitor_service", "instance_charge_type_prepaid_renewals"},
+			},
 		},
 	})
 }
@@ -122,6 +131,414 @@ func TestAccTencentCloudInstance_vpc(t *testing.T) {
 	})
 }
 
+func TestUnitTencentCloudInstance_vpc(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "instance", "tencentcloud_instance"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitInstanceConfigWithVPC("tf_unit_test")),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "instance", "tencentcloud_instance.vpc_ins"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.vpc_ins", "instance_status", "RUNNING"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.vpc_ins", "system_disk_type", "CLOUD_BASIC"),
+					resource.TestCheckResourceAttrSet("tencentcloud_instance.vpc_ins", "private_ip"),
+					resource.TestCheckResourceAttrPair("tencentcloud_instance.vpc_ins", "subnet_id", "tencentcloud_subnet.my_subnet", "id"),
+				),
+			},
+			{
+				Config: m.Config(testUnitInstanceConfigWithVPC("tf_unit_test_renamed")),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "instance", "tencentcloud_instance.vpc_ins"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.vpc_ins", "instance_name", "tf_unit_test_renamed"),
+				),
+			},
+			{
+				Config:                  m.Config(testUnitInstanceConfigWithVPC("tf_unit_test_renamed")),
+				ResourceName:            "tencentcloud_instance.vpc_ins",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password", "disable_security_service", "disable_monitor_service", "instance_charge_type_prepaid_renewals"},
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudInstance_instanceType(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "instance", "tencentcloud_instance"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitInstanceConfigInstanceType("S1.SMALL1")),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "instance", "tencentcloud_instance.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_type", "S1.SMALL1"),
+				),
+			},
+			{
+				Config: m.Config(testUnitInstanceConfigInstanceType("S1.MEDIUM2")),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "instance", "tencentcloud_instance.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_type", "S1.MEDIUM2"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_status", "RUNNING"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudInstance_disks(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	var dataDiskId string
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "instance", "tencentcloud_instance"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitInstanceConfigDisks(50, 100)),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "instance", "tencentcloud_instance.foo"),
+					resource.TestCheckResourceAttrSet("tencentcloud_instance.foo", "system_disk_id"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "data_disks.#", "1"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "data_disks.0.data_disk_size", "100"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "data_disks.0.snapshot_id", "snap-mock"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "data_disks.0.delete_with_instance", "false"),
+					func(s *terraform.State) error {
+						dataDiskId = s.RootModule().Resources["tencentcloud_instance.foo"].Primary.Attributes["data_disks.0.data_disk_id"]
+						if dataDiskId == "" {
+							return fmt.Errorf("data_disks.0.data_disk_id is not set")
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: m.Config(testUnitInstanceConfigDisks(60, 200)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "system_disk_size", "60"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "data_disks.0.data_disk_size", "200"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_status", "RUNNING"),
+					func(s *terraform.State) error {
+						id := s.RootModule().Resources["tencentcloud_instance.foo"].Primary.Attributes["data_disks.0.data_disk_id"]
+						if id != dataDiskId {
+							return fmt.Errorf("data disk is replaced, expect %v, got %v", dataDiskId, id)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:      m.Config(testUnitInstanceConfigDisks(60, 100)),
+				ExpectError: regexp.MustCompile("can not be shrunk"),
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudInstance_prepaid(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	var instanceId string
+	resource.UnitTest(t, resource.TestCase{
+		Providers: m.Providers(),
+		CheckDestroy: func(s *terraform.State) error {
+			ins, ok := m.instances[instanceId]
+			if !ok || ins.state != "SHUTDOWN" {
+				return fmt.Errorf("prepaid instance %v is not isolated", instanceId)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitInstanceConfigChargeType(tencentCloudApiInstanceChargeTypePostPaidByHour, "")),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "instance", "tencentcloud_instance.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_charge_type", tencentCloudApiInstanceChargeTypePostPaidByHour),
+					func(s *terraform.State) error {
+						instanceId = s.RootModule().Resources["tencentcloud_instance.foo"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				Config: m.Config(testUnitInstanceConfigChargeType(tencentCloudApiInstanceChargeTypePrePaid, `
+  instance_charge_type_prepaid_period     = 1
+  instance_charge_type_prepaid_renew_flag = "NOTIFY_AND_MANUAL_RENEW"`)),
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["tencentcloud_instance.foo"].Primary.ID; id != instanceId {
+							return fmt.Errorf("instance is replaced, expect %v, got %v", instanceId, id)
+						}
+						return nil
+					},
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_charge_type", tencentCloudApiInstanceChargeTypePrePaid),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_charge_type_prepaid_renew_flag", "NOTIFY_AND_MANUAL_RENEW"),
+				),
+			},
+			{
+				Config: m.Config(testUnitInstanceConfigChargeType(tencentCloudApiInstanceChargeTypePrePaid, `
+  instance_charge_type_prepaid_period     = 3
+  instance_charge_type_prepaid_renew_flag = "NOTIFY_AND_AUTO_RENEW"`)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_charge_type_prepaid_renew_flag", "NOTIFY_AND_AUTO_RENEW"),
+					testUnitCheckInstancePrepaidPeriod(m, &instanceId, 1),
+				),
+			},
+			{
+				Config: m.Config(testUnitInstanceConfigChargeType(tencentCloudApiInstanceChargeTypePrePaid, `
+  instance_charge_type_prepaid_period       = 3
+  instance_charge_type_prepaid_renewals     = 2
+  instance_charge_type_prepaid_renew_period = 6`)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_charge_type_prepaid_renewals", "2"),
+					testUnitCheckInstancePrepaidPeriod(m, &instanceId, 13),
+				),
+			},
+			{
+				Config: m.Config(testUnitInstanceConfigChargeType(tencentCloudApiInstanceChargeTypePrePaid, `
+  instance_charge_type_prepaid_period       = 1
+  instance_charge_type_prepaid_renewals     = 2
+  instance_charge_type_prepaid_renew_period = 6`)),
+				ExpectError: regexp.MustCompile("instance_charge_type_prepaid_period can not be decreased from 3 to 1"),
+			},
+			{
+				Config: m.Config(testUnitInstanceConfigChargeType(tencentCloudApiInstanceChargeTypePrePaid, `
+  instance_charge_type_prepaid_period       = 3
+  instance_charge_type_prepaid_renewals     = 1
+  instance_charge_type_prepaid_renew_period = 6`)),
+				ExpectError: regexp.MustCompile("instance_charge_type_prepaid_renewals can not be decreased from 2 to 1"),
+			},
+			{
+				Config: m.Config(testUnitInstanceConfigChargeType(tencentCloudApiInstanceChargeTypePostPaidByHour, `
+  instance_charge_type_prepaid_renewals = 2`)),
+				ExpectError: regexp.MustCompile("instance_charge_type can only be changed from POSTPAID_BY_HOUR to PREPAID"),
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudInstance_prepaidImport(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	var instanceId string
+	providers := m.Providers()
+	// reimport imports the instance again and applies the configuration of
+	// the first step to it, as if it were imported into a new state
+	reimport := func(s *terraform.State) error {
+		p := providers["tencentcloud"].(*schema.Provider)
+		r := p.ResourcesMap["tencentcloud_instance"]
+		d := r.Data(&terraform.InstanceState{ID: instanceId})
+		imported, err := r.Importer.State(d, p.Meta())
+		if err != nil {
+			return err
+		}
+		state, err := r.Refresh(imported[0].State(), p.Meta())
+		if err != nil {
+			return err
+		}
+		raw, err := config.NewRawConfig(map[string]interface{}{
+			"instance_name":                             "tf_unit_test",
+			"availability_zone":                         "ap-guangzhou-3",
+			"image_id":                                  "img-mock",
+			"instance_charge_type":                      tencentCloudApiInstanceChargeTypePrePaid,
+			"instance_charge_type_prepaid_period":       3,
+			"instance_charge_type_prepaid_renewals":     2,
+			"instance_charge_type_prepaid_renew_period": 6,
+		})
+		if err != nil {
+			return err
+		}
+		diff, err := r.Diff(state, terraform.NewResourceConfig(raw), p.Meta())
+		if err != nil {
+			return err
+		}
+		state, err = r.Apply(state, diff, p.Meta())
+		if err != nil {
+			return err
+		}
+		if renewals := state.Attributes["instance_charge_type_prepaid_renewals"]; renewals != "2" {
+			return fmt.Errorf("expect the renewals of the configuration after import, got %v", renewals)
+		}
+		return nil
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: providers,
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitInstanceConfigChargeType(tencentCloudApiInstanceChargeTypePrePaid, `
+  instance_charge_type_prepaid_period       = 3
+  instance_charge_type_prepaid_renewals     = 2
+  instance_charge_type_prepaid_renew_period = 6`)),
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						instanceId = s.RootModule().Resources["tencentcloud_instance.foo"].Primary.ID
+						return nil
+					},
+					testUnitCheckInstancePrepaidPeriod(m, &instanceId, 3),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_charge_type_prepaid_renewals", "2"),
+					reimport,
+					// the renewals are not paid again
+					testUnitCheckInstancePrepaidPeriod(m, &instanceId, 3),
+				),
+			},
+			{
+				Config:       m.Config(testUnitInstanceConfigChargeType(tencentCloudApiInstanceChargeTypePrePaid, "")),
+				ResourceName: "tencentcloud_instance.foo",
+				ImportState:  true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if renewals := states[0].Attributes["instance_charge_type_prepaid_renewals"]; renewals != "-1" {
+						return fmt.Errorf("expect the renewals of an imported instance to be -1, got %v", renewals)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func testUnitCheckInstancePrepaidPeriod(m *mockCloud, instanceId *string, want int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if period := m.instances[*instanceId].prepaidPeriod; period != want {
+			return fmt.Errorf("expect the instance to be paid for %v months, got %v", want, period)
+		}
+		return nil
+	}
+}
+
+func TestUnitTencentCloudInstance_spot(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "instance", "tencentcloud_instance"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitInstanceConfigChargeType(tencentCloudApiInstanceChargeTypeSpotPaid, `
+  spot_instance_type = "one-time"
+  spot_max_price     = "0.5"`)),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "instance", "tencentcloud_instance.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_charge_type", tencentCloudApiInstanceChargeTypeSpotPaid),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudInstance_userData(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	testCheckMockInstance := func(projectId int) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			ins := m.instances[s.RootModule().Resources["tencentcloud_instance.foo"].Primary.ID]
+			if ins == nil {
+				return fmt.Errorf("instance not found")
+			}
+			if ins.userData != base64.StdEncoding.EncodeToString([]byte("#!/bin/bash\necho hello\n")) {
+				return fmt.Errorf("unexpected user data %q", ins.userData)
+			}
+			if ins.hostName != "tf-unit-test" {
+				return fmt.Errorf("unexpected hostname %q", ins.hostName)
+			}
+			if ins.projectId != projectId {
+				return fmt.Errorf("expect project %v, got %v", projectId, ins.projectId)
+			}
+			if len(m.clientTokens) != 1 {
+				return fmt.Errorf("expect RunInstances to be sent with a client token")
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "instance", "tencentcloud_instance"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitInstanceConfigUserData(1001)),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "instance", "tencentcloud_instance.foo"),
+					testCheckMockInstance(1001),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "private_ip", "10.0.2.10"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "project_id", "1001"),
+				),
+			},
+			{
+				Config: m.Config(testUnitInstanceConfigUserData(1002)),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckMockInstance(1002),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "project_id", "1002"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudInstance_runningFlag(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "instance", "tencentcloud_instance"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitInstanceConfigRunningFlag(false, "S1.SMALL1")),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "instance", "tencentcloud_instance.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "running_flag", "false"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_status", "STOPPED"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "stop_charging_mode", "STOP_CHARGING"),
+				),
+			},
+			{
+				// a stopped instance stays stopped when it is resized
+				Config: m.Config(testUnitInstanceConfigRunningFlag(false, "S1.MEDIUM2")),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_type", "S1.MEDIUM2"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_status", "STOPPED"),
+				),
+			},
+			{
+				Config: m.Config(testUnitInstanceConfigRunningFlag(true, "S1.MEDIUM2")),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "running_flag", "true"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_status", "RUNNING"),
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "stop_charging_mode", "NOT_APPLICABLE"),
+				),
+			},
+			{
+				// the instance stopped out of band is started again
+				PreConfig: func() {
+					m.Lock()
+					defer m.Unlock()
+					for _, ins := range m.instances {
+						ins.state = "STOPPED"
+					}
+				},
+				Config: m.Config(testUnitInstanceConfigRunningFlag(true, "S1.MEDIUM2")),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tencentcloud_instance.foo", "instance_status", "RUNNING"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccTencentCloudInstance_keypair(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() { testAccPreCheck(t) },
@@ -270,11 +687,11 @@ func testAccCheckTencentCloudInstanceExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("Provider Meta is nil")
 		}
 
-		client := provider.Meta().(*TencentCloudClient).commonConn
+		client := provider.Meta().(*TencentCloudClient).cvmConn
 		instanceIds := []string{
 			rs.Primary.ID,
 		}
-		_, err := waitInstanceReachTargetStatus(client, instanceIds, "RUNNING")
+		_, err := waitInstanceReachTargetStatus(client, instanceIds, "RUNNING", defaultInstanceOperationTimeout)
 		if err != nil {
 			return err
 		}
@@ -287,7 +704,7 @@ func testAccCheckInstanceDestroy(s *terraform.State) error {
 }
 
 func testAccCheckInstanceDestroyWithProvider(s *terraform.State, provider *schema.Provider) error {
-	client := provider.Meta().(*TencentCloudClient).commonConn
+	client := provider.Meta().(*TencentCloudClient).cvmConn
 
 	var instanceIds []string
 	for _, rs := range s.RootModule().Resources {
@@ -301,7 +718,7 @@ func testAccCheckInstanceDestroyWithProvider(s *terraform.State, provider *schem
 	// NOTE,
 	// for prepaid instances, STOPPED means terminated process is done
 	// for postpaid instances, not found is expected
-	_, err := waitInstanceReachTargetStatus(client, instanceIds, "STOPPED")
+	_, err := waitInstanceReachTargetStatus(client, instanceIds, "STOPPED", defaultInstanceOperationTimeout)
 	if err != nil {
 		if err.Error() == instanceNotFoundErrorMsg(instanceIds) {
 			return nil
@@ -614,3 +1031,131 @@ resource "tencentcloud_instance" "sg" {
 		rule,
 	)
 }
+
+func testUnitInstanceConfigWithVPC(name string) string {
+	return fmt.Sprintf(`
+resource "tencentcloud_vpc" "my_vpc" {
+  cidr_block = "10.0.0.0/16"
+  name       = "tf_vpc_test"
+}
+
+resource "tencentcloud_subnet" "my_subnet" {
+  vpc_id            = "${tencentcloud_vpc.my_vpc.id}"
+  availability_zone = "ap-guangzhou-3"
+  name              = "tf_test_subnet"
+  cidr_block        = "10.0.2.0/24"
+}
+
+resource "tencentcloud_instance" "vpc_ins" {
+  instance_name     = "%s"
+  availability_zone = "ap-guangzhou-3"
+  image_id          = "img-mock"
+  instance_type     = "S1.SMALL1"
+  vpc_id            = "${tencentcloud_vpc.my_vpc.id}"
+  subnet_id         = "${tencentcloud_subnet.my_subnet.id}"
+
+  timeouts {
+    create = "5m"
+    delete = "5m"
+  }
+}
+`,
+		name,
+	)
+}
+
+func testUnitInstanceConfigInstanceType(instanceType string) string {
+	return fmt.Sprintf(`
+resource "tencentcloud_instance" "foo" {
+  instance_name     = "tf_unit_test"
+  availability_zone = "ap-guangzhou-3"
+  image_id          = "img-mock"
+  instance_type     = "%s"
+}
+`,
+		instanceType,
+	)
+}
+
+func testUnitInstanceConfigDisks(systemDiskSize, dataDiskSize int) string {
+	return fmt.Sprintf(`
+resource "tencentcloud_instance" "foo" {
+  instance_name     = "tf_unit_test"
+  availability_zone = "ap-guangzhou-3"
+  image_id          = "img-mock"
+  system_disk_type  = "CLOUD_BASIC"
+  system_disk_size  = %d
+
+  data_disks {
+    data_disk_type       = "CLOUD_BASIC"
+    data_disk_size       = %d
+    snapshot_id          = "snap-mock"
+    delete_with_instance = false
+  }
+}
+`,
+		systemDiskSize,
+		dataDiskSize,
+	)
+}
+
+func testUnitInstanceConfigChargeType(chargeType, extra string) string {
+	return fmt.Sprintf(`
+resource "tencentcloud_instance" "foo" {
+  instance_name        = "tf_unit_test"
+  availability_zone    = "ap-guangzhou-3"
+  image_id             = "img-mock"
+  instance_charge_type = "%s"
+  %s
+}
+`,
+		chargeType,
+		extra,
+	)
+}
+
+func testUnitInstanceConfigUserData(projectId int) string {
+	return fmt.Sprintf(`
+resource "tencentcloud_vpc" "my_vpc" {
+  cidr_block = "10.0.0.0/16"
+  name       = "tf_vpc_test"
+}
+
+resource "tencentcloud_subnet" "my_subnet" {
+  vpc_id            = "${tencentcloud_vpc.my_vpc.id}"
+  availability_zone = "ap-guangzhou-3"
+  name              = "tf_test_subnet"
+  cidr_block        = "10.0.2.0/24"
+}
+
+resource "tencentcloud_instance" "foo" {
+  instance_name      = "tf_unit_test"
+  availability_zone  = "ap-guangzhou-3"
+  image_id           = "img-mock"
+  vpc_id             = "${tencentcloud_vpc.my_vpc.id}"
+  subnet_id          = "${tencentcloud_subnet.my_subnet.id}"
+  private_ip         = "10.0.2.10"
+  hostname           = "tf-unit-test"
+  project_id         = %d
+  placement_group_id = "ps-mock"
+  user_data_raw      = "#!/bin/bash\necho hello\n"
+}
+`,
+		projectId,
+	)
+}
+
+func testUnitInstanceConfigRunningFlag(running bool, instanceType string) string {
+	return fmt.Sprintf(`
+resource "tencentcloud_instance" "foo" {
+  instance_name     = "tf_unit_test"
+  availability_zone = "ap-guangzhou-3"
+  image_id          = "img-mock"
+  instance_type     = "%s"
+  running_flag      = %v
+  stopped_mode      = "STOP_CHARGING"
+}
+`,
+		instanceType, running,
+	)
+}
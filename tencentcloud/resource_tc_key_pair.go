@@ -2,6 +2,7 @@ package tencentcloud
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -134,6 +135,7 @@ func resourceTencentCloudKeyPairRead(d *schema.ResourceData, meta interface{}) e
 	keyName, _, err := findKeyPairById(client, id)
 	if err != nil {
 		if err == errKeyPairNotFound {
+			log.Printf("[WARN] key pair %v not found, removing from state", id)
 			d.SetId("")
 			return nil
 		}
@@ -155,15 +157,18 @@ func resourceTencentCloudKeyPairDelete(d *schema.ResourceData, meta interface{})
 		"KeyIds.0": id,
 	}
 	client := meta.(*TencentCloudClient).commonConn
-	resource.Retry(3*time.Minute, func() *resource.RetryError {
+	return resource.Retry(3*time.Minute, func() *resource.RetryError {
 		_, bindedInstanceIds, err := findKeyPairById(client, id)
-		if err != nil && err != errKeyPairNotFound {
+		if err == errKeyPairNotFound {
+			return nil
+		}
+		if err != nil {
 			return resource.NonRetryableError(err)
 		}
 		if len(bindedInstanceIds) > 0 {
 			var stillUnbinedInstanceIds []string
 			for _, insId := range bindedInstanceIds {
-				if err := unbindKeyPiar(client, insId, id); err != nil {
+				if err := unbindKeyPiar(client, meta.(*TencentCloudClient).cvmConn, insId, id, defaultInstanceOperationTimeout); err != nil {
 					stillUnbinedInstanceIds = append(stillUnbinedInstanceIds, insId)
 				}
 			}
@@ -174,7 +179,7 @@ func resourceTencentCloudKeyPairDelete(d *schema.ResourceData, meta interface{})
 					stillUnbinedInstanceIds,
 				)
 				log.Printf("[DEBUG] %v", s)
-				err = fmt.Errorf(s)
+				err = errors.New(s)
 				return resource.RetryableError(err)
 			}
 		}
@@ -196,9 +201,6 @@ func resourceTencentCloudKeyPairDelete(d *schema.ResourceData, meta interface{})
 		if err != nil {
 			return resource.NonRetryableError(err)
 		}
-		if retryable(jsonresp.Response.Error.Code, jsonresp.Response.Error.Message) {
-			return resource.RetryableError(fmt.Errorf(jsonresp.Response.Error.Message))
-		}
 		if jsonresp.Response.Error.Code != "" {
 			err = fmt.Errorf(
 				"tencentcloud_key_pair got error, code:%v, message:%v",
@@ -209,5 +211,4 @@ func resourceTencentCloudKeyPairDelete(d *schema.ResourceData, meta interface{})
 		}
 		return nil
 	})
-	return nil
 }
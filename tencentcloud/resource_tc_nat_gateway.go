@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/zqfan/tencentcloud-sdk-go/common"
@@ -19,6 +20,14 @@ func resourceTencentCloudNatGateway() *schema.Resource {
 		Read:   resourceTencentCloudNatGatewayRead,
 		Update: resourceTencentCloudNatGatewayUpdate,
 		Delete: resourceTencentCloudNatGatewayDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(3 * time.Minute),
+			Update: schema.DefaultTimeout(3 * time.Minute),
+			Delete: schema.DefaultTimeout(3 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"vpc_id": &schema.Schema{
@@ -48,6 +57,7 @@ func resourceTencentCloudNatGateway() *schema.Resource {
 				MinItems: 1,
 				MaxItems: 10,
 			},
+			"tags": tagsSchema(),
 		},
 	}
 }
@@ -82,14 +92,14 @@ func resourceTencentCloudNatGatewayCreate(d *schema.ResourceData, meta interface
 	}
 
 	//Polling NAT gateway production status
-	if _, err := client.PollingVpcBillResult(response.BillId); err != nil {
+	if _, err := client.PollingVpcBillResult(response.BillId, d.Timeout(schema.TimeoutCreate)); err != nil {
 		return err
 	}
 
 	log.Printf("[DEBUG] conn.CreateNatGateway NatGatewayId: %s", *response.NatGatewayId)
 
 	d.SetId(*response.NatGatewayId)
-	return nil
+	return updateResourceTags(client, d, "vpc", "nat")
 }
 
 func resourceTencentCloudNatGatewayRead(d *schema.ResourceData, meta interface{}) error {
@@ -102,17 +112,30 @@ func resourceTencentCloudNatGatewayRead(d *schema.ResourceData, meta interface{}
 	descResp, err := conn.DescribeNatGateway(descReq)
 	b, _ := json.Marshal(descResp)
 	log.Printf("[DEBUG] conn.DescribeNatGateway response: %s", b)
-	if _, ok := err.(*common.APIError); ok {
+	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] nat gateway %v not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
 		return fmt.Errorf("conn.DescribeNatGateway error: %v", err)
 	}
+	if len(descResp.Data) == 0 {
+		log.Printf("[WARN] nat gateway %v not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 
 	nat := descResp.Data[0]
 
+	if nat.UnVpcId != nil {
+		d.Set("vpc_id", *nat.UnVpcId)
+	}
 	d.Set("name", *nat.NatName)
 	d.Set("max_concurrent", *nat.MaxConcurrent)
 	d.Set("bandwidth", *nat.Bandwidth)
-	d.Set("assigned_eip_set", nat.EipSet)
-	return nil
+	d.Set("assigned_eip_set", common.StringValues(nat.EipSet))
+	return readResourceTags(meta.(*TencentCloudClient), d, "vpc", "nat")
 }
 
 func resourceTencentCloudNatGatewayUpdate(d *schema.ResourceData, meta interface{}) error {
@@ -182,7 +205,7 @@ func resourceTencentCloudNatGatewayUpdate(d *schema.ResourceData, meta interface
 			return fmt.Errorf("conn.UpgradeNatGateway error: %v", err)
 		}
 
-		if _, err := client.PollingVpcBillResult(upgradeResp.BillId); err != nil {
+		if _, err := client.PollingVpcBillResult(upgradeResp.BillId, d.Timeout(schema.TimeoutUpdate)); err != nil {
 			return err
 		}
 	}
@@ -211,7 +234,7 @@ func resourceTencentCloudNatGatewayUpdate(d *schema.ResourceData, meta interface
 					return fmt.Errorf("conn.EipUnBindNatGateway error: %v", err)
 				}
 
-				if _, err := client.PollingVpcTaskResult(unbindResp.TaskId); err != nil {
+				if _, err := client.PollingVpcTaskResult(unbindResp.TaskId, d.Timeout(schema.TimeoutUpdate)); err != nil {
 					return err
 				}
 			}
@@ -230,7 +253,7 @@ func resourceTencentCloudNatGatewayUpdate(d *schema.ResourceData, meta interface
 					return fmt.Errorf("conn.EipBindNatGateway error: %v", err)
 				}
 
-				if _, err := client.PollingVpcTaskResult(bindResp.TaskId); err != nil {
+				if _, err := client.PollingVpcTaskResult(bindResp.TaskId, d.Timeout(schema.TimeoutUpdate)); err != nil {
 					return err
 				}
 			}
@@ -242,6 +265,13 @@ func resourceTencentCloudNatGatewayUpdate(d *schema.ResourceData, meta interface
 		d.SetPartial("assigned_eip_set")
 	}
 
+	if d.HasChange("tags") {
+		if err := updateResourceTags(client, d, "vpc", "nat"); err != nil {
+			return err
+		}
+		d.SetPartial("tags")
+	}
+
 	d.Partial(false)
 
 	return nil
@@ -262,6 +292,6 @@ func resourceTencentCloudNatGatewayDelete(d *schema.ResourceData, meta interface
 		return fmt.Errorf("[ERROR] client.vpcConn.DeleteNatGateway error: %v", err)
 	}
 
-	_, err = client.PollingVpcTaskResult(deleteResp.TaskId)
+	_, err = client.PollingVpcTaskResult(deleteResp.TaskId, d.Timeout(schema.TimeoutDelete))
 	return err
 }
@@ -38,6 +38,47 @@ func TestAccTencentCloudNatGateway_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("tencentcloud_nat_gateway.my_nat", "assigned_eip_set.#", "2"),
 				),
 			},
+			{
+				ResourceName:      "tencentcloud_nat_gateway.my_nat",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudNatGateway_basic(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "nat", "tencentcloud_nat_gateway"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testAccNatGatewayConfig),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "nat", "tencentcloud_nat_gateway.my_nat"),
+					resource.TestCheckResourceAttr("tencentcloud_nat_gateway.my_nat", "max_concurrent", "3000000"),
+					resource.TestCheckResourceAttr("tencentcloud_nat_gateway.my_nat", "assigned_eip_set.#", "2"),
+				),
+			},
+			{
+				Config: m.Config(testAccNatGatewayConfigUpdate),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "nat", "tencentcloud_nat_gateway.my_nat"),
+					resource.TestCheckResourceAttr("tencentcloud_nat_gateway.my_nat", "name", "new_name"),
+					resource.TestCheckResourceAttr("tencentcloud_nat_gateway.my_nat", "max_concurrent", "10000000"),
+					resource.TestCheckResourceAttr("tencentcloud_nat_gateway.my_nat", "bandwidth", "1000"),
+					resource.TestCheckResourceAttr("tencentcloud_nat_gateway.my_nat", "assigned_eip_set.#", "2"),
+				),
+			},
+			{
+				Config:            m.Config(testAccNatGatewayConfigUpdate),
+				ResourceName:      "tencentcloud_nat_gateway.my_nat",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
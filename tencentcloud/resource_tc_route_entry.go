@@ -133,16 +133,19 @@ func resourceTencentCloudRouteEntryRead(d *schema.ResourceData, m interface{}) e
 
 	log.Printf("[DEBUG] resource_tc_route_entry read params:%v", _route)
 
-	response, err := client.SendRequest("vpc", _route)
+	response, err := sendRequest(client, "vpc", _route)
 	if err != nil {
-		log.Printf("[ERROR] resource_tc_route_entry read json.Unmarshal error:%v", err)
+		// the route table is gone with the route entry
+		if isNotFound(err) {
+			log.Printf("[WARN] route entry %v not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		log.Printf("[ERROR] resource_tc_route_entry read client.SendRequest error:%v", err)
 		return err
 	}
 	var jsonresp struct {
-		Code     int    `json:"code"`
-		Message  string `json:"message"`
-		CodeDesc string `json:"codeDesc"`
-		Data     struct {
+		Data struct {
 			TotalNum int `json:"totalNum"`
 			Data     []struct {
 				DestinationCidrBlock string `json:"destinationCidrBlock"`
@@ -157,9 +160,8 @@ func resourceTencentCloudRouteEntryRead(d *schema.ResourceData, m interface{}) e
 		return err
 	}
 
-	if jsonresp.Code != 0 {
-		return fmt.Errorf("resource_tc_route_entry read error, code:%v, message:%v, CodeDesc:%v", jsonresp.Code, jsonresp.Message, jsonresp.CodeDesc)
-	} else if jsonresp.Data.TotalNum <= 0 || len(jsonresp.Data.Data) <= 0 {
+	if jsonresp.Data.TotalNum <= 0 || len(jsonresp.Data.Data) <= 0 {
+		log.Printf("[WARN] route entry %v not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
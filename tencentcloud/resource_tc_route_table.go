@@ -2,6 +2,7 @@ package tencentcloud
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
@@ -14,6 +15,9 @@ func resourceTencentCloudRouteTable() *schema.Resource {
 		Read:   resourceTencentCloudRouteTableRead,
 		Update: resourceTencentCloudRouteTableUpdate,
 		Delete: resourceTencentCloudRouteTableDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"vpc_id": &schema.Schema{
@@ -63,19 +67,25 @@ func resourceTencentCloudRouteTableRead(d *schema.ResourceData, m interface{}) e
 	client := m.(*TencentCloudClient).commonConn
 	params := map[string]string{
 		"Action":       "DescribeRouteTable",
-		"vpcId":        d.Get("vpc_id").(string),
 		"routeTableId": d.Id(),
 	}
-	response, err := client.SendRequest("vpc", params)
+	// vpc_id is unknown when the route table is being imported
+	if vpcId := d.Get("vpc_id").(string); vpcId != "" {
+		params["vpcId"] = vpcId
+	}
+	response, err := sendRequest(client, "vpc", params)
 	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] route table %v not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
 	var jsonresp struct {
-		Code     int    `json:"code"`
-		Message  string `json:"message"`
-		CodeDesc string `json:"codeDesc"`
-		Data     []struct {
+		Data []struct {
+			UnVpcId        string `json:"unVpcId"`
 			RouteTableName string `json:"routeTableName"`
 		} `json:"data"`
 	}
@@ -84,13 +94,13 @@ func resourceTencentCloudRouteTableRead(d *schema.ResourceData, m interface{}) e
 		log.Printf("[ERROR] resource_tc_route_table read json.Unmarshal error:%v", err)
 		return err
 	}
-	if jsonresp.Code != 0 {
-		return fmt.Errorf("resource_tc_route_table read error, code:%v, message:%v, CodeDesc:%v", jsonresp.Code, jsonresp.Message, jsonresp.CodeDesc)
-	} else if jsonresp.CodeDesc == "InvalidRouteTableId.NotFound" {
+	if len(jsonresp.Data) == 0 {
+		log.Printf("[WARN] route table %v not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
+	d.Set("vpc_id", jsonresp.Data[0].UnVpcId)
 	d.Set("name", jsonresp.Data[0].RouteTableName)
 
 	return nil
@@ -162,7 +172,7 @@ func resourceTencentCloudRouteTableDelete(d *schema.ResourceData, m interface{})
 	if jsonresp.Code != 0 {
 		log.Printf("[DEBUG] resource_tc_route_table delete error, code:%v, message:%v, CodeDesc:%v", jsonresp.Code, jsonresp.Message, jsonresp.CodeDesc)
 		if jsonresp.CodeDesc != "InvalidRouteTableId.NotFound" {
-			return fmt.Errorf(jsonresp.Message)
+			return errors.New(jsonresp.Message)
 		}
 	}
 
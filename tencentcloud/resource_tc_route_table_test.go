@@ -25,6 +25,11 @@ func TestAccTencentCloudRouteTable_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("tencentcloud_route_table.foo", "name", "ci-temp-test-rt"),
 				),
 			},
+			{
+				ResourceName:      "tencentcloud_route_table.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	vpcv3 "github.com/zqfan/tencentcloud-sdk-go/services/vpc/v20170312"
 )
 
 var projectId = 0
@@ -20,6 +22,9 @@ func resourceTencentCloudSecurityGroup() *schema.Resource {
 		Read:   resourceTencentCloudSecurityGroupRead,
 		Update: resourceTencentCloudSecurityGroupUpdate,
 		Delete: resourceTencentCloudSecurityGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -32,6 +37,80 @@ func resourceTencentCloudSecurityGroup() *schema.Resource {
 				Optional:     true,
 				ValidateFunc: validateStringLengthInRange(2, 100),
 			},
+			"ingress": securityGroupPoliciesSchema(),
+			"egress":  securityGroupPoliciesSchema(),
+			"tags":    tagsSchema(),
+		},
+	}
+}
+
+// securityGroupPoliciesSchema returns the schema of the ingress or egress
+// rules of a security group. It is a list rather than a set, because the
+// order of the rules is their priority.
+//
+// Once it is set, the list is authoritative and the rules not in it are
+// deleted. An empty list deletes all the rules of the direction.
+//
+// It is computed when it is not set, so the rules managed by
+// tencentcloud_security_group_rule are left alone. Removing all the blocks
+// is the same as not setting it.
+func securityGroupPoliciesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"cidr_ip": &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateSecurityGroupRuleCidrIp,
+				},
+				"ipv6_cidr_block": &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateCIDRNetworkAddress,
+				},
+				"source_sgid": &schema.Schema{
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"address_template": &schema.Schema{
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem:     securityGroupRuleTemplateResource(),
+				},
+				"ip_protocol": &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateSecurityGroupRuleIpProtocol,
+					DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+						return strings.EqualFold(old, new)
+					},
+				},
+				"port_range": &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateSecurityGroupRulePortRange,
+				},
+				"protocol_template": &schema.Schema{
+					Type:     schema.TypeList,
+					Optional: true,
+					MaxItems: 1,
+					Elem:     securityGroupRuleTemplateResource(),
+				},
+				"policy": &schema.Schema{
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validateSecurityGroupRulePolicy,
+				},
+				"description": &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateStringLengthInRange(1, 100),
+				},
+			},
 		},
 	}
 }
@@ -74,7 +153,18 @@ func resourceTencentCloudSecurityGroupCreate(d *schema.ResourceData, m interface
 	}
 	log.Printf("[DEBUG] SgId=%s", jsonresp.Data.SgId)
 	d.SetId(jsonresp.Data.SgId)
-	return nil
+	if err := updateResourceTags(m.(*TencentCloudClient), d, "cvm", "sg"); err != nil {
+		return err
+	}
+
+	_, hasIngress := d.GetOk("ingress")
+	_, hasEgress := d.GetOk("egress")
+	if hasIngress || hasEgress {
+		if err := updateSecurityGroupPolicies(m.(*TencentCloudClient).vpcV3Conn, d); err != nil {
+			return err
+		}
+	}
+	return resourceTencentCloudSecurityGroupRead(d, m)
 }
 
 func resourceTencentCloudSecurityGroupRead(d *schema.ResourceData, m interface{}) error {
@@ -87,16 +177,19 @@ func resourceTencentCloudSecurityGroupRead(d *schema.ResourceData, m interface{}
 
 	log.Printf("[DEBUG] resource_tc_security_group read params:%v", params)
 
-	response, err := client.SendRequest("dfw", params)
+	response, err := sendRequest(client, "dfw", params)
 	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] security group %v not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
 		log.Printf("[ERROR] resource_tc_security_group read client.SendRequest error:%v", err)
 		return err
 	}
 
 	var jsonresp struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-		Data    struct {
+		Data struct {
 			TotalNum int `json:"totalNum"`
 			Detail   []struct {
 				SgName   string `json:"sgName"`
@@ -109,17 +202,23 @@ func resourceTencentCloudSecurityGroupRead(d *schema.ResourceData, m interface{}
 		log.Printf("[ERROR] resource_tc_security_group read json.Unmarshal error:%v", err)
 		return err
 	}
-	if jsonresp.Code != 0 {
-		log.Printf("[ERROR] resource_tc_security_group read error, code:%v, message:%v", jsonresp.Code, jsonresp.Message)
-		return errors.New(jsonresp.Message)
-	} else if jsonresp.Data.TotalNum <= 0 || len(jsonresp.Data.Detail) <= 0 {
+	if jsonresp.Data.TotalNum <= 0 || len(jsonresp.Data.Detail) <= 0 {
+		log.Printf("[WARN] security group %v not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 	sg := jsonresp.Data.Detail[0]
 	d.Set("name", sg.SgName)
 	d.Set("description", sg.SgRemark)
-	return nil
+
+	set, err := describeSecurityGroupPolicies(m.(*TencentCloudClient).vpcV3Conn, d.Id())
+	if err != nil {
+		return err
+	}
+	d.Set("ingress", flattenSecurityGroupPolicies(set.Ingress))
+	d.Set("egress", flattenSecurityGroupPolicies(set.Egress))
+
+	return readResourceTags(m.(*TencentCloudClient), d, "cvm", "sg")
 }
 
 func resourceTencentCloudSecurityGroupUpdate(d *schema.ResourceData, m interface{}) error {
@@ -170,11 +269,69 @@ func resourceTencentCloudSecurityGroupUpdate(d *schema.ResourceData, m interface
 		}
 	}
 
+	if d.HasChange("ingress") || d.HasChange("egress") {
+		if err := updateSecurityGroupPolicies(m.(*TencentCloudClient).vpcV3Conn, d); err != nil {
+			return err
+		}
+		d.SetPartial("ingress")
+		d.SetPartial("egress")
+	}
+
+	if d.HasChange("tags") {
+		if err := updateResourceTags(m.(*TencentCloudClient), d, "cvm", "sg"); err != nil {
+			return err
+		}
+		d.SetPartial("tags")
+	}
+
 	d.Partial(false)
 
 	return resourceTencentCloudSecurityGroupRead(d, m)
 }
 
+// updateSecurityGroupPolicies replaces all the policies of the security group
+// with the ingress and egress rules at once. The rules of a direction which is
+// not changed are the ones in the cloud when they are replaced, so that the
+// rules added since the plan are kept.
+func updateSecurityGroupPolicies(vpcConn *vpcv3.Client, d *schema.ResourceData) error {
+	var ingress, egress []*vpcv3.SecurityGroupPolicy
+	var err error
+	if d.HasChange("ingress") {
+		ingress, err = expandSecurityGroupPolicies(d.Get("ingress").([]interface{}))
+		if err != nil {
+			return fmt.Errorf("invalid ingress rule: %v", err)
+		}
+	}
+	if d.HasChange("egress") {
+		egress, err = expandSecurityGroupPolicies(d.Get("egress").([]interface{}))
+		if err != nil {
+			return fmt.Errorf("invalid egress rule: %v", err)
+		}
+	}
+	log.Printf("[DEBUG] resource_tc_security_group replace policies of %v, ingress: %v, egress: %v", d.Id(), len(ingress), len(egress))
+	return replaceSecurityGroupPolicies(vpcConn, d.Id(), ingress, egress)
+}
+
+func expandSecurityGroupPolicies(rules []interface{}) ([]*vpcv3.SecurityGroupPolicy, error) {
+	policies := make([]*vpcv3.SecurityGroupPolicy, 0, len(rules))
+	for _, rule := range rules {
+		policy, err := expandSecurityGroupPolicy(rule.(map[string]interface{}))
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+func flattenSecurityGroupPolicies(policies []*vpcv3.SecurityGroupPolicy) []map[string]interface{} {
+	rules := make([]map[string]interface{}, 0, len(policies))
+	for _, policy := range policies {
+		rules = append(rules, flattenSecurityGroupPolicy(policy))
+	}
+	return rules
+}
+
 func resourceTencentCloudSecurityGroupDelete(d *schema.ResourceData, m interface{}) error {
 	client := m.(*TencentCloudClient).commonConn
 	var sgId = d.Id()
@@ -201,7 +358,7 @@ func resourceTencentCloudSecurityGroupDelete(d *schema.ResourceData, m interface
 				instanceIds,
 			)
 			log.Printf("[DEBUG] %v", s)
-			err = fmt.Errorf(s)
+			err = errors.New(s)
 			return resource.RetryableError(err)
 		}
 
@@ -0,0 +1,206 @@
+package tencentcloud
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// securityGroupAttachmentTarget is what a security group is attached to by a
+// security group attachment resource, e.g. an instance.
+type securityGroupAttachmentTarget struct {
+	// name is the name of the target in messages, e.g. "network interface"
+	name string
+	// key is the argument of the target id, e.g. "network_interface_id"
+	key string
+	// errNotFound is returned by describe when the target does not exist
+	errNotFound error
+
+	describe func(client *TencentCloudClient, targetId string) ([]string, error)
+	attach   func(client *TencentCloudClient, sgId, targetId string) error
+	detach   func(client *TencentCloudClient, sgId, targetId string) error
+}
+
+var securityGroupInstanceTarget = &securityGroupAttachmentTarget{
+	name:        "instance",
+	key:         "instance_id",
+	errNotFound: errInstanceNotFound,
+	describe: func(client *TencentCloudClient, instanceId string) ([]string, error) {
+		return describeInstanceSecurityGroups(client.cvmConn, instanceId)
+	},
+	attach: func(client *TencentCloudClient, sgId, instanceId string) error {
+		return attachSecurityGroupToInstance(client.cvmConn, sgId, instanceId)
+	},
+	detach: func(client *TencentCloudClient, sgId, instanceId string) error {
+		return detachSecurityGroupFromInstance(client.cvmConn, sgId, instanceId)
+	},
+}
+
+var securityGroupNetworkInterfaceTarget = &securityGroupAttachmentTarget{
+	name:        "network interface",
+	key:         "network_interface_id",
+	errNotFound: errNetworkInterfaceNotFound,
+	describe: func(client *TencentCloudClient, networkInterfaceId string) ([]string, error) {
+		return describeNetworkInterfaceSecurityGroups(client.vpcV3Conn, networkInterfaceId)
+	},
+	attach: func(client *TencentCloudClient, sgId, networkInterfaceId string) error {
+		return attachSecurityGroupToNetworkInterface(client.vpcV3Conn, sgId, networkInterfaceId)
+	},
+	detach: func(client *TencentCloudClient, sgId, networkInterfaceId string) error {
+		return detachSecurityGroupFromNetworkInterface(client.vpcV3Conn, sgId, networkInterfaceId)
+	},
+}
+
+var securityGroupLoadBalancerTarget = &securityGroupAttachmentTarget{
+	name:        "load balancer",
+	key:         "loadbalancer_id",
+	errNotFound: errLoadBalancerNotFound,
+	describe: func(client *TencentCloudClient, loadBalancerId string) ([]string, error) {
+		return describeLoadBalancerSecurityGroups(client.clbConn, loadBalancerId)
+	},
+	attach: func(client *TencentCloudClient, sgId, loadBalancerId string) error {
+		return setLoadBalancerSecurityGroup(client.clbConn, sgId, loadBalancerId, "ADD")
+	},
+	detach: func(client *TencentCloudClient, sgId, loadBalancerId string) error {
+		return setLoadBalancerSecurityGroup(client.clbConn, sgId, loadBalancerId, "DEL")
+	},
+}
+
+func resourceTencentCloudSecurityGroupAttachment() *schema.Resource {
+	return resourceTencentCloudSecurityGroupTargetAttachment(securityGroupInstanceTarget)
+}
+
+func resourceTencentCloudSecurityGroupEniAttachment() *schema.Resource {
+	return resourceTencentCloudSecurityGroupTargetAttachment(securityGroupNetworkInterfaceTarget)
+}
+
+func resourceTencentCloudSecurityGroupLbAttachment() *schema.Resource {
+	return resourceTencentCloudSecurityGroupTargetAttachment(securityGroupLoadBalancerTarget)
+}
+
+// resourceTencentCloudSecurityGroupTargetAttachment returns a resource which
+// attaches a security group to the target, the other security groups of the
+// target are left alone.
+func resourceTencentCloudSecurityGroupTargetAttachment(target *securityGroupAttachmentTarget) *schema.Resource {
+	return &schema.Resource{
+		Create: func(d *schema.ResourceData, m interface{}) error {
+			return resourceTencentCloudSecurityGroupAttachmentCreate(target, d, m)
+		},
+		Read: func(d *schema.ResourceData, m interface{}) error {
+			return resourceTencentCloudSecurityGroupAttachmentRead(target, d, m)
+		},
+		Delete: func(d *schema.ResourceData, m interface{}) error {
+			return resourceTencentCloudSecurityGroupAttachmentDelete(target, d, m)
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"security_group_id": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateNotEmpty,
+			},
+			target.key: &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateNotEmpty,
+			},
+		},
+	}
+}
+
+func resourceTencentCloudSecurityGroupAttachmentCreate(target *securityGroupAttachmentTarget, d *schema.ResourceData, m interface{}) error {
+	sgId := d.Get("security_group_id").(string)
+	targetId := d.Get(target.key).(string)
+
+	log.Printf("[DEBUG] security group attachment attach %v to %v %v", sgId, target.name, targetId)
+	if err := target.attach(m.(*TencentCloudClient), sgId, targetId); err != nil {
+		return err
+	}
+
+	d.SetId(buildSecurityGroupAttachmentId(sgId, targetId))
+	return resourceTencentCloudSecurityGroupAttachmentRead(target, d, m)
+}
+
+func resourceTencentCloudSecurityGroupAttachmentRead(target *securityGroupAttachmentTarget, d *schema.ResourceData, m interface{}) error {
+	sgId, targetId, err := parseSecurityGroupAttachmentId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	sgIds, err := target.describe(m.(*TencentCloudClient), targetId)
+	if err != nil {
+		if err == target.errNotFound {
+			log.Printf("[WARN] %v %v of security group attachment %v not found, removing from state", target.name, targetId, d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if !containsSecurityGroup(sgIds, sgId) {
+		log.Printf("[WARN] security group %v is no longer attached to %v %v, removing from state", sgId, target.name, targetId)
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("security_group_id", sgId)
+	d.Set(target.key, targetId)
+	return nil
+}
+
+func resourceTencentCloudSecurityGroupAttachmentDelete(target *securityGroupAttachmentTarget, d *schema.ResourceData, m interface{}) error {
+	sgId, targetId, err := parseSecurityGroupAttachmentId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// the security group may have been detached outside of Terraform
+	sgIds, err := target.describe(m.(*TencentCloudClient), targetId)
+	if err != nil {
+		if err == target.errNotFound {
+			return nil
+		}
+		return err
+	}
+	if !containsSecurityGroup(sgIds, sgId) {
+		return nil
+	}
+
+	log.Printf("[DEBUG] security group attachment detach %v from %v %v", sgId, target.name, targetId)
+	err = target.detach(m.(*TencentCloudClient), sgId, targetId)
+	if err == target.errNotFound {
+		return nil
+	}
+	return err
+}
+
+// The id of a security group attachment is in the format of
+// sgId::targetId, where the target is an instance, a network interface or a
+// load balancer.
+func buildSecurityGroupAttachmentId(sgId, targetId string) string {
+	return sgId + "::" + targetId
+}
+
+func parseSecurityGroupAttachmentId(attachmentId string) (sgId, targetId string, err error) {
+	ids := strings.Split(attachmentId, "::")
+	if len(ids) != 2 || ids[0] == "" || ids[1] == "" {
+		err = fmt.Errorf("Invalid security group attachment ID: %v", attachmentId)
+		return
+	}
+	return ids[0], ids[1], nil
+}
+
+func containsSecurityGroup(sgIds []string, sgId string) bool {
+	for _, id := range sgIds {
+		if id == sgId {
+			return true
+		}
+	}
+	return false
+}
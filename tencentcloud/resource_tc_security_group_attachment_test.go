@@ -0,0 +1,192 @@
+package tencentcloud
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testUnitSecurityGroupAttachmentCases are the targets of the security group
+// attachment resources, each of them has the security groups returned by
+// other before tencentcloud_security_group.app1 and app2 are attached.
+var testUnitSecurityGroupAttachmentCases = []struct {
+	name         string
+	resourceType string
+	key          string
+	targetId     string
+	config       string
+	setup        func(m *mockCloud)
+	other        func(s *terraform.State) []string
+	// groups and setGroups access the security groups of the target in the
+	// mock cloud, the lock of which is held by the caller
+	groups    func(m *mockCloud, targetId string) []string
+	setGroups func(m *mockCloud, targetId string, sgIds []string)
+}{
+	{
+		name:         "instance",
+		resourceType: "tencentcloud_security_group_attachment",
+		key:          "instance_id",
+		targetId:     "${tencentcloud_instance.foo.id}",
+		config: `
+resource "tencentcloud_security_group" "base" {
+  name = "base"
+}
+
+resource "tencentcloud_instance" "foo" {
+  instance_name     = "tf_unit_test"
+  availability_zone = "ap-guangzhou-3"
+  image_id          = "img-mock"
+  security_groups   = ["${tencentcloud_security_group.base.id}"]
+
+  lifecycle {
+    ignore_changes = ["security_groups"]
+  }
+}
+`,
+		setup: func(m *mockCloud) {},
+		other: func(s *terraform.State) []string {
+			return []string{s.RootModule().Resources["tencentcloud_security_group.base"].Primary.ID}
+		},
+		groups: func(m *mockCloud, instanceId string) []string {
+			return m.instances[instanceId].securityGroups
+		},
+		setGroups: func(m *mockCloud, instanceId string, sgIds []string) {
+			m.instances[instanceId].securityGroups = sgIds
+		},
+	},
+	{
+		name:         "network interface",
+		resourceType: "tencentcloud_security_group_eni_attachment",
+		key:          "network_interface_id",
+		targetId:     "eni-mock0001",
+		setup:        func(m *mockCloud) { m.enis["eni-mock0001"] = []string{"sg-other"} },
+		other:        func(*terraform.State) []string { return []string{"sg-other"} },
+		groups:       func(m *mockCloud, eniId string) []string { return m.enis[eniId] },
+		setGroups:    func(m *mockCloud, eniId string, sgIds []string) { m.enis[eniId] = sgIds },
+	},
+	{
+		name:         "load balancer",
+		resourceType: "tencentcloud_security_group_lb_attachment",
+		key:          "loadbalancer_id",
+		targetId:     "lb-mock0001",
+		setup:        func(m *mockCloud) { m.lbs["lb-mock0001"] = []string{"sg-other"} },
+		other:        func(*terraform.State) []string { return []string{"sg-other"} },
+		groups:       func(m *mockCloud, lbId string) []string { return m.lbs[lbId] },
+		setGroups:    func(m *mockCloud, lbId string, sgIds []string) { m.lbs[lbId] = sgIds },
+	},
+}
+
+func TestUnitTencentCloudSecurityGroupAttachments(t *testing.T) {
+	for _, c := range testUnitSecurityGroupAttachmentCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			m := newMockCloud()
+			defer m.Close()
+			c.setup(m)
+
+			groups := `
+resource "tencentcloud_security_group" "app1" {
+  name = "app1"
+}
+
+resource "tencentcloud_security_group" "app2" {
+  name = "app2"
+}
+` + c.config
+			attachments := groups
+			for _, app := range []string{"app1", "app2"} {
+				attachments += fmt.Sprintf(`
+resource "%s" "%s" {
+  security_group_id = "${tencentcloud_security_group.%s.id}"
+  %s = "%s"
+}
+`, c.resourceType, app, app, c.key, c.targetId)
+			}
+
+			var targetId string
+			// check compares the security groups of the target with the other
+			// ones in order, followed by the attached ones in any order
+			check := func(attached ...string) resource.TestCheckFunc {
+				return func(s *terraform.State) error {
+					want := c.other(s)
+					var wantAttached []string
+					for _, app := range attached {
+						wantAttached = append(wantAttached, s.RootModule().Resources["tencentcloud_security_group."+app].Primary.ID)
+					}
+					sort.Strings(wantAttached)
+
+					m.Lock()
+					defer m.Unlock()
+					got := c.groups(m, targetId)
+					if len(got) < len(want) {
+						return fmt.Errorf("expect security groups %v followed by %v, got %v", want, wantAttached, got)
+					}
+					var gotAttached []string
+					gotAttached = append(gotAttached, got[len(want):]...)
+					sort.Strings(gotAttached)
+					if !reflect.DeepEqual(got[:len(want)], want) || !reflect.DeepEqual(gotAttached, wantAttached) {
+						return fmt.Errorf("expect security groups %v followed by %v, got %v", want, wantAttached, got)
+					}
+					return nil
+				}
+			}
+
+			providers := m.Providers()
+			// detach deletes an attachment of a group which is not attached
+			detach := func(s *terraform.State) error {
+				p := providers["tencentcloud"].(*schema.Provider)
+				r := p.ResourcesMap[c.resourceType]
+				d := r.Data(nil)
+				d.SetId(buildSecurityGroupAttachmentId("sg-detached", targetId))
+				return r.Delete(d, p.Meta())
+			}
+
+			resource.UnitTest(t, resource.TestCase{
+				Providers:    providers,
+				CheckDestroy: testUnitCheckMockDestroy(m, "sg", "tencentcloud_security_group"),
+				Steps: []resource.TestStep{
+					{
+						Config: m.Config(attachments),
+						Check: resource.ComposeTestCheckFunc(
+							resource.TestMatchResourceAttr(c.resourceType+".app1", "id", regexp.MustCompile(`^sg-\w+::\w+-\w+$`)),
+							func(s *terraform.State) error {
+								targetId = s.RootModule().Resources[c.resourceType+".app1"].Primary.Attributes[c.key]
+								return nil
+							},
+							check("app1", "app2"),
+							detach,
+						),
+					},
+					{
+						Config:            m.Config(attachments),
+						ResourceName:      c.resourceType + ".app1",
+						ImportState:       true,
+						ImportStateVerify: true,
+					},
+					{
+						// detached outside of Terraform, it is attached again
+						PreConfig: func() {
+							m.Lock()
+							defer m.Unlock()
+							sgIds := c.groups(m, targetId)
+							c.setGroups(m, targetId, sgIds[:len(sgIds)-1])
+						},
+						Config: m.Config(attachments),
+						Check:  check("app1", "app2"),
+					},
+					{
+						// the groups attached elsewhere are left alone
+						Config: m.Config(groups),
+						Check:  check(),
+					},
+				},
+			})
+		})
+	}
+}
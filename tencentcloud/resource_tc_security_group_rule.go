@@ -1,15 +1,14 @@
 package tencentcloud
 
 import (
-	"encoding/json"
-	"errors"
 	"fmt"
 	"log"
 	"regexp"
-	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	vpcv3 "github.com/zqfan/tencentcloud-sdk-go/services/vpc/v20170312"
 )
 
 func resourceTencentCloudSecurityGroupRule() *schema.Resource {
@@ -17,6 +16,9 @@ func resourceTencentCloudSecurityGroupRule() *schema.Resource {
 		Create: resourceTencentCloudSecurityGroupRuleCreate,
 		Read:   resourceTencentCloudSecurityGroupRuleRead,
 		Delete: resourceTencentCloudSecurityGroupRuleDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceTencentCloudSecurityGroupRuleImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"security_group_id": &schema.Schema{
@@ -38,218 +40,409 @@ func resourceTencentCloudSecurityGroupRule() *schema.Resource {
 				},
 			},
 			"cidr_ip": &schema.Schema{
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
-					_, ip_err := validateIp(v, k)
-					log.Printf("[DEBUG] validateIp ip_err:%v", ip_err)
-					if len(ip_err) == 0 {
-						return
-					}
-					_, cidr_err := validateCIDRNetworkAddress(v, k)
-					log.Printf("[DEBUG] validateCIDRNetworkAddress cidr_err:%v", ip_err)
-					if len(cidr_err) == 0 {
-						return
-					}
-					errors = append(errors, fmt.Errorf("%s can be IP, or CIDR, otherwise it's invalid, value:%v", k, v))
-					return
-				},
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"ipv6_cidr_block", "source_sgid", "address_template"},
+				ValidateFunc:  validateSecurityGroupRuleCidrIp,
+			},
+			"ipv6_cidr_block": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"cidr_ip", "source_sgid", "address_template"},
+				ValidateFunc:  validateCIDRNetworkAddress,
+			},
+			"source_sgid": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"cidr_ip", "ipv6_cidr_block", "address_template"},
+			},
+			"address_template": &schema.Schema{
+				Type:          schema.TypeList,
+				Optional:      true,
+				ForceNew:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"cidr_ip", "ipv6_cidr_block", "source_sgid"},
+				Elem:          securityGroupRuleTemplateResource(),
 			},
 			"ip_protocol": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				ForceNew: true,
-				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
-					value := v.(string)
-					value = strings.ToUpper(value)
-					if value != "UDP" && value != "TCP" && value != "ICMP" {
-						errors = append(errors, fmt.Errorf("%s support 'UDP', 'TCP', 'ICMP' and not configured means all protocols. But got %s", k, v))
-					}
-					return
-				},
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateSecurityGroupRuleIpProtocol,
 			},
 			"port_range": &schema.Schema{
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Description: "example: 53、80,443、80-90",
-				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
-					value := v.(string)
-					match, _ := regexp.MatchString("^(\\d{1,5},)*\\d{1,5}$|^\\d{1,5}\\-\\d{1,5}$", value)
-					if !match {
-						errors = append(errors, fmt.Errorf("%s example: 53、80,443、80-90, Not configured to represent all ports", k))
-					}
-					return
-				},
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Description:  "example: 53、80,443、80-90",
+				ValidateFunc: validateSecurityGroupRulePortRange,
+			},
+			"protocol_template": &schema.Schema{
+				Type:          schema.TypeList,
+				Optional:      true,
+				ForceNew:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"ip_protocol", "port_range"},
+				Elem:          securityGroupRuleTemplateResource(),
 			},
 			"policy": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateSecurityGroupRulePolicy,
+			},
+			"description": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateStringLengthInRange(1, 100),
+			},
+		},
+	}
+}
+
+// securityGroupRuleTemplateResource is the schema of an address or protocol
+// template, which is referenced either by the id of a template or by the id
+// of a template group.
+func securityGroupRuleTemplateResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"template_id": &schema.Schema{
 				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
-					value := v.(string)
-					if value != "accept" && value != "drop" {
-						errors = append(errors, fmt.Errorf("Policy of rule, 'accept' or 'drop'"))
-					}
-					return
-				},
+				Optional: true,
+			},
+			"group_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
 			},
 		},
 	}
 }
 
-func resourceTencentCloudSecurityGroupRuleCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*TencentCloudClient).commonConn
-	params := map[string]string{
-		"Action":           "CreateSecurityGroupPolicy",
-		"sgId":             d.Get("security_group_id").(string),
-		"direction":        d.Get("type").(string),
-		"index":            "-1",
-		"policys.0.action": d.Get("policy").(string),
-		"policys.0.cidrIp": d.Get("cidr_ip").(string),
+func validateSecurityGroupRuleCidrIp(v interface{}, k string) (ws []string, errors []error) {
+	_, ip_err := validateIp(v, k)
+	log.Printf("[DEBUG] validateIp ip_err:%v", ip_err)
+	if len(ip_err) == 0 {
+		return
+	}
+	_, cidr_err := validateCIDRNetworkAddress(v, k)
+	log.Printf("[DEBUG] validateCIDRNetworkAddress cidr_err:%v", ip_err)
+	if len(cidr_err) == 0 {
+		return
+	}
+	errors = append(errors, fmt.Errorf("%s can be IP, or CIDR, otherwise it's invalid, value:%v", k, v))
+	return
+}
+
+func validateSecurityGroupRuleIpProtocol(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	value = strings.ToUpper(value)
+	if value != "UDP" && value != "TCP" && value != "ICMP" && value != "ICMPV6" {
+		errors = append(errors, fmt.Errorf("%s support 'UDP', 'TCP', 'ICMP', 'ICMPv6' and not configured means all protocols. But got %s", k, v))
 	}
-	if ip_protocol, ok := d.GetOk("ip_protocol"); ok {
-		params["policys.0.ipProtocol"] = ip_protocol.(string)
+	return
+}
+
+func validateSecurityGroupRulePortRange(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	match, _ := regexp.MatchString("^(\\d{1,5},)*\\d{1,5}$|^\\d{1,5}\\-\\d{1,5}$", value)
+	if !match {
+		errors = append(errors, fmt.Errorf("%s example: 53、80,443、80-90, Not configured to represent all ports", k))
 	}
-	if port_range, ok := d.GetOk("port_range"); ok {
-		params["policys.0.portRange"] = port_range.(string)
+	return
+}
+
+func validateSecurityGroupRulePolicy(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value != "accept" && value != "drop" {
+		errors = append(errors, fmt.Errorf("Policy of rule, 'accept' or 'drop'"))
 	}
+	return
+}
 
-	log.Printf("[DEBUG] resource_tc_security_group_rule create params:%v", params)
+func resourceTencentCloudSecurityGroupRuleCreate(d *schema.ResourceData, m interface{}) error {
+	vpcConn := m.(*TencentCloudClient).vpcV3Conn
+	sgId := d.Get("security_group_id").(string)
+	direction := strings.ToLower(d.Get("type").(string))
 
-	response, err := client.SendRequest("dfw", params)
+	policy, err := securityGroupRulePolicy(d)
 	if err != nil {
-		log.Printf("[ERROR] resource_tc_security_group_rule create client.SendRequest error:%v", err)
 		return err
 	}
-	var jsonresp struct {
-		Code     int    `json:"code"`
-		Message  string `json:"message"`
-		CodeDesc string `json:"codeDesc"`
-	}
-	err = json.Unmarshal([]byte(response), &jsonresp)
-	if err != nil {
-		log.Printf("[ERROR] resource_tc_security_group_rule create json.Unmarshal error:%v", err)
+	log.Printf("[DEBUG] resource_tc_security_group_rule create %v policy of %v: %v", direction, sgId, policy)
+	if err := createSecurityGroupPolicy(vpcConn, sgId, direction, policy); err != nil {
 		return err
 	}
-	if jsonresp.Code != 0 {
-		return fmt.Errorf("resource_tc_security_group_rule create error, code:%v, message:%v, CodeDesc:%v", jsonresp.Code, jsonresp.Message, jsonresp.CodeDesc)
+
+	d.SetId(buildSecurityGroupRuleId(sgId, direction, securityGroupPolicyHash(policy)))
+	return resourceTencentCloudSecurityGroupRuleRead(d, m)
+}
+
+// securityGroupRulePolicy builds the policy of the rule.
+func securityGroupRulePolicy(d *schema.ResourceData) (*vpcv3.SecurityGroupPolicy, error) {
+	rule := make(map[string]interface{})
+	for _, key := range []string{"cidr_ip", "ipv6_cidr_block", "source_sgid", "address_template", "ip_protocol", "port_range", "protocol_template", "policy", "description"} {
+		rule[key] = d.Get(key)
+	}
+	return expandSecurityGroupPolicy(rule)
+}
+
+// expandSecurityGroupPolicy builds a policy from a rule, i.e. the arguments
+// of tencentcloud_security_group_rule or an ingress or egress block of
+// tencentcloud_security_group, an empty value means the key is not set.
+// Exactly one of the sources, i.e. cidr_ip, ipv6_cidr_block, source_sgid and
+// address_template, must be set.
+func expandSecurityGroupPolicy(rule map[string]interface{}) (*vpcv3.SecurityGroupPolicy, error) {
+	policy := &vpcv3.SecurityGroupPolicy{
+		Action: common.StringPtr(strings.ToUpper(rule["policy"].(string))),
+	}
+	sources := 0
+	if v := rule["cidr_ip"].(string); v != "" {
+		policy.CidrBlock = common.StringPtr(v)
+		sources++
+	}
+	if v := rule["ipv6_cidr_block"].(string); v != "" {
+		policy.Ipv6CidrBlock = common.StringPtr(v)
+		sources++
+	}
+	if v := rule["source_sgid"].(string); v != "" {
+		policy.SecurityGroupId = common.StringPtr(v)
+		sources++
+	}
+	if v := rule["address_template"].([]interface{}); len(v) > 0 {
+		id, groupId, err := expandSecurityGroupRuleTemplate(v, "address_template")
+		if err != nil {
+			return nil, err
+		}
+		policy.AddressTemplate = &vpcv3.AddressTemplateSpecification{AddressId: id, AddressGroupId: groupId}
+		sources++
+	}
+	if sources != 1 {
+		return nil, fmt.Errorf("exactly one of cidr_ip, ipv6_cidr_block, source_sgid and address_template must be set")
 	}
 
-	rule := map[string]string{
-		"sgId":       params["sgId"],
-		"direction":  params["direction"],
-		"action":     params["policys.0.action"],
-		"cidrIp":     params["policys.0.cidrIp"],
-		"ipProtocol": "ALL",
-		"portRange":  "ALL",
+	if v := rule["protocol_template"].([]interface{}); len(v) > 0 {
+		if rule["ip_protocol"].(string) != "" || rule["port_range"].(string) != "" {
+			return nil, fmt.Errorf("protocol_template conflicts with ip_protocol and port_range")
+		}
+		id, groupId, err := expandSecurityGroupRuleTemplate(v, "protocol_template")
+		if err != nil {
+			return nil, err
+		}
+		policy.ServiceTemplate = &vpcv3.ServiceTemplateSpecification{ServiceId: id, ServiceGroupId: groupId}
+	} else {
+		if v := rule["ip_protocol"].(string); v != "" {
+			policy.Protocol = common.StringPtr(strings.ToUpper(v))
+		}
+		if v := rule["port_range"].(string); v != "" {
+			policy.Port = common.StringPtr(v)
+		}
+	}
+	if v := rule["description"].(string); v != "" {
+		policy.PolicyDescription = common.StringPtr(v)
+	}
+	return policy, nil
+}
+
+func expandSecurityGroupRuleTemplate(list []interface{}, key string) (id, groupId *string, err error) {
+	if len(list) == 0 || list[0] == nil {
+		return nil, nil, fmt.Errorf("exactly one of template_id and group_id of %v must be set", key)
 	}
-	if ipProtocol, ok := params["policys.0.ipProtocol"]; ok {
-		rule["ipProtocol"] = ipProtocol
+	m := list[0].(map[string]interface{})
+	templateId, group := m["template_id"].(string), m["group_id"].(string)
+	if (templateId == "") == (group == "") {
+		return nil, nil, fmt.Errorf("exactly one of template_id and group_id of %v must be set", key)
 	}
-	if portRange, ok := params["policys.0.portRange"]; ok {
-		rule["portRange"] = portRange
+	if templateId != "" {
+		return common.StringPtr(templateId), nil, nil
 	}
+	return nil, common.StringPtr(group), nil
+}
 
-	uniqSecurityGroupRuleId := buildSecurityGroupRuleId(rule)
-	log.Printf("[DEBUG] uniqSecurityGroupRuleId=%s", uniqSecurityGroupRuleId)
-	d.SetId(uniqSecurityGroupRuleId)
-	return nil
+func flattenSecurityGroupRuleTemplate(id, groupId *string) []map[string]interface{} {
+	if common.StringValue(id) == "" && common.StringValue(groupId) == "" {
+		return nil
+	}
+	return []map[string]interface{}{
+		{
+			"template_id": common.StringValue(id),
+			"group_id":    common.StringValue(groupId),
+		},
+	}
+}
+
+// flattenSecurityGroupPolicy is the reverse of expandSecurityGroupPolicy,
+// the protocol and the policy are in lower case and ALL is left out.
+func flattenSecurityGroupPolicy(policy *vpcv3.SecurityGroupPolicy) map[string]interface{} {
+	lower := func(v *string) string {
+		if strings.EqualFold(common.StringValue(v), "ALL") {
+			return ""
+		}
+		return strings.ToLower(common.StringValue(v))
+	}
+	rule := map[string]interface{}{
+		"cidr_ip":         common.StringValue(policy.CidrBlock),
+		"ipv6_cidr_block": common.StringValue(policy.Ipv6CidrBlock),
+		"source_sgid":     common.StringValue(policy.SecurityGroupId),
+		"ip_protocol":     lower(policy.Protocol),
+		"port_range":      lower(policy.Port),
+		"policy":          lower(policy.Action),
+		"description":     common.StringValue(policy.PolicyDescription),
+	}
+	if t := policy.AddressTemplate; t != nil {
+		rule["address_template"] = flattenSecurityGroupRuleTemplate(t.AddressId, t.AddressGroupId)
+	}
+	if t := policy.ServiceTemplate; t != nil {
+		rule["protocol_template"] = flattenSecurityGroupRuleTemplate(t.ServiceId, t.ServiceGroupId)
+	}
+	return rule
 }
 
 func resourceTencentCloudSecurityGroupRuleRead(d *schema.ResourceData, m interface{}) error {
 	log.Printf("[DEBUG] resource_tc_security_group_rule read id:%v", d.Id())
-	client := m.(*TencentCloudClient).commonConn
-	rule, ok := parseSecurityGroupRuleId(d.Id())
-	if ok == false {
-		return fmt.Errorf("resource_tc_security_group_rule read error, id decode faild! id:%v", d.Id())
-	}
+	vpcConn := m.(*TencentCloudClient).vpcV3Conn
 
-	_, err := describeSecurityGroupRuleIndex(client, rule)
+	sgId, direction, hash, err := resolveSecurityGroupRuleId(vpcConn, d.Id())
+	var policy *vpcv3.SecurityGroupPolicy
+	if err == nil {
+		policy, _, err = describeSecurityGroupPolicyByHash(vpcConn, sgId, direction, hash)
+	}
 	if err != nil {
-		if err == errSecurityGroupRuleNotFound {
+		// the rule is gone with its security group as well
+		if err == errSecurityGroupRuleNotFound || isNotFound(err) {
+			log.Printf("[WARN] security group rule %v not found, removing from state", d.Id())
 			d.SetId("")
 			return nil
 		}
 		return err
 	}
 
-	d.Set("security_group_id", rule["sgId"])
-	d.Set("type", rule["direction"])
-	d.Set("cidr_ip", rule["cidrIp"])
-	ipProtocol := strings.ToLower(rule["ipProtocol"])
-	portRange := strings.ToLower(rule["portRange"])
-	if ipProtocol != "all" {
-		d.Set("ip_protocol", rule["ipProtocol"])
-	}
-	if portRange != "all" {
-		d.Set("port_range", rule["portRange"])
-	}
-	d.Set("policy", rule["action"])
+	// a legacy id is replaced by the one of the content
+	d.SetId(buildSecurityGroupRuleId(sgId, direction, hash))
+	d.Set("security_group_id", sgId)
+	setSecurityGroupRuleString(d, "type", direction)
+	d.Set("cidr_ip", policy.CidrBlock)
+	d.Set("ipv6_cidr_block", policy.Ipv6CidrBlock)
+	d.Set("source_sgid", policy.SecurityGroupId)
+	if t := policy.AddressTemplate; t != nil {
+		d.Set("address_template", flattenSecurityGroupRuleTemplate(t.AddressId, t.AddressGroupId))
+	}
+	if t := policy.ServiceTemplate; t != nil {
+		d.Set("protocol_template", flattenSecurityGroupRuleTemplate(t.ServiceId, t.ServiceGroupId))
+	}
+	setSecurityGroupRuleString(d, "ip_protocol", common.StringValue(policy.Protocol))
+	setSecurityGroupRuleString(d, "port_range", common.StringValue(policy.Port))
+	setSecurityGroupRuleString(d, "policy", common.StringValue(policy.Action))
+	d.Set("description", policy.PolicyDescription)
 
 	return nil
 }
 
-func resourceTencentCloudSecurityGroupRuleDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*TencentCloudClient).commonConn
-	rule, ok := parseSecurityGroupRuleId(d.Id())
-	if ok == false {
-		return fmt.Errorf("resource_tc_security_group_rule read error, id decode faild! id:%v", d.Id())
+// setSecurityGroupRuleString sets a case insensitive attribute in lower case
+// unless it equals the one in the state, ALL means the attribute is not set.
+func setSecurityGroupRuleString(d *schema.ResourceData, key, value string) {
+	if strings.EqualFold(value, "ALL") {
+		value = ""
 	}
-
-	index, err := describeSecurityGroupRuleIndex(client, rule)
-	if err != nil {
-		return err
+	if !strings.EqualFold(d.Get(key).(string), value) {
+		d.Set(key, strings.ToLower(value))
 	}
+}
 
-	params := map[string]string{
-		"Action":    "DeleteSecurityGroupPolicy",
-		"sgId":      rule["sgId"],
-		"direction": rule["direction"],
-		"indexes.0": strconv.Itoa(index),
+// The rule id is in the format of sgId::direction::hash, where hash is the
+// hash of the content of the rule. The id in the format of the query string
+// built by the earlier versions of the provider, e.g.
+// sgId=sg-xxx&direction=ingress&action=accept&cidrIp=10.0.0.0/16&ipProtocol=tcp&portRange=80,
+// is accepted as well and is replaced on read, ipProtocol and portRange can be
+// omitted to match all protocols and ports.
+func resourceTencentCloudSecurityGroupRuleImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if !isLegacySecurityGroupRuleId(d.Id()) {
+		if _, _, _, ok := parseSecurityGroupRuleId(d.Id()); !ok {
+			return nil, fmt.Errorf("resource_tc_security_group_rule import error, id decode faild! id:%v", d.Id())
+		}
+		return []*schema.ResourceData{d}, nil
 	}
+	rule, ok := parseLegacySecurityGroupRuleId(d.Id())
+	if !ok {
+		return nil, fmt.Errorf("resource_tc_security_group_rule import error, id decode faild! id:%v", d.Id())
+	}
+	for _, key := range []string{"sgId", "direction", "action", "cidrIp"} {
+		if rule[key] == "" {
+			return nil, fmt.Errorf("resource_tc_security_group_rule import error, %v is missing in id:%v", key, d.Id())
+		}
+	}
+	return []*schema.ResourceData{d}, nil
+}
 
-	log.Printf("[DEBUG] resource_tc_security_group_rule delete params:%v", params)
-
-	response, err := client.SendRequest("dfw", params)
+func resourceTencentCloudSecurityGroupRuleDelete(d *schema.ResourceData, m interface{}) error {
+	vpcConn := m.(*TencentCloudClient).vpcV3Conn
+	sgId, direction, hash, err := resolveSecurityGroupRuleId(vpcConn, d.Id())
 	if err != nil {
-		log.Printf("[ERROR] resource_tc_security_group_rule delete client.SendRequest error:%v", err)
+		if err == errSecurityGroupRuleNotFound || isNotFound(err) {
+			return nil
+		}
 		return err
 	}
-	var jsonresp struct {
-		Code     int    `json:"code"`
-		Message  string `json:"message"`
-		CodeDesc string `json:"codeDesc"`
+
+	log.Printf("[DEBUG] resource_tc_security_group_rule delete %v policy %v of %v", direction, hash, sgId)
+	return deleteSecurityGroupPolicy(vpcConn, sgId, direction, hash)
+}
+
+func buildSecurityGroupRuleId(sgId, direction, hash string) string {
+	return strings.Join([]string{sgId, direction, hash}, "::")
+}
+
+func parseSecurityGroupRuleId(ruleId string) (sgId, direction, hash string, ok bool) {
+	parts := strings.Split(ruleId, "::")
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return
 	}
-	err = json.Unmarshal([]byte(response), &jsonresp)
-	if err != nil {
-		log.Printf("[ERROR] resource_tc_security_group_rule delete json.Unmarshal error:%v", err)
-		return err
+	direction = strings.ToLower(parts[1])
+	if direction != "ingress" && direction != "egress" {
+		return
 	}
+	return parts[0], direction, parts[2], true
+}
 
-	if jsonresp.Code != 0 {
-		log.Printf("[DEBUG] resource_tc_security_group_rule delete error, code:%v, message:%v, CodeDesc:%v", jsonresp.Code, jsonresp.Message, jsonresp.CodeDesc)
-		return errors.New(jsonresp.Message)
+// resolveSecurityGroupRuleId parses the rule id, a legacy one is resolved to
+// the policy it matches.
+func resolveSecurityGroupRuleId(vpcConn *vpcv3.Client, ruleId string) (sgId, direction, hash string, err error) {
+	if !isLegacySecurityGroupRuleId(ruleId) {
+		var ok bool
+		if sgId, direction, hash, ok = parseSecurityGroupRuleId(ruleId); !ok {
+			err = fmt.Errorf("resource_tc_security_group_rule id decode faild! id:%v", ruleId)
+		}
+		return
 	}
-	return nil
+	rule, ok := parseLegacySecurityGroupRuleId(ruleId)
+	if !ok {
+		err = fmt.Errorf("resource_tc_security_group_rule id decode faild! id:%v", ruleId)
+		return
+	}
+	if rule["ipProtocol"] == "" {
+		rule["ipProtocol"] = "ALL"
+	}
+	if rule["portRange"] == "" {
+		rule["portRange"] = "ALL"
+	}
+	policy, err := describeSecurityGroupPolicyByLegacyRule(vpcConn, rule)
+	if err != nil {
+		return
+	}
+	return rule["sgId"], strings.ToLower(rule["direction"]), securityGroupPolicyHash(policy), nil
 }
 
-// Build an ID for a Security Group Rule
-func buildSecurityGroupRuleId(rule map[string]string) (ruleId string) {
-	log.Printf("[DEBUG] buildSecurityGroupRuleId before: %v", rule)
-	var paramsArray []string
-	for k, v := range rule {
-		paramsArray = append(paramsArray, k+"="+v)
-	}
-	ruleId = strings.Join(paramsArray, "&")
-	log.Printf("[DEBUG] buildSecurityGroupRuleId after: %v", ruleId)
-	return
+func isLegacySecurityGroupRuleId(ruleId string) bool {
+	return strings.Contains(ruleId, "sgId=")
 }
 
-//Parse Security Group Rule ID
-func parseSecurityGroupRuleId(ruleId string) (rule map[string]string, ok bool) {
-	log.Printf("[DEBUG] parseSecurityGroupRuleId before: %v", ruleId)
+// parseLegacySecurityGroupRuleId parses the rule id in the format of a query
+// string built by the earlier versions of the provider.
+func parseLegacySecurityGroupRuleId(ruleId string) (rule map[string]string, ok bool) {
+	log.Printf("[DEBUG] parseLegacySecurityGroupRuleId before: %v", ruleId)
 	ok = true
 	rule = map[string]string{}
 	ruleQueryStrings := strings.Split(ruleId, "&")
@@ -265,6 +458,6 @@ func parseSecurityGroupRuleId(ruleId string) (rule map[string]string, ok bool) {
 		}
 		rule[arr[0]] = arr[1]
 	}
-	log.Printf("[DEBUG] parseSecurityGroupRuleId after: %v", rule)
+	log.Printf("[DEBUG] parseLegacySecurityGroupRuleId after: %v", rule)
 	return
 }
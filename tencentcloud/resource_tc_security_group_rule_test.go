@@ -1,8 +1,8 @@
 package tencentcloud
 
 import (
-	"encoding/json"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
@@ -25,6 +25,11 @@ func TestAccTencentCloudSecurityGroupRule_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("tencentcloud_security_group_rule.http-in", "ip_protocol", "tcp"),
 				),
 			},
+			{
+				ResourceName:      "tencentcloud_security_group_rule.http-in",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -72,38 +77,113 @@ func TestAccTencentCloudSecurityGroupRule_egress(t *testing.T) {
 	})
 }
 
-func testAccCheckSecurityGroupRuleDestroy(id *string) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
-		conn := testAccProvider.Meta().(*TencentCloudClient).commonConn
+func TestUnitTencentCloudSecurityGroupRule_basic(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
 
-		rule, _ := parseSecurityGroupRuleId(*id)
+	var sgId string
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "sg", "tencentcloud_security_group"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitSecurityGroupRuleConfig + testUnitSecurityGroupRuleConfigHttp),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitSaveId("tencentcloud_security_group.foo", &sgId),
+					resource.TestMatchResourceAttr("tencentcloud_security_group_rule.http-in", "id", regexp.MustCompile(`^sg-\w+::ingress::\d+$`)),
+					resource.TestCheckResourceAttr("tencentcloud_security_group_rule.http-in", "ip_protocol", "tcp"),
+					resource.TestCheckResourceAttr("tencentcloud_security_group_rule.http-in", "policy", "accept"),
+					resource.TestCheckResourceAttr("tencentcloud_security_group_rule.from-sg", "description", "from the bar group"),
+					resource.TestCheckNoResourceAttr("tencentcloud_security_group_rule.from-sg", "port_range"),
+					resource.TestCheckResourceAttr("tencentcloud_security_group_rule.ipv6-out", "ipv6_cidr_block", "2001:db8::/32"),
+					resource.TestCheckResourceAttr("tencentcloud_security_group_rule.template-in", "address_template.0.group_id", "ipmg-mock"),
+					resource.TestCheckResourceAttr("tencentcloud_security_group_rule.template-in", "protocol_template.0.template_id", "ppm-mock"),
+				),
+			},
+			{
+				// a policy inserted before the rules out of band shifts
+				// their indexes, they are still found by their content
+				PreConfig: func() {
+					m.Lock()
+					defer m.Unlock()
+					sg := m.sgs[sgId]
+					sg.ingress = append([]map[string]string{{"Protocol": "TCP", "Port": "80,8080", "CidrBlock": "10.0.0.0/8", "Action": "ACCEPT"}}, sg.ingress...)
+					sg.version++
+				},
+				Config: m.Config(testUnitSecurityGroupRuleConfig + testUnitSecurityGroupRuleConfigHttp),
+			},
+			{
+				Config:            m.Config(testUnitSecurityGroupRuleConfig + testUnitSecurityGroupRuleConfigHttp),
+				ResourceName:      "tencentcloud_security_group_rule.template-in",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// only the policy of the rule is deleted
+				Config: m.Config(testUnitSecurityGroupRuleConfig),
+				Check: func(*terraform.State) error {
+					m.Lock()
+					defer m.Unlock()
+					sg := m.sgs[sgId]
+					if len(sg.ingress) != 2 || sg.ingress[0]["CidrBlock"] != "10.0.0.0/8" || sg.ingress[1]["SecurityGroupId"] == "" {
+						return fmt.Errorf("unexpected ingress policies %v", sg.ingress)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
 
-		params := map[string]string{
-			"Action":    "DescribeSecurityGroupEx",
-			"projectId": "0",
-			"sgId":      rule["sgId"],
-		}
-		response, err := conn.SendRequest("dfw", params)
-		if err != nil {
-			return err
-		}
-		var jsonresp struct {
-			Code     int    `json:"code"`
-			CodeDesc string `json:"codeDesc"`
-			Data     struct {
-				TotalNum int `json:"totalNum"`
-			} `json:"data"`
-		}
-		err = json.Unmarshal([]byte(response), &jsonresp)
-		if err != nil {
-			return err
-		}
-		if jsonresp.Data.TotalNum == 0 {
-			return nil
-		}
+func TestUnitTencentCloudSecurityGroupRule_legacyId(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "sg", "tencentcloud_security_group"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitSecurityGroupRuleConfig + testUnitSecurityGroupRuleConfigHttp),
+			},
+			{
+				// the id of the earlier versions is resolved to the new one
+				Config:       m.Config(testUnitSecurityGroupRuleConfig + testUnitSecurityGroupRuleConfigHttp),
+				ResourceName: "tencentcloud_security_group_rule.http-in",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					sgId := s.RootModule().Resources["tencentcloud_security_group.foo"].Primary.ID
+					return fmt.Sprintf("sgId=%s&direction=ingress&action=accept&cidrIp=0.0.0.0/0&ipProtocol=tcp&portRange=80,8080", sgId), nil
+				},
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
 
-		_, err = describeSecurityGroupRuleIndex(conn, rule)
-		if err == errSecurityGroupRuleNotFound {
+func TestUnitTencentCloudSecurityGroupRule_duplicate(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "sg", "tencentcloud_security_group"),
+		Steps: []resource.TestStep{
+			{
+				Config:      m.Config(testUnitSecurityGroupRuleConfig + testUnitSecurityGroupRuleConfigDuplicate),
+				ExpectError: regexp.MustCompile("rule with the same content already exists"),
+			},
+		},
+	})
+}
+
+func testAccCheckSecurityGroupRuleDestroy(id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn := testAccProvider.Meta().(*TencentCloudClient).vpcV3Conn
+
+		sgId, direction, hash, _ := parseSecurityGroupRuleId(*id)
+		_, _, err := describeSecurityGroupPolicyByHash(conn, sgId, direction, hash)
+		if err == errSecurityGroupRuleNotFound || isNotFound(err) {
 			return nil
 		}
 		if err != nil {
@@ -124,9 +204,9 @@ func testAccCheckSecurityGroupRuleExists(n string, id *string) resource.TestChec
 			return fmt.Errorf("No security group ID is set")
 		}
 
-		conn := testAccProvider.Meta().(*TencentCloudClient).commonConn
-		rule, _ := parseSecurityGroupRuleId(rs.Primary.ID)
-		_, err := describeSecurityGroupRuleIndex(conn, rule)
+		conn := testAccProvider.Meta().(*TencentCloudClient).vpcV3Conn
+		sgId, direction, hash, _ := parseSecurityGroupRuleId(rs.Primary.ID)
+		_, _, err := describeSecurityGroupPolicyByHash(conn, sgId, direction, hash)
 		if err != nil {
 			return err
 		}
@@ -180,3 +260,75 @@ resource "tencentcloud_security_group_rule" "egress-drop" {
   policy            = "drop"
 }
 `
+
+const testUnitSecurityGroupRuleConfig = `
+resource "tencentcloud_security_group" "foo" {
+  name = "ci-temp-test-sg"
+}
+
+resource "tencentcloud_security_group" "bar" {
+  name = "ci-temp-test-sg-bar"
+}
+
+resource "tencentcloud_security_group_rule" "from-sg" {
+  security_group_id = "${tencentcloud_security_group.foo.id}"
+  type              = "ingress"
+  source_sgid       = "${tencentcloud_security_group.bar.id}"
+  ip_protocol       = "udp"
+  policy            = "accept"
+  description       = "from the bar group"
+}
+`
+
+const testUnitSecurityGroupRuleConfigHttp = `
+resource "tencentcloud_security_group_rule" "http-in" {
+  security_group_id = "${tencentcloud_security_group.foo.id}"
+  type              = "ingress"
+  cidr_ip           = "0.0.0.0/0"
+  ip_protocol       = "tcp"
+  port_range        = "80,8080"
+  policy            = "accept"
+}
+
+resource "tencentcloud_security_group_rule" "ipv6-out" {
+  security_group_id = "${tencentcloud_security_group.foo.id}"
+  type              = "egress"
+  ipv6_cidr_block   = "2001:db8::/32"
+  ip_protocol       = "ICMPv6"
+  policy            = "drop"
+}
+
+resource "tencentcloud_security_group_rule" "template-in" {
+  security_group_id = "${tencentcloud_security_group.foo.id}"
+  type              = "ingress"
+  policy            = "accept"
+
+  address_template {
+    group_id = "ipmg-mock"
+  }
+
+  protocol_template {
+    template_id = "ppm-mock"
+  }
+}
+`
+
+const testUnitSecurityGroupRuleConfigDuplicate = `
+resource "tencentcloud_security_group_rule" "ssh-in" {
+  security_group_id = "${tencentcloud_security_group.foo.id}"
+  type              = "ingress"
+  cidr_ip           = "0.0.0.0/0"
+  ip_protocol       = "tcp"
+  port_range        = "22"
+  policy            = "accept"
+}
+
+resource "tencentcloud_security_group_rule" "ssh-in-again" {
+  security_group_id = "${tencentcloud_security_group_rule.ssh-in.security_group_id}"
+  type              = "ingress"
+  cidr_ip           = "0.0.0.0/0"
+  ip_protocol       = "TCP"
+  port_range        = "22"
+  policy            = "accept"
+}
+`
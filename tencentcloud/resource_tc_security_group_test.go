@@ -3,10 +3,13 @@ package tencentcloud
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	vpcv3 "github.com/zqfan/tencentcloud-sdk-go/services/vpc/v20170312"
 )
 
 func TestAccTencentCloudSecurityGroup_basic(t *testing.T) {
@@ -24,6 +27,11 @@ func TestAccTencentCloudSecurityGroup_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("tencentcloud_security_group.foo", "name", "ci-temp-test-sg"),
 				),
 			},
+			{
+				ResourceName:      "tencentcloud_security_group.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -54,6 +62,136 @@ func TestAccTencentCloudSecurityGroup_update(t *testing.T) {
 	})
 }
 
+func TestUnitTencentCloudSecurityGroup_rules(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	var sgId string
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "sg", "tencentcloud_security_group"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitSecurityGroupConfigRules(testUnitSecurityGroupRuleHttp+testUnitSecurityGroupRuleSsh, testUnitSecurityGroupRuleDrop)),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitSaveId("tencentcloud_security_group.foo", &sgId),
+					resource.TestCheckResourceAttr("tencentcloud_security_group.foo", "ingress.#", "2"),
+					resource.TestCheckResourceAttr("tencentcloud_security_group.foo", "ingress.0.port_range", "80,443"),
+					resource.TestCheckResourceAttr("tencentcloud_security_group.foo", "ingress.1.description", "ssh from the office"),
+					resource.TestCheckResourceAttr("tencentcloud_security_group.foo", "egress.0.policy", "drop"),
+					testUnitCheckSecurityGroupPolicies(m, &sgId, []string{"80,443", "22"}, []string{"ALL"}),
+				),
+			},
+			{
+				// a rule added in the console is deleted
+				PreConfig: func() {
+					m.Lock()
+					defer m.Unlock()
+					sg := m.sgs[sgId]
+					sg.ingress = append([]map[string]string{{"Protocol": "TCP", "Port": "3389", "CidrBlock": "0.0.0.0/0", "Action": "ACCEPT"}}, sg.ingress...)
+					sg.version++
+				},
+				Config: m.Config(testUnitSecurityGroupConfigRules(testUnitSecurityGroupRuleHttp+testUnitSecurityGroupRuleSsh, testUnitSecurityGroupRuleDrop)),
+				Check:  testUnitCheckSecurityGroupPolicies(m, &sgId, []string{"80,443", "22"}, []string{"ALL"}),
+			},
+			{
+				// the order is the priority
+				Config: m.Config(testUnitSecurityGroupConfigRules(testUnitSecurityGroupRuleSsh+testUnitSecurityGroupRuleHttp, testUnitSecurityGroupRuleDrop)),
+				Check:  testUnitCheckSecurityGroupPolicies(m, &sgId, []string{"22", "80,443"}, []string{"ALL"}),
+			},
+			{
+				Config:            m.Config(testUnitSecurityGroupConfigRules(testUnitSecurityGroupRuleSsh+testUnitSecurityGroupRuleHttp, testUnitSecurityGroupRuleDrop)),
+				ResourceName:      "tencentcloud_security_group.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// egress is kept once it is no longer managed
+				Config: m.Config(testUnitSecurityGroupConfigRules(testUnitSecurityGroupRuleSsh, "")),
+				Check:  testUnitCheckSecurityGroupPolicies(m, &sgId, []string{"22"}, []string{"ALL"}),
+			},
+			{
+				Config: m.Config(`
+resource "tencentcloud_security_group" "foo" {
+  name    = "ci-temp-test-sg"
+  ingress = []
+  egress  = []
+}
+`),
+				Check: testUnitCheckSecurityGroupPolicies(m, &sgId, nil, nil),
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudSecurityGroup_replaceKeepsUnchanged(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	config := Config{
+		SecretId:  "mock-secret-id",
+		SecretKey: "mock-secret-key",
+		Region:    "ap-guangzhou",
+		Endpoints: map[string]string{"vpc": m.server.URL},
+	}
+	meta, err := config.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// the egress rule of port 53 is added after the plan
+	sgId := "sg-mock0001"
+	m.Lock()
+	m.sgs[sgId] = &mockSecurityGroup{
+		id:      sgId,
+		version: 2,
+		egress: []map[string]string{
+			{"Protocol": "ALL", "Port": "ALL", "CidrBlock": "0.0.0.0/0", "Action": "DROP"},
+			{"Protocol": "UDP", "Port": "53", "CidrBlock": "0.0.0.0/0", "Action": "ACCEPT"},
+		},
+	}
+	m.Unlock()
+
+	ingress := []*vpcv3.SecurityGroupPolicy{{
+		Protocol:  common.StringPtr("TCP"),
+		Port:      common.StringPtr("22"),
+		CidrBlock: common.StringPtr("10.0.0.0/8"),
+		Action:    common.StringPtr("ACCEPT"),
+	}}
+	if err := replaceSecurityGroupPolicies(meta.(*TencentCloudClient).vpcV3Conn, sgId, ingress, nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := testUnitCheckSecurityGroupPolicies(m, &sgId, []string{"22"}, []string{"ALL", "53"})(nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testUnitCheckSecurityGroupPolicies checks the ports of the ingress and
+// egress policies of the security group in the mock cloud, in order.
+func testUnitCheckSecurityGroupPolicies(m *mockCloud, sgId *string, ingress, egress []string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		m.Lock()
+		defer m.Unlock()
+		sg, ok := m.sgs[*sgId]
+		if !ok {
+			return fmt.Errorf("security group %v not found", *sgId)
+		}
+		ports := func(policies []map[string]string) (ports []string) {
+			for _, policy := range policies {
+				ports = append(ports, policy["Port"])
+			}
+			return
+		}
+		if got := ports(sg.ingress); !reflect.DeepEqual(got, ingress) {
+			return fmt.Errorf("expect ingress ports %v, got %v", ingress, got)
+		}
+		if got := ports(sg.egress); !reflect.DeepEqual(got, egress) {
+			return fmt.Errorf("expect egress ports %v, got %v", egress, got)
+		}
+		return nil
+	}
+}
+
 func testAccCheckSecurityGroupDestroy(id *string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		conn := testAccProvider.Meta().(*TencentCloudClient).commonConn
@@ -135,3 +273,41 @@ resource "tencentcloud_security_group" "foo" {
   name = "ci-temp-test-sg-updated"
 }
 `
+
+func testUnitSecurityGroupConfigRules(ingress, egress string) string {
+	return fmt.Sprintf(`
+resource "tencentcloud_security_group" "bar" {
+  name = "ci-temp-test-sg-bar"
+}
+
+resource "tencentcloud_security_group" "foo" {
+  name = "ci-temp-test-sg"
+%s%s}
+`, ingress, egress)
+}
+
+const testUnitSecurityGroupRuleHttp = `
+  ingress {
+    cidr_ip     = "0.0.0.0/0"
+    ip_protocol = "TCP"
+    port_range  = "80,443"
+    policy      = "accept"
+  }
+`
+
+const testUnitSecurityGroupRuleSsh = `
+  ingress {
+    source_sgid = "${tencentcloud_security_group.bar.id}"
+    ip_protocol = "tcp"
+    port_range  = "22"
+    policy      = "accept"
+    description = "ssh from the office"
+  }
+`
+
+const testUnitSecurityGroupRuleDrop = `
+  egress {
+    cidr_ip = "10.0.0.0/8"
+    policy  = "drop"
+  }
+`
@@ -2,6 +2,7 @@ package tencentcloud
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -16,6 +17,9 @@ func resourceTencentCloudSubnet() *schema.Resource {
 		Read:   resourceTencentCloudSubnetRead,
 		Update: resourceTencentCloudSubnetUpdate,
 		Delete: resourceTencentCloudSubnetDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"cidr_block": &schema.Schema{
@@ -42,6 +46,7 @@ func resourceTencentCloudSubnet() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"tags": tagsSchema(),
 		},
 	}
 }
@@ -77,25 +82,42 @@ func resourceTencentCloudSubnetCreate(d *schema.ResourceData, m interface{}) err
 	subnet := jsonresp.SubnetSet[0]
 	log.Printf("[DEBUG] UnSubnetId=%s", subnet.UnSubnetId)
 	d.SetId(subnet.UnSubnetId)
-	return nil
+	return updateResourceTags(m.(*TencentCloudClient), d, "vpc", "subnet")
 }
 
 func resourceTencentCloudSubnetRead(d *schema.ResourceData, m interface{}) error {
 	client := m.(*TencentCloudClient).commonConn
+	vpcId := d.Get("vpc_id").(string)
+	if vpcId == "" {
+		// the subnet is being imported
+		var err error
+		vpcId, err = describeSubnetVpcId(client, d.Id())
+		if err != nil {
+			if err == errSubnetNotFound || isNotFound(err) {
+				log.Printf("[WARN] subnet %v not found, removing from state", d.Id())
+				d.SetId("")
+				return nil
+			}
+			return err
+		}
+	}
 	params := map[string]string{
 		"Action":   "DescribeSubnet",
-		"vpcId":    d.Get("vpc_id").(string),
+		"vpcId":    vpcId,
 		"subnetId": d.Id(),
 	}
-	response, err := client.SendRequest("vpc", params)
+	response, err := sendRequest(client, "vpc", params)
 	if err != nil {
+		// the subnet is gone along with its vpc as well
+		if isNotFound(err) {
+			log.Printf("[WARN] subnet %v not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
 	var jsonresp struct {
-		Code         int    `json:"code"`
-		Message      string `json:"message"`
-		CodeDesc     string `json:"codeDesc"`
 		SubnetName   string `json:"subnetName"`
 		CidrBlock    string `json:"cidrBlock"`
 		RouteTableId string `json:"routeTableId"`
@@ -105,18 +127,18 @@ func resourceTencentCloudSubnetRead(d *schema.ResourceData, m interface{}) error
 	if err != nil {
 		return err
 	}
-	if jsonresp.Code != 0 {
-		if jsonresp.CodeDesc == "InvalidSubnet.NotFound" {
-			d.SetId("")
-			return nil
-		}
-		return fmt.Errorf("resource_tc_subnet got error, code:%v, message:%v", jsonresp.Code, jsonresp.Message)
+
+	zone, err := describeZoneById(m.(*TencentCloudClient), jsonresp.ZoneId)
+	if err != nil {
+		return err
 	}
 
+	d.Set("vpc_id", vpcId)
+	d.Set("availability_zone", zone)
 	d.Set("cidr_block", jsonresp.CidrBlock)
 	d.Set("name", jsonresp.SubnetName)
 	d.Set("route_table_id", jsonresp.RouteTableId)
-	return nil
+	return readResourceTags(m.(*TencentCloudClient), d, "vpc", "subnet")
 }
 
 func resourceTencentCloudSubnetUpdate(d *schema.ResourceData, m interface{}) error {
@@ -149,6 +171,13 @@ func resourceTencentCloudSubnetUpdate(d *schema.ResourceData, m interface{}) err
 		d.SetPartial("name")
 	}
 
+	if d.HasChange("tags") {
+		if err := updateResourceTags(m.(*TencentCloudClient), d, "vpc", "subnet"); err != nil {
+			return err
+		}
+		d.SetPartial("tags")
+	}
+
 	d.Partial(false)
 
 	return resourceTencentCloudSubnetRead(d, m)
@@ -176,11 +205,11 @@ func resourceTencentCloudSubnetDelete(d *schema.ResourceData, m interface{}) err
 			return resource.NonRetryableError(err)
 		}
 		if jsonresp.CodeDesc == "InvalidSubnet.CannotDelete" {
-			return resource.RetryableError(fmt.Errorf(jsonresp.Message))
+			return resource.RetryableError(errors.New(jsonresp.Message))
 		} else if jsonresp.CodeDesc == "InvalidVpc.NotFound" || jsonresp.CodeDesc == "InvalidSubnet.NotFound" {
 			log.Printf("[DEBUG] Delete subnet faid failed, CodeDesc:%s, vpcId:%s, subnetId:%s", jsonresp.CodeDesc, params["vpcId"], params["subnetId"])
 		} else if jsonresp.Code != 0 {
-			return resource.NonRetryableError(fmt.Errorf(jsonresp.Message))
+			return resource.NonRetryableError(errors.New(jsonresp.Message))
 		}
 		return nil
 	})
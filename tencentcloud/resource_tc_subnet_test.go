@@ -29,6 +29,11 @@ func TestAccTencentCloudSubnet_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("tencentcloud_subnet.foo", "name", "ci-temp-test-subnet"),
 				),
 			},
+			{
+				ResourceName:      "tencentcloud_subnet.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -61,6 +66,69 @@ func TestAccTencentCloudSubnet_update(t *testing.T) {
 	})
 }
 
+func TestUnitTencentCloudSubnet_update(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "subnet", "tencentcloud_subnet"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testAccSubnetConfig),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "subnet", "tencentcloud_subnet.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_subnet.foo", "cidr_block", "10.0.11.0/24"),
+					resource.TestCheckResourceAttr("tencentcloud_subnet.foo", "name", "ci-temp-test-subnet"),
+				),
+			},
+			{
+				Config: m.Config(testAccSubnetConfigUpdate),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "subnet", "tencentcloud_subnet.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_subnet.foo", "name", "ci-temp-test-subnet-updated"),
+				),
+			},
+			{
+				Config:            m.Config(testAccSubnetConfigUpdate),
+				ResourceName:      "tencentcloud_subnet.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudSubnet_zoneCache(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	config := Config{
+		SecretId:  "mock-secret-id",
+		SecretKey: "mock-secret-key",
+		Region:    "ap-guangzhou",
+		Endpoints: map[string]string{"cvm": m.server.URL},
+	}
+	meta, err := config.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client := meta.(*TencentCloudClient)
+
+	for _, zone := range []string{"ap-guangzhou-3", "ap-guangzhou-4", "ap-guangzhou-3"} {
+		got, err := describeZoneById(client, mockZoneId(zone))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if got != zone {
+			t.Fatalf("expect zone %s, got %s", zone, got)
+		}
+	}
+	if n := m.Calls("DescribeZones"); n != 1 {
+		t.Fatalf("expect zones to be described once, got %d calls", n)
+	}
+}
+
 func testAccCheckSubnetDestroy(vpcId, subnetId *string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		if *vpcId == "" || *subnetId == "" {
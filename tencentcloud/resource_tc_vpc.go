@@ -2,6 +2,7 @@ package tencentcloud
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -16,6 +17,9 @@ func resourceTencentCloudVpc() *schema.Resource {
 		Read:   resourceTencentCloudVpcRead,
 		Update: resourceTencentCloudVpcUpdate,
 		Delete: resourceTencentCloudVpcDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -36,6 +40,7 @@ func resourceTencentCloudVpc() *schema.Resource {
 				Type:     schema.TypeBool,
 				Computed: true,
 			},
+			"tags": tagsSchema(),
 		},
 	}
 }
@@ -65,7 +70,7 @@ func resourceTencentCloudVpcCreate(d *schema.ResourceData, m interface{}) error
 	}
 	log.Printf("[DEBUG] UniqVpcId=%v", jsonresp.UniqVpcId)
 	d.SetId(jsonresp.UniqVpcId)
-	return nil
+	return updateResourceTags(m.(*TencentCloudClient), d, "vpc", "vpc")
 }
 
 func resourceTencentCloudVpcRead(d *schema.ResourceData, m interface{}) error {
@@ -74,16 +79,18 @@ func resourceTencentCloudVpcRead(d *schema.ResourceData, m interface{}) error {
 		"Action": "DescribeVpcEx",
 		"vpcId":  d.Id(),
 	}
-	response, err := client.SendRequest("vpc", params)
+	response, err := sendRequest(client, "vpc", params)
 	if err != nil {
-		d.SetId("")
+		if isNotFound(err) {
+			log.Printf("[WARN] vpc %v not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 
 	var jsonresp struct {
-		Code       int    `json:"code"`
-		Message    string `json:"message"`
-		TotalCount int    `json:"totalCount"`
+		TotalCount int `json:"totalCount"`
 		Data       []struct {
 			VpcName     string `json:"vpcName"`
 			CidrBlock   string `json:"cidrBlock"`
@@ -95,9 +102,8 @@ func resourceTencentCloudVpcRead(d *schema.ResourceData, m interface{}) error {
 	if err != nil {
 		return err
 	}
-	if jsonresp.Code != 0 {
-		return fmt.Errorf("resource_tc_vpc read error, code:%v, message:%v", jsonresp.Code, jsonresp.Message)
-	} else if jsonresp.TotalCount == 0 {
+	if jsonresp.TotalCount == 0 {
+		log.Printf("[WARN] vpc %v not found, removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
@@ -107,7 +113,7 @@ func resourceTencentCloudVpcRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("cidr_block", vpc.CidrBlock)
 	d.Set("is_default", vpc.IsDefault)
 	d.Set("is_multicast", vpc.IsMulticast)
-	return nil
+	return readResourceTags(m.(*TencentCloudClient), d, "vpc", "vpc")
 }
 
 func resourceTencentCloudVpcUpdate(d *schema.ResourceData, m interface{}) error {
@@ -140,6 +146,12 @@ func resourceTencentCloudVpcUpdate(d *schema.ResourceData, m interface{}) error
 			return fmt.Errorf("resource_tc_vpc update error, code:%v, message:%v", jsonresp.Code, jsonresp.Message)
 		}
 	}
+	if d.HasChange("tags") {
+		if err := updateResourceTags(m.(*TencentCloudClient), d, "vpc", "vpc"); err != nil {
+			return err
+		}
+		d.SetPartial("tags")
+	}
 	d.Partial(false)
 	return resourceTencentCloudVpcRead(d, m)
 }
@@ -165,11 +177,11 @@ func resourceTencentCloudVpcDelete(d *schema.ResourceData, m interface{}) error
 			return resource.NonRetryableError(err)
 		}
 		if jsonresp.CodeDesc == "InvalidVpc.CannotDelete" || jsonresp.CodeDesc == "InvalidSubnet.CannotDelete" {
-			return resource.RetryableError(fmt.Errorf(jsonresp.Message))
+			return resource.RetryableError(errors.New(jsonresp.Message))
 		} else if jsonresp.CodeDesc == "InvalidVpc.NotFound" {
 			log.Printf("[DEBUG] Delete vpc faid failed, CodeDesc:InvalidVpc.NotFound, vpcId:%s", params["vpcId"])
 		} else if jsonresp.Code != 0 {
-			return resource.NonRetryableError(fmt.Errorf(jsonresp.Message))
+			return resource.NonRetryableError(errors.New(jsonresp.Message))
 		}
 		return nil
 	})
@@ -0,0 +1,265 @@
+package tencentcloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	vpc "github.com/zqfan/tencentcloud-sdk-go/services/vpc/unversioned"
+)
+
+func resourceTencentCloudVpcPeeringConnection() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTencentCloudVpcPeeringConnectionCreate,
+		Read:   resourceTencentCloudVpcPeeringConnectionRead,
+		Update: resourceTencentCloudVpcPeeringConnectionUpdate,
+		Delete: resourceTencentCloudVpcPeeringConnectionDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(3 * time.Minute),
+			Update: schema.DefaultTimeout(3 * time.Minute),
+			Delete: schema.DefaultTimeout(3 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vpc_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"peer_vpc_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"peer_uin": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"peer_region": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"name": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateStringLengthInRange(1, 60),
+			},
+			"bandwidth": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIntegerInRange(1, 10000),
+			},
+
+			// Computed values
+			"state": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"create_time": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceTencentCloudVpcPeeringConnectionCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*TencentCloudClient)
+	conn := client.vpcConn
+
+	peerRegion := d.Get("peer_region").(string)
+	if peerRegion == "" || peerRegion == client.region {
+		if _, ok := d.GetOk("bandwidth"); ok {
+			return fmt.Errorf("bandwidth is only supported by cross-region peering connections")
+		}
+
+		createReq := vpc.NewCreateVpcPeeringConnectionRequest()
+		createReq.VpcId = common.StringPtr(d.Get("vpc_id").(string))
+		createReq.PeerVpcId = common.StringPtr(d.Get("peer_vpc_id").(string))
+		createReq.PeeringConnectionName = common.StringPtr(d.Get("name").(string))
+		if v, ok := d.GetOk("peer_uin"); ok {
+			createReq.PeerUin = common.StringPtr(v.(string))
+		}
+		createResp, err := conn.CreateVpcPeeringConnection(createReq)
+		b, _ := json.Marshal(createResp)
+		log.Printf("[DEBUG] conn.CreateVpcPeeringConnection response: %s", b)
+		if err != nil {
+			return fmt.Errorf("conn.CreateVpcPeeringConnection error: %v", err)
+		}
+		d.SetId(*createResp.PeeringConnectionId)
+		return resourceTencentCloudVpcPeeringConnectionRead(d, meta)
+	}
+
+	createReq := vpc.NewCreateVpcPeeringConnectionExRequest()
+	createReq.VpcId = common.StringPtr(d.Get("vpc_id").(string))
+	createReq.PeerVpcId = common.StringPtr(d.Get("peer_vpc_id").(string))
+	createReq.PeerRegion = common.StringPtr(peerRegion)
+	createReq.PeeringConnectionName = common.StringPtr(d.Get("name").(string))
+	if v, ok := d.GetOk("peer_uin"); ok {
+		createReq.PeerUin = common.StringPtr(v.(string))
+	}
+	if v, ok := d.GetOk("bandwidth"); ok {
+		createReq.Bandwidth = common.IntPtr(v.(int))
+	}
+	createResp, err := conn.CreateVpcPeeringConnectionEx(createReq)
+	b, _ := json.Marshal(createResp)
+	log.Printf("[DEBUG] conn.CreateVpcPeeringConnectionEx response: %s", b)
+	if err != nil {
+		return fmt.Errorf("conn.CreateVpcPeeringConnectionEx error: %v", err)
+	}
+	d.SetId(*createResp.UniqPeeringConnectionId)
+
+	if _, err := client.PollingVpcTaskResult(createResp.TaskId, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+	return resourceTencentCloudVpcPeeringConnectionRead(d, meta)
+}
+
+func resourceTencentCloudVpcPeeringConnectionRead(d *schema.ResourceData, meta interface{}) error {
+	peering, err := describeVpcPeeringConnection(meta.(*TencentCloudClient).vpcConn, d.Id())
+	if err != nil {
+		if err == errPeeringConnectionNotFound {
+			log.Printf("[WARN] vpc peering connection %v not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if peering.State != nil && *peering.State == vpc.PeeringConnectionStateDeleted {
+		log.Printf("[WARN] vpc peering connection %v is deleted, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	setVpcPeeringConnection(d, peering)
+	return nil
+}
+
+func resourceTencentCloudVpcPeeringConnectionUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*TencentCloudClient)
+	conn := client.vpcConn
+
+	if !d.HasChange("name") && !d.HasChange("bandwidth") {
+		return resourceTencentCloudVpcPeeringConnectionRead(d, meta)
+	}
+
+	peerRegion := d.Get("peer_region").(string)
+	if peerRegion == "" || peerRegion == client.region {
+		if d.HasChange("bandwidth") {
+			return fmt.Errorf("bandwidth is only supported by cross-region peering connections")
+		}
+
+		modifyReq := vpc.NewModifyVpcPeeringConnectionRequest()
+		modifyReq.PeeringConnectionId = common.StringPtr(d.Id())
+		modifyReq.PeeringConnectionName = common.StringPtr(d.Get("name").(string))
+		modifyResp, err := conn.ModifyVpcPeeringConnection(modifyReq)
+		b, _ := json.Marshal(modifyResp)
+		log.Printf("[DEBUG] conn.ModifyVpcPeeringConnection response: %s", b)
+		if err != nil {
+			return fmt.Errorf("conn.ModifyVpcPeeringConnection error: %v", err)
+		}
+		return resourceTencentCloudVpcPeeringConnectionRead(d, meta)
+	}
+
+	modifyReq := vpc.NewModifyVpcPeeringConnectionExRequest()
+	modifyReq.PeeringConnectionId = common.StringPtr(d.Id())
+	modifyReq.PeeringConnectionName = common.StringPtr(d.Get("name").(string))
+	if d.HasChange("bandwidth") {
+		modifyReq.Bandwidth = common.IntPtr(d.Get("bandwidth").(int))
+	}
+	modifyResp, err := conn.ModifyVpcPeeringConnectionEx(modifyReq)
+	b, _ := json.Marshal(modifyResp)
+	log.Printf("[DEBUG] conn.ModifyVpcPeeringConnectionEx response: %s", b)
+	if err != nil {
+		return fmt.Errorf("conn.ModifyVpcPeeringConnectionEx error: %v", err)
+	}
+
+	// only a change of the bandwidth is a task
+	if modifyResp.TaskId != nil {
+		if _, err := client.PollingVpcTaskResult(modifyResp.TaskId, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+	return resourceTencentCloudVpcPeeringConnectionRead(d, meta)
+}
+
+func resourceTencentCloudVpcPeeringConnectionDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*TencentCloudClient)
+	conn := client.vpcConn
+
+	peering, err := describeVpcPeeringConnection(conn, d.Id())
+	if err != nil {
+		if err == errPeeringConnectionNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if !isCrossRegionPeeringConnection(peering) {
+		deleteReq := vpc.NewDeleteVpcPeeringConnectionRequest()
+		deleteReq.PeeringConnectionId = common.StringPtr(d.Id())
+		deleteResp, err := conn.DeleteVpcPeeringConnection(deleteReq)
+		b, _ := json.Marshal(deleteResp)
+		log.Printf("[DEBUG] conn.DeleteVpcPeeringConnection response: %s", b)
+		if err != nil {
+			return fmt.Errorf("conn.DeleteVpcPeeringConnection error: %v", err)
+		}
+		return nil
+	}
+
+	deleteReq := vpc.NewDeleteVpcPeeringConnectionExRequest()
+	deleteReq.PeeringConnectionId = common.StringPtr(d.Id())
+	deleteResp, err := conn.DeleteVpcPeeringConnectionEx(deleteReq)
+	b, _ := json.Marshal(deleteResp)
+	log.Printf("[DEBUG] conn.DeleteVpcPeeringConnectionEx response: %s", b)
+	if err != nil {
+		return fmt.Errorf("conn.DeleteVpcPeeringConnectionEx error: %v", err)
+	}
+	_, err = client.PollingVpcTaskResult(deleteResp.TaskId, d.Timeout(schema.TimeoutDelete))
+	return err
+}
+
+// setVpcPeeringConnection sets the attributes shared by the requester and the
+// accepter of a peering connection.
+func setVpcPeeringConnection(d *schema.ResourceData, peering *vpc.VpcPeeringConnection) {
+	vpcId, peerVpcId := common.StringValue(peering.VpcId), common.StringValue(peering.PeerVpcId)
+	if peering.UnVpcId != nil && *peering.UnVpcId != "" {
+		vpcId = *peering.UnVpcId
+	}
+	if peering.UnPeerVpcId != nil && *peering.UnPeerVpcId != "" {
+		peerVpcId = *peering.UnPeerVpcId
+	}
+	d.Set("vpc_id", vpcId)
+	d.Set("peer_vpc_id", peerVpcId)
+	if peering.PeerUin != nil {
+		d.Set("peer_uin", *peering.PeerUin)
+	}
+	if peering.PeerRegion != nil && *peering.PeerRegion != "" {
+		d.Set("peer_region", *peering.PeerRegion)
+	} else if peering.Region != nil {
+		d.Set("peer_region", *peering.Region)
+	}
+	if peering.PeeringConnectionName != nil {
+		d.Set("name", *peering.PeeringConnectionName)
+	}
+	if peering.Bandwidth != nil {
+		d.Set("bandwidth", *peering.Bandwidth)
+	}
+	if peering.State != nil {
+		d.Set("state", vpcPeeringConnectionStates[*peering.State])
+	}
+	if peering.CreateTime != nil {
+		d.Set("create_time", *peering.CreateTime)
+	}
+}
@@ -0,0 +1,121 @@
+package tencentcloud
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	vpc "github.com/zqfan/tencentcloud-sdk-go/services/vpc/unversioned"
+)
+
+func resourceTencentCloudVpcPeeringConnectionAccepter() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTencentCloudVpcPeeringConnectionAccepterCreate,
+		Read:   resourceTencentCloudVpcPeeringConnectionAccepterRead,
+		Delete: resourceTencentCloudVpcPeeringConnectionAccepterDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(3 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"peering_connection_id": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateNotEmpty,
+			},
+
+			// Computed values
+			"vpc_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"peer_vpc_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"peer_uin": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"peer_region": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"bandwidth": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"state": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"create_time": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceTencentCloudVpcPeeringConnectionAccepterCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*TencentCloudClient)
+	peeringConnectionId := d.Get("peering_connection_id").(string)
+
+	peering, err := describeVpcPeeringConnection(client.vpcConn, peeringConnectionId)
+	if err != nil {
+		return err
+	}
+	if peering.State == nil {
+		return fmt.Errorf("vpc peering connection %v has no state", peeringConnectionId)
+	}
+	switch *peering.State {
+	case vpc.PeeringConnectionStateActive:
+		log.Printf("[DEBUG] vpc peering connection %v is already active", peeringConnectionId)
+	case vpc.PeeringConnectionStatePending:
+		if err := client.acceptVpcPeeringConnection(peering, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("vpc peering connection %v is %v, which can not be accepted", peeringConnectionId, vpcPeeringConnectionStates[*peering.State])
+	}
+
+	d.SetId(peeringConnectionId)
+	return resourceTencentCloudVpcPeeringConnectionAccepterRead(d, meta)
+}
+
+func resourceTencentCloudVpcPeeringConnectionAccepterRead(d *schema.ResourceData, meta interface{}) error {
+	peering, err := describeVpcPeeringConnection(meta.(*TencentCloudClient).vpcConn, d.Id())
+	if err != nil {
+		if err == errPeeringConnectionNotFound {
+			log.Printf("[WARN] vpc peering connection %v not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if peering.State != nil && *peering.State == vpc.PeeringConnectionStateDeleted {
+		log.Printf("[WARN] vpc peering connection %v is deleted, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("peering_connection_id", d.Id())
+	setVpcPeeringConnection(d, peering)
+	return nil
+}
+
+func resourceTencentCloudVpcPeeringConnectionAccepterDelete(d *schema.ResourceData, meta interface{}) error {
+	// an accepted peering connection can only be deleted as a whole, which is
+	// left to the tencentcloud_vpc_peering_connection of the requester
+	log.Printf("[WARN] vpc peering connection %v is kept, only removing the accepter from state", d.Id())
+	return nil
+}
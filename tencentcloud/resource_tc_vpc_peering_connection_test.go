@@ -0,0 +1,181 @@
+package tencentcloud
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	vpc "github.com/zqfan/tencentcloud-sdk-go/services/vpc/unversioned"
+)
+
+func TestUnitTencentCloudVpcPeeringConnection_basic(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "peering", "tencentcloud_vpc_peering_connection"),
+		Steps: []resource.TestStep{
+			{
+				Config:      m.Config(fmt.Sprintf(testUnitVpcPeeringConnectionConfig, "ci-temp-test-pcx", "bandwidth = 10")),
+				ExpectError: regexp.MustCompile("bandwidth is only supported by cross-region peering connections"),
+			},
+			{
+				Config: m.Config(fmt.Sprintf(testUnitVpcPeeringConnectionConfig, "ci-temp-test-pcx", "")),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "peering", "tencentcloud_vpc_peering_connection.foo"),
+					resource.TestCheckResourceAttrPair("tencentcloud_vpc_peering_connection.foo", "vpc_id", "tencentcloud_vpc.foo", "id"),
+					resource.TestCheckResourceAttrPair("tencentcloud_vpc_peering_connection.foo", "peer_vpc_id", "tencentcloud_vpc.bar", "id"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc_peering_connection.foo", "name", "ci-temp-test-pcx"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc_peering_connection.foo", "peer_uin", mockUin),
+					resource.TestCheckResourceAttr("tencentcloud_vpc_peering_connection.foo", "peer_region", "ap-guangzhou"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc_peering_connection.foo", "state", "active"),
+				),
+			},
+			{
+				Config: m.Config(fmt.Sprintf(testUnitVpcPeeringConnectionConfig, "ci-temp-test-pcx-updated", "")),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "peering", "tencentcloud_vpc_peering_connection.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc_peering_connection.foo", "name", "ci-temp-test-pcx-updated"),
+				),
+			},
+			{
+				Config:            m.Config(fmt.Sprintf(testUnitVpcPeeringConnectionConfig, "ci-temp-test-pcx-updated", "")),
+				ResourceName:      "tencentcloud_vpc_peering_connection.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudVpcPeeringConnection_crossRegion(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "peering", "tencentcloud_vpc_peering_connection"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(fmt.Sprintf(testUnitVpcPeeringConnectionConfigCrossRegion, 20)),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "peering", "tencentcloud_vpc_peering_connection.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc_peering_connection.foo", "peer_region", "ap-shanghai"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc_peering_connection.foo", "peer_uin", "100000000002"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc_peering_connection.foo", "bandwidth", "20"),
+					resource.TestCheckResourceAttrPair("tencentcloud_vpc_peering_connection_accepter.bar", "id", "tencentcloud_vpc_peering_connection.foo", "id"),
+					resource.TestCheckResourceAttrPair("tencentcloud_vpc_peering_connection_accepter.bar", "vpc_id", "tencentcloud_vpc.foo", "id"),
+					resource.TestCheckResourceAttrPair("tencentcloud_vpc_peering_connection_accepter.bar", "peer_vpc_id", "tencentcloud_vpc.bar", "id"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc_peering_connection_accepter.bar", "region", "ap-shanghai"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc_peering_connection_accepter.bar", "bandwidth", "20"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc_peering_connection_accepter.bar", "state", "active"),
+					testUnitCheckVpcPeeringConnectionState(m, "tencentcloud_vpc_peering_connection.foo", 1),
+				),
+			},
+			{
+				Config: m.Config(fmt.Sprintf(testUnitVpcPeeringConnectionConfigCrossRegion, 50)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tencentcloud_vpc_peering_connection.foo", "bandwidth", "50"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc_peering_connection.foo", "state", "active"),
+				),
+			},
+			{
+				Config:            m.Config(fmt.Sprintf(testUnitVpcPeeringConnectionConfigCrossRegion, 50)),
+				ResourceName:      "tencentcloud_vpc_peering_connection.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:            m.Config(fmt.Sprintf(testUnitVpcPeeringConnectionConfigCrossRegion, 50)),
+				ResourceName:      "tencentcloud_vpc_peering_connection_accepter.bar",
+				ImportState:       true,
+				ImportStateIdFunc: testUnitRegionalImportId("tencentcloud_vpc_peering_connection_accepter.bar", "ap-shanghai"),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudVpcPeeringConnection_partialResponse(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceTencentCloudVpcPeeringConnection().Schema, map[string]interface{}{})
+	setVpcPeeringConnection(d, &vpc.VpcPeeringConnection{
+		PeeringConnectionId: common.StringPtr("pcx-mock0001"),
+		VpcId:               common.StringPtr("vpc-mock0001"),
+	})
+	if got := d.Get("vpc_id").(string); got != "vpc-mock0001" {
+		t.Fatalf("expect vpc_id vpc-mock0001, got %v", got)
+	}
+	if got := d.Get("state").(string); got != "" {
+		t.Fatalf("expect no state, got %v", got)
+	}
+}
+
+func testUnitCheckVpcPeeringConnectionState(m *mockCloud, n string, state int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource %v not found", n)
+		}
+		m.Lock()
+		defer m.Unlock()
+		peering, ok := m.peerings[rs.Primary.ID]
+		if !ok {
+			return fmt.Errorf("vpc peering connection %v not found in the mock cloud", rs.Primary.ID)
+		}
+		if peering.state != state {
+			return fmt.Errorf("expect state %v of vpc peering connection %v, got %v", state, rs.Primary.ID, peering.state)
+		}
+		return nil
+	}
+}
+
+const testUnitVpcPeeringConnectionConfig = `
+resource "tencentcloud_vpc" "foo" {
+  name       = "ci-temp-test-foo"
+  cidr_block = "10.0.0.0/16"
+}
+
+resource "tencentcloud_vpc" "bar" {
+  name       = "ci-temp-test-bar"
+  cidr_block = "10.1.0.0/16"
+}
+
+resource "tencentcloud_vpc_peering_connection" "foo" {
+  vpc_id      = "${tencentcloud_vpc.foo.id}"
+  peer_vpc_id = "${tencentcloud_vpc.bar.id}"
+  name        = "%s"
+  %s
+}
+`
+
+const testUnitVpcPeeringConnectionConfigCrossRegion = `
+resource "tencentcloud_vpc" "foo" {
+  name       = "ci-temp-test-foo"
+  cidr_block = "10.0.0.0/16"
+}
+
+resource "tencentcloud_vpc" "bar" {
+  region     = "ap-shanghai"
+  name       = "ci-temp-test-bar"
+  cidr_block = "10.1.0.0/16"
+}
+
+resource "tencentcloud_vpc_peering_connection" "foo" {
+  vpc_id      = "${tencentcloud_vpc.foo.id}"
+  peer_vpc_id = "${tencentcloud_vpc.bar.id}"
+  peer_uin    = "100000000002"
+  peer_region = "ap-shanghai"
+  name        = "ci-temp-test-pcx"
+  bandwidth   = %d
+}
+
+resource "tencentcloud_vpc_peering_connection_accepter" "bar" {
+  region                = "ap-shanghai"
+  peering_connection_id = "${tencentcloud_vpc_peering_connection.foo.id}"
+}
+`
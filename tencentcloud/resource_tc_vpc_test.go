@@ -25,6 +25,11 @@ func TestAccTencentCloudVpc_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("tencentcloud_vpc.foo", "name", "ci-temp-test"),
 				),
 			},
+			{
+				ResourceName:      "tencentcloud_vpc.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -57,6 +62,232 @@ func TestAccTencentCloudVpc_update(t *testing.T) {
 	})
 }
 
+func TestUnitTencentCloudVpc_update(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "vpc", "tencentcloud_vpc"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testAccVpcConfig),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "vpc", "tencentcloud_vpc.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc.foo", "cidr_block", "10.0.0.0/16"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc.foo", "name", "ci-temp-test"),
+				),
+			},
+			{
+				Config: m.Config(testAccVpcConfigUpdate),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "vpc", "tencentcloud_vpc.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc.foo", "name", "ci-temp-test-updated"),
+				),
+			},
+			{
+				Config:            m.Config(testAccVpcConfigUpdate),
+				ResourceName:      "tencentcloud_vpc.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudVpc_region(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "vpc", "tencentcloud_vpc"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitVpcConfigRegion),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "vpc", "tencentcloud_vpc.foo"),
+					testUnitCheckMockExists(m, "vpc", "tencentcloud_vpc.bar"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc.foo", "region", "ap-guangzhou"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc.bar", "region", "ap-shanghai"),
+					testUnitCheckVpcRegion(m, "tencentcloud_vpc.foo", "ap-guangzhou"),
+					testUnitCheckVpcRegion(m, "tencentcloud_vpc.bar", "ap-shanghai"),
+				),
+			},
+			{
+				Config:            m.Config(testUnitVpcConfigRegion),
+				ResourceName:      "tencentcloud_vpc.bar",
+				ImportState:       true,
+				ImportStateIdFunc: testUnitRegionalImportId("tencentcloud_vpc.bar", "ap-shanghai"),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// testUnitRegionalImportId returns the import id of a resource in another
+// region than the provider, i.e. {id}@{region}.
+func testUnitRegionalImportId(n, region string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return "", fmt.Errorf("Not found: %s", n)
+		}
+		return rs.Primary.ID + "@" + region, nil
+	}
+}
+
+func TestUnitTencentCloudVpc_disappears(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	var vpcId string
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "vpc", "tencentcloud_vpc"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testAccVpcConfig),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "vpc", "tencentcloud_vpc.foo"),
+					testUnitSaveId("tencentcloud_vpc.foo", &vpcId),
+				),
+			},
+			{
+				// the vpc deleted out of band is created again
+				PreConfig: func() { m.Remove("vpc", vpcId) },
+				Config:    m.Config(testAccVpcConfig),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "vpc", "tencentcloud_vpc.foo"),
+					func(s *terraform.State) error {
+						if id := s.RootModule().Resources["tencentcloud_vpc.foo"].Primary.ID; id == vpcId {
+							return fmt.Errorf("expect the vpc to be created again, got the deleted one %v", id)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudVpc_tags(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	var vpcId string
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "vpc", "tencentcloud_vpc"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.ConfigWithProvider(testUnitVpcProviderDefaultTags, testUnitVpcConfigTags(`env = "test"
+        team = "a"`)),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "vpc", "tencentcloud_vpc.foo"),
+					testUnitSaveId("tencentcloud_vpc.foo", &vpcId),
+					resource.TestCheckResourceAttr("tencentcloud_vpc.foo", "tags.%", "2"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc.foo", "tags.env", "test"),
+					testUnitCheckMockTags(m, "tencentcloud_vpc.foo", "vpc", "vpc", map[string]string{
+						"env":   "test",
+						"team":  "a",
+						"owner": "ci",
+					}),
+				),
+			},
+			{
+				// the default tag comes back when the tag is removed
+				Config: m.ConfigWithProvider(testUnitVpcProviderDefaultTags, testUnitVpcConfigTags(`team = "b"`)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tencentcloud_vpc.foo", "tags.%", "1"),
+					resource.TestCheckResourceAttr("tencentcloud_vpc.foo", "tags.team", "b"),
+					testUnitCheckMockTags(m, "tencentcloud_vpc.foo", "vpc", "vpc", map[string]string{
+						"env":   "default",
+						"team":  "b",
+						"owner": "ci",
+					}),
+				),
+			},
+			{
+				// a default tag deleted outside of Terraform is added back
+				PreConfig: func() {
+					m.Untag(tagResourceName("vpc", "vpc", "ap-guangzhou", vpcId), "owner")
+				},
+				Config: m.ConfigWithProvider(testUnitVpcProviderDefaultTags, testUnitVpcConfigTags(`team = "b"`)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tencentcloud_vpc.foo", "tags.%", "1"),
+					testUnitCheckMockTags(m, "tencentcloud_vpc.foo", "vpc", "vpc", map[string]string{
+						"env":   "default",
+						"team":  "b",
+						"owner": "ci",
+					}),
+				),
+			},
+			{
+				Config:            m.ConfigWithProvider(testUnitVpcProviderDefaultTags, testUnitVpcConfigTags(`team = "b"`)),
+				ResourceName:      "tencentcloud_vpc.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// the tags of the resource are left alone without default tags
+				Config: m.Config(testUnitVpcConfigTags(`team = "b"`)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("tencentcloud_vpc.foo", "tags.%", "1"),
+					testUnitCheckMockTags(m, "tencentcloud_vpc.foo", "vpc", "vpc", map[string]string{
+						"team": "b",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudVpc_tagsDenied(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+	m.Deny("DescribeResourceTagsByResourceIds")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "vpc", "tencentcloud_vpc"),
+		Steps: []resource.TestStep{
+			{
+				// the tags are not required without tags and default tags
+				Config: m.Config(testAccVpcConfig),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "vpc", "tencentcloud_vpc.foo"),
+					resource.TestCheckNoResourceAttr("tencentcloud_vpc.foo", "tags.%"),
+				),
+			},
+		},
+	})
+}
+
+func testUnitSaveId(n string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
+func testUnitCheckVpcRegion(m *mockCloud, n, region string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		if got := m.Region(rs.Primary.ID); got != region {
+			return fmt.Errorf("VPC %s is created in region %q, expected %q", rs.Primary.ID, got, region)
+		}
+		return nil
+	}
+}
+
 const testAccVpcConfig = `
 resource "tencentcloud_vpc" "foo" {
     name = "ci-temp-test"
@@ -71,6 +302,19 @@ resource "tencentcloud_vpc" "foo" {
 }
 `
 
+const testUnitVpcConfigRegion = `
+resource "tencentcloud_vpc" "foo" {
+    name = "ci-temp-test"
+    cidr_block = "10.0.0.0/16"
+}
+
+resource "tencentcloud_vpc" "bar" {
+    name = "ci-temp-test"
+    cidr_block = "10.0.0.0/16"
+    region = "ap-shanghai"
+}
+`
+
 func testAccCheckVpcDestroy(id *string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		if *id == "" {
@@ -139,3 +383,24 @@ func testAccCheckVpcExists(n string, id *string) resource.TestCheckFunc {
 	}
 
 }
+
+const testUnitVpcProviderDefaultTags = `
+    default_tags {
+        tags = {
+            env = "default"
+            owner = "ci"
+        }
+    }
+`
+
+func testUnitVpcConfigTags(tags string) string {
+	return fmt.Sprintf(`
+resource "tencentcloud_vpc" "foo" {
+    name = "ci-temp-test"
+    cidr_block = "10.0.0.0/16"
+    tags = {
+        %s
+    }
+}
+`, tags)
+}
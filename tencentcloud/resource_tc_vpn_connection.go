@@ -0,0 +1,457 @@
+package tencentcloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	vpc "github.com/zqfan/tencentcloud-sdk-go/services/vpc/unversioned"
+)
+
+func resourceTencentCloudVpnConnection() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTencentCloudVpnConnectionCreate,
+		Read:   resourceTencentCloudVpnConnectionRead,
+		Update: resourceTencentCloudVpnConnectionUpdate,
+		Delete: resourceTencentCloudVpnConnectionDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vpc_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"vpn_gateway_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"customer_gateway_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateStringLengthInRange(1, 60),
+			},
+			"pre_share_key": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				Sensitive:    true,
+				ValidateFunc: validateStringLengthInRange(1, 128),
+			},
+			"security_policy": &schema.Schema{
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"local_cidr_block": &schema.Schema{
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateCIDRNetworkAddress,
+						},
+						"remote_cidr_blocks": &schema.Schema{
+							Type:     schema.TypeSet,
+							Required: true,
+							MinItems: 1,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateCIDRNetworkAddress,
+							},
+						},
+					},
+				},
+			},
+			"ike_config": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"proto_encry_algorithm": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "3DES-CBC",
+							ValidateFunc: validateAllowedStringValue([]string{"3DES-CBC", "AES-CBC-128", "AES-CBC-192", "AES-CBC-256", "DES-CBC"}),
+						},
+						"proto_authen_algorithm": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "MD5",
+							ValidateFunc: validateAllowedStringValue([]string{"MD5", "SHA"}),
+						},
+						"exchange_mode": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "main",
+							ValidateFunc: validateAllowedStringValue([]string{"main", "aggressive"}),
+						},
+						"local_identity": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "address",
+							ValidateFunc: validateAllowedStringValue([]string{"address", "fqdn"}),
+						},
+						"remote_identity": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "address",
+							ValidateFunc: validateAllowedStringValue([]string{"address", "fqdn"}),
+						},
+						// the addresses default to the ones of the gateways
+						"local_address": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateIp,
+						},
+						"remote_address": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateIp,
+						},
+						"local_fqdn_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"remote_fqdn_name": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"dh_group_name": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "group1",
+							ValidateFunc: validateAllowedStringValue([]string{"group1", "group2", "group5", "group14", "group24"}),
+						},
+						"sa_lifetime_seconds": &schema.Schema{
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      86400,
+							ValidateFunc: validateIntegerInRange(60, 604800),
+						},
+					},
+				},
+			},
+			"ipsec_config": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"encrypt_algorithm": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "3DES-CBC",
+							ValidateFunc: validateAllowedStringValue([]string{"3DES-CBC", "AES-CBC-128", "AES-CBC-192", "AES-CBC-256", "DES-CBC", "NULL"}),
+						},
+						"integrity_algorithm": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "MD5",
+							ValidateFunc: validateAllowedStringValue([]string{"MD5", "SHA1"}),
+						},
+						"sa_lifetime_seconds": &schema.Schema{
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      3600,
+							ValidateFunc: validateIntegerInRange(180, 604800),
+						},
+						"sa_lifetime_traffic": &schema.Schema{
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1843200,
+							ValidateFunc: validateIntegerInRange(2560, 604800000),
+						},
+						"pfs_dh_group": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "NULL",
+							ValidateFunc: validateAllowedStringValue([]string{"NULL", "DH-GROUP1", "DH-GROUP2", "DH-GROUP5", "DH-GROUP14", "DH-GROUP24"}),
+						},
+					},
+				},
+			},
+
+			// Computed values
+			"state": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"net_status": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"create_time": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceTencentCloudVpnConnectionCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*TencentCloudClient)
+
+	spdAcl, err := expandVpnSecurityPolicies(d.Get("security_policy").(*schema.Set))
+	if err != nil {
+		return err
+	}
+	ikeSet, err := expandVpnIkeConfig(d.Get("ike_config").([]interface{}))
+	if err != nil {
+		return err
+	}
+
+	addReq := vpc.NewAddVpnConnRequest()
+	addReq.VpcId = common.StringPtr(d.Get("vpc_id").(string))
+	addReq.VpnGwId = common.StringPtr(d.Get("vpn_gateway_id").(string))
+	addReq.UserGwId = common.StringPtr(d.Get("customer_gateway_id").(string))
+	addReq.VpnConnName = common.StringPtr(d.Get("name").(string))
+	addReq.PreSharedKey = common.StringPtr(d.Get("pre_share_key").(string))
+	addReq.SPDAcl = common.StringPtr(spdAcl)
+	addReq.IKESet = ikeSet
+	addReq.IPsecSet = expandVpnIpsecConfig(d.Get("ipsec_config").([]interface{}))
+	addResp, err := client.vpcConn.AddVpnConn(addReq)
+	if err != nil {
+		return fmt.Errorf("client.vpcConn.AddVpnConn error: %v", err)
+	}
+	log.Printf("[DEBUG] client.vpcConn.AddVpnConn vpnConnId: %v, taskId: %v", *addResp.VpnConnId, *addResp.TaskId)
+	d.SetId(*addResp.VpnConnId)
+
+	if _, err := client.PollingVpcTaskResult(addResp.TaskId, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+	return resourceTencentCloudVpnConnectionRead(d, meta)
+}
+
+func resourceTencentCloudVpnConnectionRead(d *schema.ResourceData, meta interface{}) error {
+	connection, err := describeVpnConnection(meta.(*TencentCloudClient).vpcConn, d.Id())
+	if err != nil {
+		if err == errVpnConnectionNotFound {
+			log.Printf("[WARN] vpn connection %v not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("vpc_id", *connection.VpcId)
+	d.Set("vpn_gateway_id", *connection.VpnGwId)
+	d.Set("customer_gateway_id", *connection.UserGwId)
+	d.Set("name", *connection.VpnConnName)
+	// the key may be left out of the response, e.g. for a sub-account
+	if connection.PreSharedKey != nil && *connection.PreSharedKey != "" {
+		d.Set("pre_share_key", *connection.PreSharedKey)
+	}
+	if err := d.Set("security_policy", flattenVpnSecurityPolicies(connection.SPDAcl)); err != nil {
+		return err
+	}
+	if connection.IKEArg != nil {
+		if err := d.Set("ike_config", flattenVpnIkeConfig(connection.IKEArg)); err != nil {
+			return err
+		}
+	}
+	if connection.IPSECArg != nil {
+		if err := d.Set("ipsec_config", flattenVpnIpsecConfig(connection.IPSECArg)); err != nil {
+			return err
+		}
+	}
+	d.Set("state", vpnStates[*connection.VpnConnState])
+	if connection.NetStatus != nil {
+		d.Set("net_status", *connection.NetStatus)
+	}
+	if connection.CreateTime != nil {
+		d.Set("create_time", *connection.CreateTime)
+	}
+	return nil
+}
+
+func resourceTencentCloudVpnConnectionUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*TencentCloudClient)
+
+	if !d.HasChange("name") && !d.HasChange("pre_share_key") && !d.HasChange("security_policy") &&
+		!d.HasChange("ike_config") && !d.HasChange("ipsec_config") {
+		return resourceTencentCloudVpnConnectionRead(d, meta)
+	}
+
+	// the connection is modified as a whole
+	spdAcl, err := expandVpnSecurityPolicies(d.Get("security_policy").(*schema.Set))
+	if err != nil {
+		return err
+	}
+	ikeSet, err := expandVpnIkeConfig(d.Get("ike_config").([]interface{}))
+	if err != nil {
+		return err
+	}
+
+	modifyReq := vpc.NewModifyVpnConnRequest()
+	modifyReq.VpcId = common.StringPtr(d.Get("vpc_id").(string))
+	modifyReq.VpnConnId = common.StringPtr(d.Id())
+	modifyReq.VpnConnName = common.StringPtr(d.Get("name").(string))
+	modifyReq.PreSharedKey = common.StringPtr(d.Get("pre_share_key").(string))
+	modifyReq.SPDAcl = common.StringPtr(spdAcl)
+	modifyReq.IKESet = ikeSet
+	modifyReq.IPsecSet = expandVpnIpsecConfig(d.Get("ipsec_config").([]interface{}))
+	modifyResp, err := client.vpcConn.ModifyVpnConn(modifyReq)
+	b, _ := json.Marshal(modifyResp)
+	log.Printf("[DEBUG] client.vpcConn.ModifyVpnConn response: %s", b)
+	if err != nil {
+		return fmt.Errorf("client.vpcConn.ModifyVpnConn error: %v", err)
+	}
+
+	if modifyResp.TaskId != nil {
+		if _, err := client.PollingVpcTaskResult(modifyResp.TaskId, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
+	return resourceTencentCloudVpnConnectionRead(d, meta)
+}
+
+func resourceTencentCloudVpnConnectionDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*TencentCloudClient)
+
+	deleteReq := vpc.NewDeleteVpnConnRequest()
+	deleteReq.VpcId = common.StringPtr(d.Get("vpc_id").(string))
+	deleteReq.VpnConnId = common.StringPtr(d.Id())
+	deleteResp, err := client.vpcConn.DeleteVpnConn(deleteReq)
+	b, _ := json.Marshal(deleteResp)
+	log.Printf("[DEBUG] client.vpcConn.DeleteVpnConn response: %s", b)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("client.vpcConn.DeleteVpnConn error: %v", err)
+	}
+
+	_, err = client.PollingVpcTaskResult(deleteResp.TaskId, d.Timeout(schema.TimeoutDelete))
+	return err
+}
+
+// expandVpnSecurityPolicies renders the security policies as the SPDAcl param,
+// a JSON object of the remote cidr blocks by the local one.
+func expandVpnSecurityPolicies(policies *schema.Set) (string, error) {
+	spdAcl := make(map[string][]string)
+	for _, v := range policies.List() {
+		policy := v.(map[string]interface{})
+		local := policy["local_cidr_block"].(string)
+		if _, ok := spdAcl[local]; ok {
+			return "", fmt.Errorf("duplicate security_policy of local_cidr_block %v", local)
+		}
+		remotes := expandStringList(policy["remote_cidr_blocks"].(*schema.Set).List())
+		sort.Strings(remotes)
+		spdAcl[local] = remotes
+	}
+	b, err := json.Marshal(spdAcl)
+	return string(b), err
+}
+
+func flattenVpnSecurityPolicies(spdAcl map[string][]string) []interface{} {
+	policies := make([]interface{}, 0, len(spdAcl))
+	for local, remotes := range spdAcl {
+		remoteSet := make([]interface{}, 0, len(remotes))
+		for _, remote := range remotes {
+			remoteSet = append(remoteSet, remote)
+		}
+		policies = append(policies, map[string]interface{}{
+			"local_cidr_block":   local,
+			"remote_cidr_blocks": schema.NewSet(schema.HashString, remoteSet),
+		})
+	}
+	return policies
+}
+
+// expandVpnIkeConfig returns nil if the block is not set, so that the API
+// applies its defaults.
+func expandVpnIkeConfig(configs []interface{}) (*vpc.VpnConnIKESet, error) {
+	if len(configs) == 0 || configs[0] == nil {
+		return nil, nil
+	}
+	config := configs[0].(map[string]interface{})
+	ike := &vpc.VpnConnIKESet{
+		PropoEncryAlgorithm:  common.StringPtr(config["proto_encry_algorithm"].(string)),
+		PropoAuthenAlgorithm: common.StringPtr(config["proto_authen_algorithm"].(string)),
+		ExchangeMode:         common.StringPtr(config["exchange_mode"].(string)),
+		LocalIdentity:        common.StringPtr(config["local_identity"].(string)),
+		RemoteIdentity:       common.StringPtr(config["remote_identity"].(string)),
+		LocalAddress:         common.StringPtr(config["local_address"].(string)),
+		RemoteAddress:        common.StringPtr(config["remote_address"].(string)),
+		LocalFqdnName:        common.StringPtr(config["local_fqdn_name"].(string)),
+		RemoteFqdnName:       common.StringPtr(config["remote_fqdn_name"].(string)),
+		DhGroupName:          common.StringPtr(config["dh_group_name"].(string)),
+		IkeSaLifetimeSeconds: common.IntPtr(config["sa_lifetime_seconds"].(int)),
+	}
+	if *ike.LocalIdentity == "fqdn" && *ike.LocalFqdnName == "" {
+		return nil, fmt.Errorf("local_fqdn_name is required when local_identity is fqdn")
+	}
+	if *ike.RemoteIdentity == "fqdn" && *ike.RemoteFqdnName == "" {
+		return nil, fmt.Errorf("remote_fqdn_name is required when remote_identity is fqdn")
+	}
+	return ike, nil
+}
+
+func flattenVpnIkeConfig(ike *vpc.VpnConnIKESet) []interface{} {
+	config := map[string]interface{}{
+		"proto_encry_algorithm":  common.StringValue(ike.PropoEncryAlgorithm),
+		"proto_authen_algorithm": common.StringValue(ike.PropoAuthenAlgorithm),
+		"exchange_mode":          common.StringValue(ike.ExchangeMode),
+		"local_identity":         common.StringValue(ike.LocalIdentity),
+		"remote_identity":        common.StringValue(ike.RemoteIdentity),
+		"local_address":          common.StringValue(ike.LocalAddress),
+		"remote_address":         common.StringValue(ike.RemoteAddress),
+		"local_fqdn_name":        common.StringValue(ike.LocalFqdnName),
+		"remote_fqdn_name":       common.StringValue(ike.RemoteFqdnName),
+		"dh_group_name":          common.StringValue(ike.DhGroupName),
+	}
+	if ike.IkeSaLifetimeSeconds != nil {
+		config["sa_lifetime_seconds"] = *ike.IkeSaLifetimeSeconds
+	}
+	return []interface{}{config}
+}
+
+// expandVpnIpsecConfig returns nil if the block is not set, so that the API
+// applies its defaults.
+func expandVpnIpsecConfig(configs []interface{}) *vpc.VpnConnIPsecSet {
+	if len(configs) == 0 || configs[0] == nil {
+		return nil
+	}
+	config := configs[0].(map[string]interface{})
+	return &vpc.VpnConnIPsecSet{
+		EncryptAlgorithm:       common.StringPtr(config["encrypt_algorithm"].(string)),
+		IntegrityAlgorith:      common.StringPtr(config["integrity_algorithm"].(string)),
+		IpsecSaLifetimeSeconds: common.IntPtr(config["sa_lifetime_seconds"].(int)),
+		IpsecSaLifetimeTraffic: common.IntPtr(config["sa_lifetime_traffic"].(int)),
+		PfsDhGroup:             common.StringPtr(config["pfs_dh_group"].(string)),
+	}
+}
+
+func flattenVpnIpsecConfig(ipsec *vpc.VpnConnIPsecSet) []interface{} {
+	config := map[string]interface{}{
+		"encrypt_algorithm":   common.StringValue(ipsec.EncryptAlgorithm),
+		"integrity_algorithm": common.StringValue(ipsec.IntegrityAlgorith),
+		"pfs_dh_group":        common.StringValue(ipsec.PfsDhGroup),
+	}
+	if ipsec.IpsecSaLifetimeSeconds != nil {
+		config["sa_lifetime_seconds"] = *ipsec.IpsecSaLifetimeSeconds
+	}
+	if ipsec.IpsecSaLifetimeTraffic != nil {
+		config["sa_lifetime_traffic"] = *ipsec.IpsecSaLifetimeTraffic
+	}
+	return []interface{}{config}
+}
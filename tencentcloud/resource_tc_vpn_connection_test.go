@@ -0,0 +1,157 @@
+package tencentcloud
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestUnitTencentCloudVpnConnection_basic(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+	m.AddVpc("vpc-mockvpn1", "10.0.0.0/16")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "vpnx", "tencentcloud_vpn_connection"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(testUnitVpnConnectionConfig),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "vpnx", "tencentcloud_vpn_connection.foo"),
+					resource.TestCheckResourceAttrPair("tencentcloud_vpn_connection.foo", "vpn_gateway_id", "tencentcloud_vpn_gateway.foo", "id"),
+					resource.TestCheckResourceAttrPair("tencentcloud_vpn_connection.foo", "customer_gateway_id", "tencentcloud_vpn_customer_gateway.foo", "id"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "name", "ci-temp-test-vpnx"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "pre_share_key", "ci-temp-test-key"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "security_policy.#", "1"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "ike_config.#", "1"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "ike_config.0.exchange_mode", "main"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "ike_config.0.sa_lifetime_seconds", "86400"),
+					resource.TestCheckResourceAttrPair("tencentcloud_vpn_connection.foo", "ike_config.0.local_address", "tencentcloud_vpn_gateway.foo", "public_ip_address"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "ike_config.0.remote_address", "198.51.100.1"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "ipsec_config.#", "1"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "ipsec_config.0.pfs_dh_group", "NULL"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "state", "available"),
+				),
+			},
+			{
+				Config: m.Config(testUnitVpnConnectionConfigUpdate),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "vpnx", "tencentcloud_vpn_connection.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "name", "ci-temp-test-vpnx-updated"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "security_policy.#", "2"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "ike_config.0.proto_encry_algorithm", "AES-CBC-256"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "ike_config.0.exchange_mode", "aggressive"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "ike_config.0.dh_group_name", "group14"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "ipsec_config.0.encrypt_algorithm", "AES-CBC-128"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_connection.foo", "ipsec_config.0.sa_lifetime_seconds", "7200"),
+				),
+			},
+			{
+				Config:            m.Config(testUnitVpnConnectionConfigUpdate),
+				ResourceName:      "tencentcloud_vpn_connection.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestUnitTencentCloudVpnConnection_fqdn(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+	m.AddVpc("vpc-mockvpn1", "10.0.0.0/16")
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: m.Providers(),
+		Steps: []resource.TestStep{
+			{
+				Config:      m.Config(testUnitVpnConnectionConfigFqdn),
+				ExpectError: regexp.MustCompile("local_fqdn_name is required when local_identity is fqdn"),
+			},
+		},
+	})
+}
+
+const testUnitVpnConnectionConfigGateways = `
+data "tencentcloud_vpc" "foo" {
+  id = "vpc-mockvpn1"
+}
+
+resource "tencentcloud_vpn_gateway" "foo" {
+  vpc_id = "${data.tencentcloud_vpc.foo.id}"
+  name   = "ci-temp-test-vpngw"
+  period = 1
+}
+
+resource "tencentcloud_vpn_customer_gateway" "foo" {
+  name              = "ci-temp-test-cgw"
+  public_ip_address = "198.51.100.1"
+}
+`
+
+const testUnitVpnConnectionConfig = testUnitVpnConnectionConfigGateways + `
+resource "tencentcloud_vpn_connection" "foo" {
+  vpc_id              = "${data.tencentcloud_vpc.foo.id}"
+  vpn_gateway_id      = "${tencentcloud_vpn_gateway.foo.id}"
+  customer_gateway_id = "${tencentcloud_vpn_customer_gateway.foo.id}"
+  name                = "ci-temp-test-vpnx"
+  pre_share_key       = "ci-temp-test-key"
+
+  security_policy {
+    local_cidr_block   = "10.0.0.0/16"
+    remote_cidr_blocks = ["192.168.0.0/24"]
+  }
+}
+`
+
+const testUnitVpnConnectionConfigUpdate = testUnitVpnConnectionConfigGateways + `
+resource "tencentcloud_vpn_connection" "foo" {
+  vpc_id              = "${data.tencentcloud_vpc.foo.id}"
+  vpn_gateway_id      = "${tencentcloud_vpn_gateway.foo.id}"
+  customer_gateway_id = "${tencentcloud_vpn_customer_gateway.foo.id}"
+  name                = "ci-temp-test-vpnx-updated"
+  pre_share_key       = "ci-temp-test-key"
+
+  security_policy {
+    local_cidr_block   = "10.0.0.0/16"
+    remote_cidr_blocks = ["192.168.0.0/24", "192.168.1.0/24"]
+  }
+
+  security_policy {
+    local_cidr_block   = "10.0.1.0/24"
+    remote_cidr_blocks = ["192.168.2.0/24"]
+  }
+
+  ike_config {
+    proto_encry_algorithm = "AES-CBC-256"
+    exchange_mode         = "aggressive"
+    dh_group_name         = "group14"
+  }
+
+  ipsec_config {
+    encrypt_algorithm   = "AES-CBC-128"
+    sa_lifetime_seconds = 7200
+  }
+}
+`
+
+const testUnitVpnConnectionConfigFqdn = testUnitVpnConnectionConfigGateways + `
+resource "tencentcloud_vpn_connection" "foo" {
+  vpc_id              = "${data.tencentcloud_vpc.foo.id}"
+  vpn_gateway_id      = "${tencentcloud_vpn_gateway.foo.id}"
+  customer_gateway_id = "${tencentcloud_vpn_customer_gateway.foo.id}"
+  name                = "ci-temp-test-vpnx"
+  pre_share_key       = "ci-temp-test-key"
+
+  security_policy {
+    local_cidr_block   = "10.0.0.0/16"
+    remote_cidr_blocks = ["192.168.0.0/24"]
+  }
+
+  ike_config {
+    local_identity = "fqdn"
+  }
+}
+`
@@ -0,0 +1,113 @@
+package tencentcloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	vpc "github.com/zqfan/tencentcloud-sdk-go/services/vpc/unversioned"
+)
+
+func resourceTencentCloudVpnCustomerGateway() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTencentCloudVpnCustomerGatewayCreate,
+		Read:   resourceTencentCloudVpnCustomerGatewayRead,
+		Update: resourceTencentCloudVpnCustomerGatewayUpdate,
+		Delete: resourceTencentCloudVpnCustomerGatewayDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateStringLengthInRange(1, 60),
+			},
+			"public_ip_address": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIp,
+			},
+
+			// Computed values
+			"create_time": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceTencentCloudVpnCustomerGatewayCreate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*TencentCloudClient).vpcConn
+
+	addReq := vpc.NewAddUserGwRequest()
+	addReq.UserGwName = common.StringPtr(d.Get("name").(string))
+	addReq.UserGwAddr = common.StringPtr(d.Get("public_ip_address").(string))
+	addResp, err := conn.AddUserGw(addReq)
+	b, _ := json.Marshal(addResp)
+	log.Printf("[DEBUG] conn.AddUserGw response: %s", b)
+	if err != nil {
+		return fmt.Errorf("conn.AddUserGw error: %v", err)
+	}
+
+	d.SetId(*addResp.UserGwId)
+	return resourceTencentCloudVpnCustomerGatewayRead(d, meta)
+}
+
+func resourceTencentCloudVpnCustomerGatewayRead(d *schema.ResourceData, meta interface{}) error {
+	gateway, err := describeCustomerGateway(meta.(*TencentCloudClient).vpcConn, d.Id())
+	if err != nil {
+		if err == errCustomerGatewayNotFound {
+			log.Printf("[WARN] vpn customer gateway %v not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("name", *gateway.UserGwName)
+	d.Set("public_ip_address", *gateway.UserGwAddr)
+	if gateway.CreateTime != nil {
+		d.Set("create_time", *gateway.CreateTime)
+	}
+	return nil
+}
+
+func resourceTencentCloudVpnCustomerGatewayUpdate(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*TencentCloudClient).vpcConn
+
+	if d.HasChange("name") {
+		modifyReq := vpc.NewModifyUserGwRequest()
+		modifyReq.UserGwId = common.StringPtr(d.Id())
+		modifyReq.UserGwName = common.StringPtr(d.Get("name").(string))
+		modifyResp, err := conn.ModifyUserGw(modifyReq)
+		b, _ := json.Marshal(modifyResp)
+		log.Printf("[DEBUG] conn.ModifyUserGw response: %s", b)
+		if err != nil {
+			return fmt.Errorf("conn.ModifyUserGw error: %v", err)
+		}
+	}
+	return resourceTencentCloudVpnCustomerGatewayRead(d, meta)
+}
+
+func resourceTencentCloudVpnCustomerGatewayDelete(d *schema.ResourceData, meta interface{}) error {
+	conn := meta.(*TencentCloudClient).vpcConn
+
+	deleteReq := vpc.NewDeleteUserGwRequest()
+	deleteReq.UserGwId = common.StringPtr(d.Id())
+	deleteResp, err := conn.DeleteUserGw(deleteReq)
+	b, _ := json.Marshal(deleteResp)
+	log.Printf("[DEBUG] conn.DeleteUserGw response: %s", b)
+	if err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("conn.DeleteUserGw error: %v", err)
+	}
+	return nil
+}
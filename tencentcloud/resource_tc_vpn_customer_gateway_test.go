@@ -0,0 +1,49 @@
+package tencentcloud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestUnitTencentCloudVpnCustomerGateway_basic(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    m.Providers(),
+		CheckDestroy: testUnitCheckMockDestroy(m, "cgw", "tencentcloud_vpn_customer_gateway"),
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(fmt.Sprintf(testUnitVpnCustomerGatewayConfig, "ci-temp-test-cgw")),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "cgw", "tencentcloud_vpn_customer_gateway.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_customer_gateway.foo", "name", "ci-temp-test-cgw"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_customer_gateway.foo", "public_ip_address", "198.51.100.1"),
+					resource.TestCheckResourceAttrSet("tencentcloud_vpn_customer_gateway.foo", "create_time"),
+				),
+			},
+			{
+				Config: m.Config(fmt.Sprintf(testUnitVpnCustomerGatewayConfig, "ci-temp-test-cgw-updated")),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "cgw", "tencentcloud_vpn_customer_gateway.foo"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_customer_gateway.foo", "name", "ci-temp-test-cgw-updated"),
+				),
+			},
+			{
+				Config:            m.Config(fmt.Sprintf(testUnitVpnCustomerGatewayConfig, "ci-temp-test-cgw-updated")),
+				ResourceName:      "tencentcloud_vpn_customer_gateway.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testUnitVpnCustomerGatewayConfig = `
+resource "tencentcloud_vpn_customer_gateway" "foo" {
+  name              = "%s"
+  public_ip_address = "198.51.100.1"
+}
+`
@@ -0,0 +1,178 @@
+package tencentcloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	vpc "github.com/zqfan/tencentcloud-sdk-go/services/vpc/unversioned"
+)
+
+func resourceTencentCloudVpnGateway() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTencentCloudVpnGatewayCreate,
+		Read:   resourceTencentCloudVpnGatewayRead,
+		Update: resourceTencentCloudVpnGatewayUpdate,
+		Delete: resourceTencentCloudVpnGatewayDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vpc_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateStringLengthInRange(1, 60),
+			},
+			"bandwidth": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      5,
+				ValidateFunc: validateIntegerInRange(1, 1000),
+			},
+			// billing, the gateway is prepaid, the period and the auto renewal
+			// are used only when it is created and are not returned by the API
+			"period": &schema.Schema{
+				Type:             schema.TypeInt,
+				Required:         true,
+				ValidateFunc:     validateIntegerInRange(1, 36),
+				DiffSuppressFunc: suppressVpnGatewayBillingDiff,
+			},
+			"auto_renew": &schema.Schema{
+				Type:             schema.TypeBool,
+				Optional:         true,
+				Default:          false,
+				DiffSuppressFunc: suppressVpnGatewayBillingDiff,
+			},
+			"tags": tagsSchema(),
+
+			// Computed values
+			"public_ip_address": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"state": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"create_time": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"expire_time": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// suppressVpnGatewayBillingDiff ignores the changes of the billing arguments
+// once the gateway is created, so that neither a change nor an import
+// replaces a gateway which is paid for.
+func suppressVpnGatewayBillingDiff(k, old, new string, d *schema.ResourceData) bool {
+	return d.Id() != ""
+}
+
+func resourceTencentCloudVpnGatewayCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*TencentCloudClient)
+
+	createReq := vpc.NewCreateVpnRequest()
+	createReq.VpcId = common.StringPtr(d.Get("vpc_id").(string))
+	createReq.VpnGwName = common.StringPtr(d.Get("name").(string))
+	createReq.Bandwidth = common.IntPtr(d.Get("bandwidth").(int))
+	createReq.Period = common.IntPtr(d.Get("period").(int))
+	createReq.IsAutoRenewals = common.IntPtr(0)
+	if d.Get("auto_renew").(bool) {
+		createReq.IsAutoRenewals = common.IntPtr(1)
+	}
+	createResp, err := client.vpcConn.CreateVpn(createReq)
+	b, _ := json.Marshal(createResp)
+	log.Printf("[DEBUG] client.vpcConn.CreateVpn response: %s", b)
+	if err != nil {
+		return fmt.Errorf("client.vpcConn.CreateVpn error: %v", err)
+	}
+	d.SetId(*createResp.VpnGwId)
+
+	if _, err := client.PollingVpcBillResult(createResp.BillId, d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+	if err := updateResourceTags(client, d, "vpc", "vpngw"); err != nil {
+		return err
+	}
+	return resourceTencentCloudVpnGatewayRead(d, meta)
+}
+
+func resourceTencentCloudVpnGatewayRead(d *schema.ResourceData, meta interface{}) error {
+	gateway, err := describeVpnGateway(meta.(*TencentCloudClient).vpcConn, d.Id())
+	if err != nil {
+		if err == errVpnGatewayNotFound {
+			log.Printf("[WARN] vpn gateway %v not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("vpc_id", *gateway.VpcId)
+	d.Set("name", *gateway.VpnGwName)
+	d.Set("bandwidth", *gateway.Bandwidth)
+	d.Set("public_ip_address", *gateway.VpnGwAddress)
+	d.Set("state", vpnStates[*gateway.State])
+	if gateway.CreateTime != nil {
+		d.Set("create_time", *gateway.CreateTime)
+	}
+	if gateway.ExpireTime != nil {
+		d.Set("expire_time", *gateway.ExpireTime)
+	}
+	return readResourceTags(meta.(*TencentCloudClient), d, "vpc", "vpngw")
+}
+
+func resourceTencentCloudVpnGatewayUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*TencentCloudClient)
+
+	d.Partial(true)
+	if d.HasChange("name") {
+		modifyReq := vpc.NewModifyVpnGwRequest()
+		modifyReq.VpcId = common.StringPtr(d.Get("vpc_id").(string))
+		modifyReq.VpnGwId = common.StringPtr(d.Id())
+		modifyReq.VpnGwName = common.StringPtr(d.Get("name").(string))
+		modifyResp, err := client.vpcConn.ModifyVpnGw(modifyReq)
+		b, _ := json.Marshal(modifyResp)
+		log.Printf("[DEBUG] client.vpcConn.ModifyVpnGw response: %s", b)
+		if err != nil {
+			return fmt.Errorf("client.vpcConn.ModifyVpnGw error: %v", err)
+		}
+		d.SetPartial("name")
+	}
+	if d.HasChange("tags") {
+		if err := updateResourceTags(client, d, "vpc", "vpngw"); err != nil {
+			return err
+		}
+		d.SetPartial("tags")
+	}
+	d.Partial(false)
+
+	return resourceTencentCloudVpnGatewayRead(d, meta)
+}
+
+// resourceTencentCloudVpnGatewayDelete only removes the gateway from the
+// state, a prepaid gateway can not be deleted by the API, it is released when
+// it expires.
+func resourceTencentCloudVpnGatewayDelete(d *schema.ResourceData, meta interface{}) error {
+	log.Printf("[WARN] vpn gateway %v is prepaid and can not be deleted, it is removed from state and released when it expires at %v",
+		d.Id(), d.Get("expire_time"))
+	return nil
+}
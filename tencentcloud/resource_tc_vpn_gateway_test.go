@@ -0,0 +1,103 @@
+package tencentcloud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestUnitTencentCloudVpnGateway_basic(t *testing.T) {
+	m := newMockCloud()
+	defer m.Close()
+	m.AddVpc("vpc-mockvpn1", "10.0.0.0/16")
+
+	var gatewayId string
+	resource.UnitTest(t, resource.TestCase{
+		Providers: m.Providers(),
+		// a prepaid gateway is only removed from the state
+		CheckDestroy: func(*terraform.State) error {
+			if !m.Exists("vpngw", gatewayId) {
+				return fmt.Errorf("vpn gateway %v is deleted before it expires", gatewayId)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: m.Config(fmt.Sprintf(testUnitVpnGatewayConfig, "ci-temp-test-vpngw", 3, `test = "test"`)),
+				Check: resource.ComposeTestCheckFunc(
+					testUnitCheckMockExists(m, "vpngw", "tencentcloud_vpn_gateway.foo"),
+					func(s *terraform.State) error {
+						gatewayId = s.RootModule().Resources["tencentcloud_vpn_gateway.foo"].Primary.ID
+						return nil
+					},
+					resource.TestCheckResourceAttrPair("tencentcloud_vpn_gateway.foo", "vpc_id", "data.tencentcloud_vpc.foo", "id"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_gateway.foo", "name", "ci-temp-test-vpngw"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_gateway.foo", "bandwidth", "10"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_gateway.foo", "period", "3"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_gateway.foo", "auto_renew", "true"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_gateway.foo", "state", "available"),
+					resource.TestCheckResourceAttrSet("tencentcloud_vpn_gateway.foo", "public_ip_address"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_gateway.foo", "expire_time", "2018-04-01 00:00:00"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_gateway.foo", "tags.%", "1"),
+					testUnitCheckMockTags(m, "tencentcloud_vpn_gateway.foo", "vpc", "vpngw", map[string]string{"test": "test"}),
+					testUnitCheckVpnGatewayBilling(m, "tencentcloud_vpn_gateway.foo", 3, true),
+				),
+			},
+			{
+				// the period is used only on create
+				Config: m.Config(fmt.Sprintf(testUnitVpnGatewayConfig, "ci-temp-test-vpngw-updated", 6, `abc = "abc"`)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("tencentcloud_vpn_gateway.foo", "id", &gatewayId),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_gateway.foo", "name", "ci-temp-test-vpngw-updated"),
+					resource.TestCheckResourceAttr("tencentcloud_vpn_gateway.foo", "period", "3"),
+					testUnitCheckMockTags(m, "tencentcloud_vpn_gateway.foo", "vpc", "vpngw", map[string]string{"abc": "abc"}),
+					testUnitCheckVpnGatewayBilling(m, "tencentcloud_vpn_gateway.foo", 3, true),
+				),
+			},
+			{
+				Config:                  m.Config(fmt.Sprintf(testUnitVpnGatewayConfig, "ci-temp-test-vpngw-updated", 6, `abc = "abc"`)),
+				ResourceName:            "tencentcloud_vpn_gateway.foo",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"period", "auto_renew"},
+			},
+		},
+	})
+}
+
+func testUnitCheckVpnGatewayBilling(m *mockCloud, n string, period int, autoRenew bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource %v not found", n)
+		}
+		m.Lock()
+		defer m.Unlock()
+		gw := m.vpnGws[rs.Primary.ID]
+		if gw.period != period || gw.autoRenew != autoRenew {
+			return fmt.Errorf("expect vpn gateway %v to be paid for %v months with auto renewal %v, got %v months with %v",
+				rs.Primary.ID, period, autoRenew, gw.period, gw.autoRenew)
+		}
+		return nil
+	}
+}
+
+const testUnitVpnGatewayConfig = `
+data "tencentcloud_vpc" "foo" {
+  id = "vpc-mockvpn1"
+}
+
+resource "tencentcloud_vpn_gateway" "foo" {
+  vpc_id     = "${data.tencentcloud_vpc.foo.id}"
+  name       = "%s"
+  bandwidth  = 10
+  period     = %d
+  auto_renew = true
+
+  tags = {
+    %s
+  }
+}
+`
@@ -3,7 +3,6 @@ package tencentcloud
 import (
 	"time"
 
-	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/zqfan/tencentcloud-sdk-go/common"
 	cvm "github.com/zqfan/tencentcloud-sdk-go/services/cvm/v20170312"
 )
@@ -19,7 +18,11 @@ func findEipById(cvmConn *cvm.Client, eipId string) (eip *cvm.Address, retryable
 	req.Limit = common.IntPtr(1)
 	resp, err := cvmConn.DescribeAddresses(req)
 	if err != nil {
-		retryable = true
+		if isNotFound(err) {
+			err = errEIPNotFound
+			return
+		}
+		retryable = isRetryable(err)
 		return
 	}
 	if *resp.Response.TotalCount == 0 {
@@ -49,24 +52,41 @@ func setEipName(cvmConn *cvm.Client, eipId string, newName string) (err error) {
 }
 
 func waitForEipAvailable(cvmConn *cvm.Client, eipId string) (err error) {
-	return resource.Retry(3*time.Minute, func() *resource.RetryError {
-		eip, retryable, err := findEipById(cvmConn, eipId)
-		if err != nil {
-			if retryable {
-				return resource.RetryableError(err)
-			}
-			return resource.NonRetryableError(err)
-		}
-
-		status := *eip.AddressStatus
-		if status == tencentCloudApiEipStatusCreateFailed {
-			return resource.NonRetryableError(errCreateEIPFailed)
-		}
+	_, err = waitForEipStatus(cvmConn, eipId, []string{tencentCloudApiEipStatusUnbind}, 3*time.Minute)
+	return
+}
 
-		if status == tencentCloudApiEipStatusUnbind {
-			return nil
-		}
+func waitForEipBinded(cvmConn *cvm.Client, eipId string) (err error) {
+	_, err = waitForEipStatus(cvmConn, eipId, []string{
+		tencentCloudApiEipStatusBind,
+		tencentCloudApiEipStatusBindEni,
+	}, 3*time.Minute)
+	return
+}
 
-		return resource.RetryableError(errEIPStillCreating)
-	})
+// waitForEipStatus waits for the eip to reach one of the target statuses, all
+// the other statuses but CREATE_FAILED are pending.
+func waitForEipStatus(cvmConn *cvm.Client, eipId string, targets []string, timeout time.Duration) (eip *cvm.Address, err error) {
+	w := &waiter{
+		Name:   "eip " + eipId,
+		Target: targets,
+		Failed: []string{tencentCloudApiEipStatusCreateFailed},
+		Refresh: func() (interface{}, string, error) {
+			eip, _, err := findEipById(cvmConn, eipId)
+			if err != nil {
+				if err == errEIPNotFound {
+					return nil, "", nil
+				}
+				return nil, "", err
+			}
+			return eip, *eip.AddressStatus, nil
+		},
+		Timeout: timeout,
+	}
+	result, err := w.Wait()
+	if err != nil {
+		return
+	}
+	eip = result.(*cvm.Address)
+	return
 }
@@ -1,98 +1,174 @@
 package tencentcloud
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/athom/goset"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/zqfan/tencentcloud-sdk-go/client"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	cvm "github.com/zqfan/tencentcloud-sdk-go/services/cvm/v20170312"
 )
 
-func waitInstanceReachTargetStatus(client *client.Client, instanceIds []string, targetStatus string) (instanceStatusMap map[string]string, err error) {
-	return waitInstanceReachOneOfTargetStatusList(client, instanceIds, []string{targetStatus})
+// defaultInstanceOperationTimeout is how long to wait for an instance operation
+// when the caller has no timeout of its own, e.g. a key pair or a disk.
+const defaultInstanceOperationTimeout = 3 * time.Minute
+
+// instanceResetPasswordDelay is how long a password reset takes in most cases,
+// there is no API to query the progress of the reset.
+const instanceResetPasswordDelay = 42 * time.Second
+
+var (
+	errInstanceNotFound = errors.New("instance not found")
+)
+
+func describeInstanceById(cvmConn *cvm.Client, instanceId string) (instance *cvm.Instance, err error) {
+	req := cvm.NewDescribeInstancesRequest()
+	req.InstanceIds = []*string{common.StringPtr(instanceId)}
+	resp, err := cvmConn.DescribeInstances(req)
+	if err != nil {
+		if isNotFound(err) {
+			err = errInstanceNotFound
+		}
+		return
+	}
+	if len(resp.Response.InstanceSet) == 0 {
+		err = errInstanceNotFound
+		return
+	}
+	instance = resp.Response.InstanceSet[0]
+	return
 }
 
-func waitInstanceReachOneOfTargetStatusList(client *client.Client, instanceIds []string, targetStatuses []string) (instanceStatusMap map[string]string, err error) {
-	err = resource.Retry(3*time.Minute, func() *resource.RetryError {
-		instanceStatusMap, err = queryInstancesStatus(client, instanceIds)
-		if err != nil {
-			return resource.NonRetryableError(err)
+func waitInstanceReachTargetStatus(cvmConn *cvm.Client, instanceIds []string, targetStatus string, timeout time.Duration) (instanceStatusMap map[string]string, err error) {
+	return waitInstanceReachOneOfTargetStatusList(cvmConn, instanceIds, []string{targetStatus}, timeout)
+}
+
+func waitInstanceReachOneOfTargetStatusList(cvmConn *cvm.Client, instanceIds []string, targetStatuses []string, timeout time.Duration) (instanceStatusMap map[string]string, err error) {
+	instanceStatusMap = make(map[string]string)
+	for _, instanceId := range instanceIds {
+		w := &waiter{
+			Name:    "instance " + instanceId,
+			Target:  targetStatuses,
+			Refresh: instanceStatusRefreshFunc(cvmConn, instanceId),
+			Timeout: timeout,
 		}
-		var targetStatusInstanceIds []string
-		for instanceId, instanceStatus := range instanceStatusMap {
-			if goset.IsIncluded(targetStatuses, instanceStatus) {
-				targetStatusInstanceIds = append(targetStatusInstanceIds, instanceId)
-			}
+		if !goset.IsIncluded(targetStatuses, "LAUNCH_FAILED") {
+			w.Failed = []string{"LAUNCH_FAILED"}
 		}
-		if len(targetStatusInstanceIds) == len(instanceStatusMap) {
-			return nil
+		var status interface{}
+		status, err = w.Wait()
+		if err != nil {
+			return
 		}
-		_, _, penddingInstanceIds, _ := goset.Difference(instanceIds, targetStatusInstanceIds)
-		return resource.RetryableError(fmt.Errorf("query instances status, pendding instanceIds: %v, not all instances are ready, retry...", penddingInstanceIds))
-	})
+		instanceStatusMap[instanceId] = status.(string)
+	}
 	return
 }
 
-func queryInstancesStatus(client *client.Client, instanceIds []string) (instanceStatusMap map[string]string, err error) {
-	if len(instanceIds) == 0 {
-		err = fmt.Errorf("queryInstancesStatus, empty instanceIds")
-		return
-	}
+// waitInstanceTerminated waits until the instance is gone after it is
+// terminated, so that its subnet and security groups can be deleted.
+func waitInstanceTerminated(cvmConn *cvm.Client, instanceId string, timeout time.Duration) error {
+	w := &waiter{
+		Name:    "instance " + instanceId,
+		Refresh: instanceStatusRefreshFunc(cvmConn, instanceId),
+		Timeout: timeout,
+	}
+	_, err := w.Wait()
+	return err
+}
 
-	params := map[string]string{
-		"Version": "2017-03-12",
-		"Action":  "DescribeInstancesStatus",
-	}
-	for i, instanceId := range instanceIds {
-		paramsKey := fmt.Sprintf("InstanceIds.%v", i+1)
-		params[paramsKey] = instanceId
+// waitInstanceIsolated waits until a terminated prepaid instance is isolated,
+// i.e. SHUTDOWN, or is released at once, isolated is false if it is released.
+func waitInstanceIsolated(cvmConn *cvm.Client, instanceId string, timeout time.Duration) (isolated bool, err error) {
+	refresh := instanceStatusRefreshFunc(cvmConn, instanceId)
+	w := &waiter{
+		Name:   "prepaid instance " + instanceId,
+		Target: []string{"SHUTDOWN", "RELEASED"},
+		Refresh: func() (interface{}, string, error) {
+			result, state, err := refresh()
+			if err == nil && result == nil {
+				return "RELEASED", "RELEASED", nil
+			}
+			return result, state, err
+		},
+		Timeout: timeout,
 	}
-
-	var response string
-	response, err = client.SendRequest("cvm", params)
+	state, err := w.Wait()
 	if err != nil {
 		return
 	}
+	isolated = state.(string) == "SHUTDOWN"
+	return
+}
 
-	var jsonresp struct {
-		Response struct {
-			Error struct {
-				Code    string `json:"Code"`
-				Message string `json:"Message"`
-			}
-			TotalCount        int
-			InstanceStatusSet []struct {
-				InstanceId    string
-				InstanceState string
+func instanceStatusRefreshFunc(cvmConn *cvm.Client, instanceId string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		instanceStatusMap, err := queryInstancesStatus(cvmConn, []string{instanceId})
+		if err != nil {
+			if err.Error() == instanceNotFoundErrorMsg([]string{instanceId}) {
+				return nil, "", nil
 			}
-			RequestId string
+			return nil, "", err
 		}
+		status := instanceStatusMap[instanceId]
+		return status, status, nil
 	}
-	err = json.Unmarshal([]byte(response), &jsonresp)
-	if err != nil {
+}
+
+func queryInstancesStatus(cvmConn *cvm.Client, instanceIds []string) (instanceStatusMap map[string]string, err error) {
+	if len(instanceIds) == 0 {
+		err = fmt.Errorf("queryInstancesStatus, empty instanceIds")
 		return
 	}
-	if jsonresp.Response.Error.Code != "" {
-		err = fmt.Errorf(
-			"call DescribeInstancesStatus got error, code:%v, message:%v",
-			jsonresp.Response.Error.Code,
-			jsonresp.Response.Error.Message,
-		)
+
+	req := cvm.NewDescribeInstancesStatusRequest()
+	req.InstanceIds = common.StringPtrs(instanceIds)
+	resp, err := cvmConn.DescribeInstancesStatus(req)
+	if err != nil {
 		return
 	}
-	if jsonresp.Response.TotalCount == 0 && len(jsonresp.Response.InstanceStatusSet) == 0 {
-		err = fmt.Errorf(instanceNotFoundErrorMsg(instanceIds))
+	if len(resp.Response.InstanceStatusSet) == 0 {
+		err = errors.New(instanceNotFoundErrorMsg(instanceIds))
 		return
 	}
 
 	instanceStatusMap = make(map[string]string)
-	instanceStatusList := jsonresp.Response.InstanceStatusSet
-	for _, instanceStatus := range instanceStatusList {
-		instanceStatusMap[instanceStatus.InstanceId] = instanceStatus.InstanceState
+	for _, instanceStatus := range resp.Response.InstanceStatusSet {
+		instanceStatusMap[*instanceStatus.InstanceId] = *instanceStatus.InstanceState
+	}
+	return
+}
+
+// describeZoneById returns the name of a zone by its numeric id, which is
+// what the v2 APIs return, e.g. 100003 --> ap-guangzhou-3. The zones are
+// described once per client, they rarely change during a run.
+func describeZoneById(client *TencentCloudClient, zoneId int) (zone string, err error) {
+	client.zonesMu.Lock()
+	defer client.zonesMu.Unlock()
+
+	id := strconv.Itoa(zoneId)
+	if zone, ok := client.zones[id]; ok {
+		return zone, nil
+	}
+	resp, err := client.cvmConn.DescribeZones(cvm.NewDescribeZonesRequest())
+	if err != nil {
+		return
+	}
+	zones := make(map[string]string, len(resp.Response.ZoneSet))
+	for _, z := range resp.Response.ZoneSet {
+		if z.ZoneId != nil && z.Zone != nil {
+			zones[*z.ZoneId] = *z.Zone
+		}
+	}
+	client.zones = zones
+	if zone, ok := zones[id]; ok {
+		return zone, nil
 	}
+	err = fmt.Errorf("zone %v not found", zoneId)
 	return
 }
 
@@ -100,105 +176,112 @@ func instanceNotFoundErrorMsg(instanceIds []string) string {
 	return fmt.Sprintf("no such instances: %v", instanceIds)
 }
 
-func stopInstance(client *client.Client, instanceId string) error {
-	return operateInstance(client, instanceId, "StopInstances")
+// stopInstance stops the instance, stoppedMode is either KEEP_CHARGING or
+// STOP_CHARGING, an empty one means the default of the API.
+func stopInstance(cvmConn *cvm.Client, instanceId string, stoppedMode string) error {
+	req := cvm.NewStopInstancesRequest()
+	req.InstanceIds = []*string{common.StringPtr(instanceId)}
+	req.ForceStop = common.BoolPtr(true)
+	if stoppedMode != "" {
+		req.StoppedMode = common.StringPtr(stoppedMode)
+	}
+	_, err := cvmConn.StopInstances(req)
+	return err
 }
 
-func startInstance(client *client.Client, instanceId string) error {
-	return operateInstance(client, instanceId, "StartInstances")
+func startInstance(cvmConn *cvm.Client, instanceId string) error {
+	req := cvm.NewStartInstancesRequest()
+	req.InstanceIds = []*string{common.StringPtr(instanceId)}
+	_, err := cvmConn.StartInstances(req)
+	return err
 }
 
-func operateInstance(client *client.Client, instanceId string, action string) error {
-	params := map[string]string{
-		"Version":       "2017-03-12",
-		"Action":        action,
-		"InstanceIds.0": instanceId,
+// modifyInstanceRunningState starts or stops the instance so that it is
+// running or not as wanted, an instance in a transitional state is waited for
+// first. stoppedMode is passed to stopInstance.
+func modifyInstanceRunningState(cvmConn *cvm.Client, instanceId string, running bool, stoppedMode string, timeout time.Duration) error {
+	statusMap, err := waitInstanceReachOneOfTargetStatusList(cvmConn, []string{instanceId}, []string{"RUNNING", "STOPPED"}, timeout)
+	if err != nil {
+		return err
 	}
-	if action == "StopInstances" {
-		params["ForceStop"] = "TRUE"
+	switch status := statusMap[instanceId]; {
+	case running && status == "STOPPED":
+		if err := startInstance(cvmConn, instanceId); err != nil {
+			return err
+		}
+		_, err = waitInstanceReachTargetStatus(cvmConn, []string{instanceId}, "RUNNING", timeout)
+	case !running && status == "RUNNING":
+		if err := stopInstance(cvmConn, instanceId, stoppedMode); err != nil {
+			return err
+		}
+		_, err = waitInstanceReachTargetStatus(cvmConn, []string{instanceId}, "STOPPED", timeout)
 	}
-
-	return runBasicActionWithRetry(client, params)
+	return err
 }
 
-func renameInstancesName(client *client.Client, instanceIds []string, newName string) error {
+func renameInstancesName(cvmConn *cvm.Client, instanceIds []string, newName string) error {
 	_, errs := validateInstanceName(interface{}(newName), "")
 	if len(errs) > 0 {
 		return errs[0]
 	}
 
-	params := map[string]string{
-		"Version":      "2017-03-12",
-		"Action":       "ModifyInstancesAttribute",
-		"InstanceName": newName,
-	}
-	for i, instanceId := range instanceIds {
-		paramKey := fmt.Sprintf("InstanceIds.%v", i+1)
-		paramValue := instanceId
-		params[paramKey] = paramValue
-	}
-
-	return runBasicActionWithRetry(client, params)
+	req := cvm.NewModifyInstancesAttributeRequest()
+	req.InstanceIds = common.StringPtrs(instanceIds)
+	req.InstanceName = common.StringPtr(newName)
+	_, err := cvmConn.ModifyInstancesAttribute(req)
+	return err
 }
 
-func resetInstancePassword(client *client.Client, instanceId string, newPassword string) error {
-	params := map[string]string{
-		"Version":       "2017-03-12",
-		"Action":        "ResetInstancesPassword",
-		"Password":      newPassword,
-		"InstanceIds.0": instanceId,
-	}
-	return operateInstanceBetweenStopAndStart(client, params, instanceId, func() error {
-		// just wait, 95% change password will be reset
-		// a little stupid, but it's ok for now
-		// need to improve it when there is API for reseting proccess query
-		time.Sleep(42 * time.Second)
-		return nil
+func resetInstancePassword(cvmConn *cvm.Client, instanceId string, newPassword string, timeout time.Duration) error {
+	req := cvm.NewResetInstancesPasswordRequest()
+	req.InstanceIds = []*string{common.StringPtr(instanceId)}
+	req.Password = common.StringPtr(newPassword)
+	operate := func() error {
+		_, err := cvmConn.ResetInstancesPassword(req)
+		return err
+	}
+	return operateInstanceBetweenStopAndStart(cvmConn, instanceId, timeout, operate, func() error {
+		// the instance stays stopped while the password is reset, so wait for
+		// the usual time of a reset and make sure it is still stopped
+		w := &waiter{
+			Name:    "instance " + instanceId,
+			Target:  []string{"STOPPED"},
+			Refresh: instanceStatusRefreshFunc(cvmConn, instanceId),
+			Timeout: timeout,
+			Delay:   instanceResetPasswordDelay,
+		}
+		_, err := w.Wait()
+		return err
 	})
 }
 
-func resetInstanceSystem(client *client.Client, d *schema.ResourceData, instanceId string, newImageId string) error {
-	params := map[string]string{
-		"Version":    "2017-03-12",
-		"Action":     "ResetInstance",
-		"ImageId":    newImageId,
-		"InstanceId": instanceId,
-	}
+func resetInstanceSystem(cvmConn *cvm.Client, d *schema.ResourceData, instanceId string, newImageId string, timeout time.Duration) error {
+	req := cvm.NewResetInstanceRequest()
+	req.InstanceId = common.StringPtr(instanceId)
+	req.ImageId = common.StringPtr(newImageId)
+	req.EnhancedService = instanceEnhancedService(d)
 
-	if v, ok := d.GetOk("disable_security_service"); ok {
-		disable := v.(bool)
-		if disable {
-			params["EnhancedService.SecurityService.Enabled"] = "FALSE"
-		}
-	}
-	if v, ok := d.GetOk("disable_monitor_service"); ok {
-		disable := v.(bool)
-		if disable {
-			params["EnhancedService.MonitorService.Enabled"] = "FALSE"
-		}
-	}
+	req.LoginSettings = &cvm.LoginSettings{}
 	if v, ok := d.GetOk("password"); ok {
-		password := v.(string)
-		params["LoginSettings.Password"] = password
+		req.LoginSettings.Password = common.StringPtr(v.(string))
 	}
-	if v, ok := d.GetOk("key_pair"); ok {
-		keyId := v.(string)
-		params["LoginSettings.KeyIds.0"] = keyId
+	if v, ok := d.GetOk("key_name"); ok {
+		req.LoginSettings.KeyIds = []*string{common.StringPtr(v.(string))}
 	}
 	if d.HasChange("system_disk_size") {
 		if d.HasChange("system_disk_type") {
 			return fmt.Errorf("system_disk_type is not allowed to change when reinstall system")
 		}
+		req.SystemDisk = &cvm.SystemDisk{}
 		if systemDiskType, ok := d.GetOk("system_disk_type"); ok {
-			params["SystemDisk.DiskType"] = systemDiskType.(string)
+			req.SystemDisk.DiskType = common.StringPtr(systemDiskType.(string))
 		}
 		if systemDiskSize, ok := d.GetOk("system_disk_size"); ok {
-			diskSize := systemDiskSize.(int)
-			params["SystemDisk.DiskSize"] = fmt.Sprintf("%v", diskSize)
+			req.SystemDisk.DiskSize = common.IntPtr(systemDiskSize.(int))
 		}
 	}
 
-	if err := runBasicActionWithRetry(client, params); err != nil {
+	if _, err := cvmConn.ResetInstance(req); err != nil {
 		return err
 	}
 
@@ -206,29 +289,193 @@ func resetInstanceSystem(client *client.Client, d *schema.ResourceData, instance
 	// status flow during resetting
 	// `RUNNING` -> `PENDING` -> ... -> `RUNNING`
 	// so let's wait for `PENDING` and then wait for `RUNNING`
-	if _, err := waitInstanceReachTargetStatus(client, []string{instanceId}, "PENDING"); err != nil {
+	if _, err := waitInstanceReachTargetStatus(cvmConn, []string{instanceId}, "PENDING", timeout); err != nil {
 		return err
 	}
-	if _, err := waitInstanceReachTargetStatus(client, []string{instanceId}, "RUNNING"); err != nil {
+	if _, err := waitInstanceReachTargetStatus(cvmConn, []string{instanceId}, "RUNNING", timeout); err != nil {
 		return err
 	}
 	return nil
 }
 
-func operateInstanceBetweenStopAndStart(client *client.Client, params map[string]string, instanceId string, wait func() error) error {
+// resetInstanceType changes the type of the instance, which has to be stopped
+// during the change, and starts it again.
+func resetInstanceType(cvmConn *cvm.Client, instanceId string, instanceType string, timeout time.Duration) error {
+	req := cvm.NewResetInstancesTypeRequest()
+	req.InstanceIds = []*string{common.StringPtr(instanceId)}
+	req.InstanceType = common.StringPtr(instanceType)
+	operate := func() error {
+		_, err := cvmConn.ResetInstancesType(req)
+		return err
+	}
+	return operateInstanceBetweenStopAndStart(cvmConn, instanceId, timeout, operate, func() error {
+		return waitInstanceStoppedAndChanged(cvmConn, instanceId, timeout, func(instance *cvm.Instance) bool {
+			return instance.InstanceType != nil && *instance.InstanceType == instanceType
+		})
+	})
+}
+
+// resizeInstanceDisks expands the system disk if systemDiskSize is not zero
+// and the data disks, the instance is stopped during the expansion and is
+// started again.
+func resizeInstanceDisks(cvmConn *cvm.Client, instanceId string, systemDiskSize int, dataDisks []*cvm.DataDisks, timeout time.Duration) error {
+	req := cvm.NewResizeInstanceDisksRequest()
+	req.InstanceId = common.StringPtr(instanceId)
+	if systemDiskSize > 0 {
+		req.SystemDisk = &cvm.SystemDisk{DiskSize: common.IntPtr(systemDiskSize)}
+	}
+	req.DataDisks = dataDisks
+	operate := func() error {
+		_, err := cvmConn.ResizeInstanceDisks(req)
+		return err
+	}
+	return operateInstanceBetweenStopAndStart(cvmConn, instanceId, timeout, operate, func() error {
+		return waitInstanceStoppedAndChanged(cvmConn, instanceId, timeout, func(instance *cvm.Instance) bool {
+			if systemDiskSize > 0 && (instance.SystemDisk == nil || instance.SystemDisk.DiskSize == nil || *instance.SystemDisk.DiskSize != systemDiskSize) {
+				return false
+			}
+			sizes := make(map[string]int)
+			for _, disk := range instance.DataDisks {
+				if disk.DiskId != nil && disk.DiskSize != nil {
+					sizes[*disk.DiskId] = *disk.DiskSize
+				}
+			}
+			for _, disk := range dataDisks {
+				if sizes[*disk.DiskId] != *disk.DiskSize {
+					return false
+				}
+			}
+			return true
+		})
+	})
+}
+
+// waitInstanceStoppedAndChanged waits for a change of a stopped instance to be
+// done, the instance is STOPPED both before and after the change, so the change
+// is only done when changed returns true.
+func waitInstanceStoppedAndChanged(cvmConn *cvm.Client, instanceId string, timeout time.Duration, changed func(instance *cvm.Instance) bool) error {
+	w := &waiter{
+		Name:   "instance " + instanceId,
+		Target: []string{"STOPPED"},
+		Refresh: func() (interface{}, string, error) {
+			instance, err := describeInstanceById(cvmConn, instanceId)
+			if err == errInstanceNotFound {
+				return nil, "", nil
+			}
+			if err != nil {
+				return nil, "", err
+			}
+			if !changed(instance) {
+				return instance, "CHANGING", nil
+			}
+			return instance, *instance.InstanceState, nil
+		},
+		Timeout: timeout,
+	}
+	_, err := w.Wait()
+	return err
+}
+
+// modifyInstanceChargeTypeToPrepaid converts a postpaid instance to a prepaid
+// one which is paid for period months and waits for the conversion.
+func modifyInstanceChargeTypeToPrepaid(cvmConn *cvm.Client, instanceId string, period int, renewFlag string, timeout time.Duration) error {
+	req := cvm.NewModifyInstancesChargeTypeRequest()
+	req.InstanceIds = []*string{common.StringPtr(instanceId)}
+	req.InstanceChargeType = common.StringPtr(tencentCloudApiInstanceChargeTypePrePaid)
+	req.InstanceChargePrepaid = &cvm.InstanceChargePrepaid{
+		Period: common.IntPtr(period),
+	}
+	if renewFlag != "" {
+		req.InstanceChargePrepaid.RenewFlag = common.StringPtr(renewFlag)
+	}
+	if _, err := cvmConn.ModifyInstancesChargeType(req); err != nil {
+		return err
+	}
+
+	w := &waiter{
+		Name:   "instance " + instanceId,
+		Target: []string{tencentCloudApiInstanceChargeTypePrePaid},
+		Refresh: func() (interface{}, string, error) {
+			instance, err := describeInstanceById(cvmConn, instanceId)
+			if err == errInstanceNotFound {
+				return nil, "", nil
+			}
+			if err != nil {
+				return nil, "", err
+			}
+			if instance.InstanceChargeType == nil {
+				return instance, "", nil
+			}
+			return instance, *instance.InstanceChargeType, nil
+		},
+		Timeout: timeout,
+	}
+	_, err := w.Wait()
+	return err
+}
+
+// renewInstance renews a prepaid instance for period months.
+func renewInstance(cvmConn *cvm.Client, instanceId string, period int) error {
+	req := cvm.NewRenewInstancesRequest()
+	req.InstanceIds = []*string{common.StringPtr(instanceId)}
+	req.InstanceChargePrepaid = &cvm.InstanceChargePrepaid{
+		Period: common.IntPtr(period),
+	}
+	_, err := cvmConn.RenewInstances(req)
+	return err
+}
+
+func modifyInstanceRenewFlag(cvmConn *cvm.Client, instanceId string, renewFlag string) error {
+	req := cvm.NewModifyInstancesRenewFlagRequest()
+	req.InstanceIds = []*string{common.StringPtr(instanceId)}
+	req.RenewFlag = common.StringPtr(renewFlag)
+	_, err := cvmConn.ModifyInstancesRenewFlag(req)
+	return err
+}
+
+func modifyInstanceProject(cvmConn *cvm.Client, instanceId string, projectId int) error {
+	req := cvm.NewModifyInstancesProjectRequest()
+	req.InstanceIds = []*string{common.StringPtr(instanceId)}
+	req.ProjectId = common.IntPtr(projectId)
+	_, err := cvmConn.ModifyInstancesProject(req)
+	return err
+}
+
+// instanceEnhancedService returns the enhanced services to disable, or nil if
+// both of them are enabled, which is the default of the API.
+func instanceEnhancedService(d *schema.ResourceData) *cvm.EnhancedService {
+	var service *cvm.EnhancedService
+	if v, ok := d.GetOk("disable_security_service"); ok && v.(bool) {
+		service = &cvm.EnhancedService{}
+		service.SecurityService = &cvm.RunSecurityServiceEnabled{Enabled: common.BoolPtr(false)}
+	}
+	if v, ok := d.GetOk("disable_monitor_service"); ok && v.(bool) {
+		if service == nil {
+			service = &cvm.EnhancedService{}
+		}
+		service.MonitorService = &cvm.RunMonitorServiceEnabled{Enabled: common.BoolPtr(false)}
+	}
+	return service
+}
+
+// operateInstanceBetweenStopAndStart stops the instance, runs the operation
+// which requires a stopped instance, waits for it with wait and starts the
+// instance again unless it was already stopped.
+func operateInstanceBetweenStopAndStart(cvmConn *cvm.Client, instanceId string, timeout time.Duration, operate func() error, wait func() error) error {
 	// make sure instance status is STOPPED before bind/unbind key pair
-	if err := stopInstance(client, instanceId); err != nil {
+	wasStopped := false
+	if err := stopInstance(cvmConn, instanceId, ""); err != nil {
 		if !errAlreadyStopped(err, instanceId) {
 			return err
 		}
+		wasStopped = true
 	}
 
-	if _, err := waitInstanceReachTargetStatus(client, []string{instanceId}, "STOPPED"); err != nil {
+	if _, err := waitInstanceReachTargetStatus(cvmConn, []string{instanceId}, "STOPPED", timeout); err != nil {
 		return err
 	}
 
-	err := runBasicActionWithRetry(client, params)
-	if err != nil {
+	if err := operate(); err != nil {
 		return err
 	}
 
@@ -237,55 +484,26 @@ func operateInstanceBetweenStopAndStart(client *client.Client, params map[string
 		return err
 	}
 
+	// an instance which was stopped in the first place stays stopped
+	if wasStopped {
+		return nil
+	}
+
 	// recover instance to running
-	if err := startInstance(client, instanceId); err != nil {
+	if err := startInstance(cvmConn, instanceId); err != nil {
 		return err
 	}
-	if _, err := waitInstanceReachTargetStatus(client, []string{instanceId}, "RUNNING"); err != nil {
+	if _, err := waitInstanceReachTargetStatus(cvmConn, []string{instanceId}, "RUNNING", timeout); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func runBasicActionWithRetry(client *client.Client, params map[string]string) error {
-	return resource.Retry(3*time.Minute, func() *resource.RetryError {
-		response, err := client.SendRequest("cvm", params)
-		if err != nil {
-			return resource.NonRetryableError(err)
-		}
-		var jsonresp struct {
-			Response struct {
-				Error struct {
-					Code    string `json:"Code"`
-					Message string `json:"Message"`
-				}
-				RequestId string
-			}
-		}
-		err = json.Unmarshal([]byte(response), &jsonresp)
-		if err != nil {
-			return resource.NonRetryableError(err)
-		}
-		if retryable(jsonresp.Response.Error.Code, jsonresp.Response.Error.Message) {
-			return resource.RetryableError(fmt.Errorf(jsonresp.Response.Error.Message))
-		}
-		if jsonresp.Response.Error.Code != "" {
-			return resource.NonRetryableError(fmt.Errorf(jsonresp.Response.Error.Message))
-		}
-		return nil
-	})
-}
-
-func bindInstanceWithSgIds(client *client.Client, instanceId string, sgIds []string) (err error) {
-	params := map[string]string{
-		"Version":       "2017-03-12",
-		"Action":        "ModifyInstancesAttribute",
-		"InstanceIds.0": instanceId,
-	}
-	for i, sgId := range sgIds {
-		paramKey := fmt.Sprintf("SecurityGroups.%v", i)
-		params[paramKey] = sgId
-	}
-	return runBasicActionWithRetry(client, params)
+func bindInstanceWithSgIds(cvmConn *cvm.Client, instanceId string, sgIds []string) (err error) {
+	req := cvm.NewModifyInstancesAttributeRequest()
+	req.InstanceIds = []*string{common.StringPtr(instanceId)}
+	req.SecurityGroups = common.StringPtrs(sgIds)
+	_, err = cvmConn.ModifyInstancesAttribute(req)
+	return
 }
@@ -3,77 +3,80 @@ package tencentcloud
 import (
 	"encoding/json"
 	"fmt"
-	"log"
 	"time"
 
 	"strings"
 
 	"github.com/athom/goset"
-	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/zqfan/tencentcloud-sdk-go/client"
+	cvm "github.com/zqfan/tencentcloud-sdk-go/services/cvm/v20170312"
+)
+
+const (
+	keyPairStateBound   = "BOUND"
+	keyPairStateUnbound = "UNBOUND"
 )
 
 var (
 	errKeyPairNotFound = fmt.Errorf("tencentcloud_key_pair not found")
 )
 
-func bindKeyPiar(client *client.Client, instanceId string, keyId string) error {
-	if err := operateKeyPiar(client, instanceId, keyId, "AssociateInstancesKeyPairs", waitForKeyPairBinded); err != nil {
+func bindKeyPiar(client *client.Client, cvmConn *cvm.Client, instanceId string, keyId string, timeout time.Duration) error {
+	if err := operateKeyPiar(client, cvmConn, instanceId, keyId, "AssociateInstancesKeyPairs", timeout, waitForKeyPairBinded); err != nil {
 		return err
 	}
 	return nil
 }
 
-func unbindKeyPiar(client *client.Client, instanceId string, keyId string) error {
-	if err := operateKeyPiar(client, instanceId, keyId, "DisassociateInstancesKeyPairs", waitForKeyPairUnbinded); err != nil {
+func unbindKeyPiar(client *client.Client, cvmConn *cvm.Client, instanceId string, keyId string, timeout time.Duration) error {
+	if err := operateKeyPiar(client, cvmConn, instanceId, keyId, "DisassociateInstancesKeyPairs", timeout, waitForKeyPairUnbinded); err != nil {
 		return err
 	}
 	return nil
 }
 
-func operateKeyPiar(client *client.Client, instanceId string, keyId string, action string, wait func(client *client.Client, instanceId string, keyId string) error) error {
+func operateKeyPiar(client *client.Client, cvmConn *cvm.Client, instanceId string, keyId string, action string, timeout time.Duration, wait func(client *client.Client, instanceId string, keyId string, timeout time.Duration) error) error {
 	params := map[string]string{
 		"Version":       "2017-03-12",
 		"Action":        action,
 		"InstanceIds.0": instanceId,
 		"KeyIds.0":      keyId,
 	}
-	return operateInstanceBetweenStopAndStart(client, params, instanceId, func() error {
-		return wait(client, instanceId, keyId)
+	operate := func() error {
+		_, err := sendRequest(client, "cvm", params)
+		return err
+	}
+	return operateInstanceBetweenStopAndStart(cvmConn, instanceId, timeout, operate, func() error {
+		return wait(client, instanceId, keyId, timeout)
 	})
 }
 
-func waitForKeyPairUnbinded(client *client.Client, instanceId string, keyId string) error {
-	return resource.Retry(3*time.Minute, func() *resource.RetryError {
-		log.Printf("[DEBUG] waitForKeyPairUnbinded: keyId: %v, instanceId: %v", keyId, instanceId)
-		_, associatedInstanceIds, err := findKeyPairById(client, keyId)
-		if err != nil {
-			return resource.NonRetryableError(err)
-		}
-		if len(associatedInstanceIds) == 0 {
-			return nil
-		}
-		if goset.IsIncluded(associatedInstanceIds, instanceId) {
-			err := fmt.Errorf("key pair: %v still bind in instance: %v", keyId, instanceId)
-			return resource.RetryableError(err)
-		}
-		return nil
-	})
+func waitForKeyPairUnbinded(client *client.Client, instanceId string, keyId string, timeout time.Duration) error {
+	return waitForKeyPairBindingState(client, instanceId, keyId, keyPairStateUnbound, timeout)
 }
 
-func waitForKeyPairBinded(client *client.Client, instanceId string, keyId string) error {
-	return resource.Retry(3*time.Minute, func() *resource.RetryError {
-		log.Printf("[DEBUG] waitForKeyPairBinded: keyId: %v, instanceId: %v", keyId, instanceId)
-		_, associatedInstanceIds, err := findKeyPairById(client, keyId)
-		if err != nil {
-			return resource.NonRetryableError(err)
-		}
-		if goset.IsIncluded(associatedInstanceIds, instanceId) {
-			return nil
-		}
-		err = fmt.Errorf("key pair: %v not bind in instance: %v yet, retry", keyId, instanceId)
-		return resource.RetryableError(err)
-	})
+func waitForKeyPairBinded(client *client.Client, instanceId string, keyId string, timeout time.Duration) error {
+	return waitForKeyPairBindingState(client, instanceId, keyId, keyPairStateBound, timeout)
+}
+
+func waitForKeyPairBindingState(client *client.Client, instanceId string, keyId string, state string, timeout time.Duration) error {
+	w := &waiter{
+		Name:   fmt.Sprintf("key pair %v of instance %v", keyId, instanceId),
+		Target: []string{state},
+		Refresh: func() (interface{}, string, error) {
+			_, associatedInstanceIds, err := findKeyPairById(client, keyId)
+			if err != nil {
+				return nil, "", err
+			}
+			if goset.IsIncluded(associatedInstanceIds, instanceId) {
+				return keyPairStateBound, keyPairStateBound, nil
+			}
+			return keyPairStateUnbound, keyPairStateUnbound, nil
+		},
+		Timeout: timeout,
+	}
+	_, err := w.Wait()
+	return err
 }
 
 func findKeyPairById(client *client.Client, id string) (keyName string, associatedInstanceIds []string, err error) {
@@ -83,24 +86,23 @@ func findKeyPairById(client *client.Client, id string) (keyName string, associat
 		"KeyIds.0": id,
 	}
 	var response string
-	response, err = client.SendRequest("cvm", params)
+	response, err = sendRequest(client, "cvm", params)
 	if err != nil {
+		if isNotFound(err) {
+			err = errKeyPairNotFound
+		}
 		return
 	}
 	var jsonresp struct {
 		Response struct {
-			Error struct {
-				Code    string `json:"Code"`
-				Message string `json:"Message"`
-			}
-			TotalCount int `json::"TotalCount"`
+			TotalCount int `json:"TotalCount"`
 			KeyPairSet []struct {
 				KeyId                 string    `json:"KeyId"`
 				KeyName               string    `json:"KeyName"`
 				Description           string    `json:"Description"`
 				PublicKey             string    `json:"PublicKey"`
-				AssociatedInstanceIds []string  `json::"AssociatedInstanceIds"`
-				CreateTime            time.Time `json::"CreateTime"`
+				AssociatedInstanceIds []string  `json:"AssociatedInstanceIds"`
+				CreateTime            time.Time `json:"CreateTime"`
 			} `json:"KeyPairSet"`
 			RequestId string
 		}
@@ -109,15 +111,6 @@ func findKeyPairById(client *client.Client, id string) (keyName string, associat
 	if err != nil {
 		return
 	}
-	if jsonresp.Response.Error.Code != "" {
-		err = fmt.Errorf(
-			"tencentcloud_key_pair got error, code:%v, message:%v",
-			jsonresp.Response.Error.Code,
-			jsonresp.Response.Error.Message,
-		)
-		return
-	}
-
 	kpSet := jsonresp.Response.KeyPairSet
 	if len(kpSet) == 0 {
 		err = errKeyPairNotFound
@@ -6,91 +6,369 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/zqfan/tencentcloud-sdk-go/client"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	clb "github.com/zqfan/tencentcloud-sdk-go/services/clb/v20180317"
+	cvm "github.com/zqfan/tencentcloud-sdk-go/services/cvm/v20170312"
+	vpcv3 "github.com/zqfan/tencentcloud-sdk-go/services/vpc/v20170312"
 )
 
 var (
-	errSecurityGroupRuleNotFound = errors.New("security group rule index not found")
+	errSecurityGroupRuleNotFound = errors.New("security group rule not found")
+	errNetworkInterfaceNotFound  = errors.New("network interface not found")
+	errLoadBalancerNotFound      = errors.New("load balancer not found")
 )
 
-func describeSecurityGroupRuleIndex(client *client.Client, rule map[string]string) (index int, err error) {
-	if rule["sgId"] == "" {
-		err = fmt.Errorf("describeSecurityGroupRuleIndex, sgId empty")
-		return
+// instanceSecurityGroupsMu serializes the changes of the security groups of
+// instances, which are replaced as a whole, so that two attachments to the
+// same instance don't overwrite each other.
+var instanceSecurityGroupsMu = &sync.Mutex{}
+
+const (
+	// the policy set of a security group is changed by someone else since
+	// it was described
+	securityGroupPolicyVersionMismatch = "UnsupportedOperation.VersionMismatch"
+
+	securityGroupPolicyRetryTimeout = 2 * time.Minute
+)
+
+func describeSecurityGroupPolicies(vpcConn *vpcv3.Client, sgId string) (*vpcv3.SecurityGroupPolicySet, error) {
+	req := vpcv3.NewDescribeSecurityGroupPoliciesRequest()
+	req.SecurityGroupId = common.StringPtr(sgId)
+	resp, err := vpcConn.DescribeSecurityGroupPolicies(req)
+	if err != nil {
+		return nil, err
 	}
-	params := map[string]string{
-		"Action": "DescribeSecurityGroupPolicys",
-		"sgId":   rule["sgId"],
+	if resp.Response.SecurityGroupPolicySet == nil {
+		return &vpcv3.SecurityGroupPolicySet{}, nil
+	}
+	return resp.Response.SecurityGroupPolicySet, nil
+}
+
+// securityGroupPoliciesOf returns the ingress or egress policies of the set.
+func securityGroupPoliciesOf(set *vpcv3.SecurityGroupPolicySet, direction string) []*vpcv3.SecurityGroupPolicy {
+	if strings.EqualFold(direction, "egress") {
+		return set.Egress
 	}
-	var response string
-	response, err = client.SendRequest("dfw", params)
+	return set.Ingress
+}
+
+// securityGroupPolicyHash identifies a policy by its content, so that it is
+// found regardless of its index, which changes whenever a policy before it is
+// added or removed. The protocol, port and action are case insensitive and an
+// empty protocol or port means all of them.
+func securityGroupPolicyHash(policy *vpcv3.SecurityGroupPolicy) string {
+	var serviceId, serviceGroupId, addressId, addressGroupId string
+	if t := policy.ServiceTemplate; t != nil {
+		serviceId, serviceGroupId = common.StringValue(t.ServiceId), common.StringValue(t.ServiceGroupId)
+	}
+	if t := policy.AddressTemplate; t != nil {
+		addressId, addressGroupId = common.StringValue(t.AddressId), common.StringValue(t.AddressGroupId)
+	}
+	// the API returns empty template objects for the policies without a
+	// protocol template, and ALL for the protocol and port it omits
+	protocol := strings.ToUpper(common.StringValue(policy.Protocol))
+	port := strings.ToUpper(common.StringValue(policy.Port))
+	if serviceId == "" && serviceGroupId == "" {
+		if protocol == "" {
+			protocol = "ALL"
+		}
+		if port == "" {
+			port = "ALL"
+		}
+	}
+	fields := []string{
+		"protocol=" + protocol,
+		"port=" + port,
+		"serviceId=" + serviceId,
+		"serviceGroupId=" + serviceGroupId,
+		"cidrBlock=" + common.StringValue(policy.CidrBlock),
+		"ipv6CidrBlock=" + common.StringValue(policy.Ipv6CidrBlock),
+		"securityGroupId=" + common.StringValue(policy.SecurityGroupId),
+		"addressId=" + addressId,
+		"addressGroupId=" + addressGroupId,
+		"action=" + strings.ToUpper(common.StringValue(policy.Action)),
+		"description=" + common.StringValue(policy.PolicyDescription),
+	}
+	return fmt.Sprintf("%d", hashcode.String(strings.Join(fields, "&")))
+}
+
+// describeSecurityGroupPolicyByHash returns the policy of the direction whose
+// content hash is hash, and the version of the policy set it is found in.
+func describeSecurityGroupPolicyByHash(vpcConn *vpcv3.Client, sgId, direction, hash string) (policy *vpcv3.SecurityGroupPolicy, version string, err error) {
+	set, err := describeSecurityGroupPolicies(vpcConn, sgId)
 	if err != nil {
-		log.Printf("[ERROR] describeSecurityGroupRuleIndex client.SendRequest error:%v", err)
 		return
 	}
+	version = common.StringValue(set.Version)
+	for _, p := range securityGroupPoliciesOf(set, direction) {
+		if securityGroupPolicyHash(p) == hash {
+			policy = p
+			return
+		}
+	}
+	err = errSecurityGroupRuleNotFound
+	return
+}
 
-	var jsonresp struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-		Data    struct {
-			Ingress []struct {
-				Index      int    `json:"index"`
-				CidrIp     string `json:"cidrIp"`
-				IpProtocol string `json:"ipProtocol"`
-				PortRange  string `json:"portRange"`
-				Action     string `json:"action"`
-			}
-			Egress []struct {
-				Index      int    `json:"index"`
-				CidrIp     string `json:"cidrIp"`
-				IpProtocol string `json:"ipProtocol"`
-				PortRange  string `json:"portRange"`
-				Action     string `json:"action"`
+// describeSecurityGroupPolicyByLegacyRule returns the first policy which
+// matches the cidr, protocol, port and action of a rule id created by the
+// earlier versions of the provider, such an id doesn't identify a policy
+// precisely, so it is only used to migrate the id.
+func describeSecurityGroupPolicyByLegacyRule(vpcConn *vpcv3.Client, rule map[string]string) (*vpcv3.SecurityGroupPolicy, error) {
+	set, err := describeSecurityGroupPolicies(vpcConn, rule["sgId"])
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range securityGroupPoliciesOf(set, rule["direction"]) {
+		protocol, port := common.StringValue(p.Protocol), common.StringValue(p.Port)
+		if protocol == "" {
+			protocol = "ALL"
+		}
+		if port == "" {
+			port = "ALL"
+		}
+		if common.StringValue(p.CidrBlock) == rule["cidrIp"] &&
+			strings.EqualFold(protocol, rule["ipProtocol"]) &&
+			strings.EqualFold(port, rule["portRange"]) &&
+			strings.EqualFold(common.StringValue(p.Action), rule["action"]) {
+			return p, nil
+		}
+	}
+	return nil, errSecurityGroupRuleNotFound
+}
+
+// createSecurityGroupPolicy appends the policy to the ingress or egress
+// policies of the security group, a policy with the same content is refused
+// since the two could not be told apart.
+func createSecurityGroupPolicy(vpcConn *vpcv3.Client, sgId, direction string, policy *vpcv3.SecurityGroupPolicy) error {
+	hash := securityGroupPolicyHash(policy)
+	return resource.Retry(securityGroupPolicyRetryTimeout, func() *resource.RetryError {
+		_, version, err := describeSecurityGroupPolicyByHash(vpcConn, sgId, direction, hash)
+		if err == nil {
+			return resource.NonRetryableError(fmt.Errorf("a %v rule with the same content already exists in security group %v, import it instead", direction, sgId))
+		}
+		if err != errSecurityGroupRuleNotFound {
+			return resource.NonRetryableError(err)
+		}
+
+		req := vpcv3.NewCreateSecurityGroupPoliciesRequest()
+		req.SecurityGroupId = common.StringPtr(sgId)
+		req.SecurityGroupPolicySet = newSecurityGroupPolicySet(version, direction, policy)
+		if _, err := vpcConn.CreateSecurityGroupPolicies(req); err != nil {
+			return securityGroupPolicyRetryError(err)
+		}
+		return nil
+	})
+}
+
+// deleteSecurityGroupPolicy deletes the policy by its full content along
+// with the version of the policy set it is found in, so a concurrent change
+// of the security group makes the deletion fail and start over instead of
+// deleting another policy.
+func deleteSecurityGroupPolicy(vpcConn *vpcv3.Client, sgId, direction, hash string) error {
+	return resource.Retry(securityGroupPolicyRetryTimeout, func() *resource.RetryError {
+		policy, version, err := describeSecurityGroupPolicyByHash(vpcConn, sgId, direction, hash)
+		if err != nil {
+			if err == errSecurityGroupRuleNotFound || isNotFound(err) {
+				return nil
 			}
+			return resource.NonRetryableError(err)
+		}
+
+		spec := *policy
+		spec.PolicyIndex = nil
+		spec.ModifyTime = nil
+		req := vpcv3.NewDeleteSecurityGroupPoliciesRequest()
+		req.SecurityGroupId = common.StringPtr(sgId)
+		req.SecurityGroupPolicySet = newSecurityGroupPolicySet(version, direction, &spec)
+		if _, err := vpcConn.DeleteSecurityGroupPolicies(req); err != nil {
+			return securityGroupPolicyRetryError(err)
+		}
+		return nil
+	})
+}
+
+// replaceSecurityGroupPolicies replaces all the policies of the security
+// group with the ingress and egress ones in a single request, in the order
+// they are given since the order is their priority. The request carries the
+// version of the policy set it replaces and starts over if the security group
+// changes in between, an empty set is sent with version 0 which deletes all
+// the policies. A nil direction keeps the policies of the version it replaces.
+func replaceSecurityGroupPolicies(vpcConn *vpcv3.Client, sgId string, ingress, egress []*vpcv3.SecurityGroupPolicy) error {
+	return resource.Retry(securityGroupPolicyRetryTimeout, func() *resource.RetryError {
+		set, err := describeSecurityGroupPolicies(vpcConn, sgId)
+		if err != nil {
+			return resource.NonRetryableError(err)
 		}
+		ingress, egress := ingress, egress
+		if ingress == nil {
+			ingress = keptSecurityGroupPolicies(set.Ingress)
+		}
+		if egress == nil {
+			egress = keptSecurityGroupPolicies(set.Egress)
+		}
+
+		req := vpcv3.NewModifySecurityGroupPoliciesRequest()
+		req.SecurityGroupId = common.StringPtr(sgId)
+		req.SecurityGroupPolicySet = &vpcv3.SecurityGroupPolicySet{
+			Version: set.Version,
+			Ingress: ingress,
+			Egress:  egress,
+		}
+		if len(ingress) == 0 && len(egress) == 0 {
+			req.SecurityGroupPolicySet.Version = common.StringPtr("0")
+		}
+		if _, err := vpcConn.ModifySecurityGroupPolicies(req); err != nil {
+			return securityGroupPolicyRetryError(err)
+		}
+		return nil
+	})
+}
+
+// keptSecurityGroupPolicies returns the described policies to be sent back as
+// they are, their index is given by their order.
+func keptSecurityGroupPolicies(policies []*vpcv3.SecurityGroupPolicy) []*vpcv3.SecurityGroupPolicy {
+	kept := make([]*vpcv3.SecurityGroupPolicy, 0, len(policies))
+	for _, policy := range policies {
+		policy.PolicyIndex = nil
+		kept = append(kept, policy)
 	}
-	err = json.Unmarshal([]byte(response), &jsonresp)
-	if err != nil {
-		log.Printf("[ERROR] describeSecurityGroupRules json.Unmarshal error:%v", err)
-		return
+	return kept
+}
+
+func newSecurityGroupPolicySet(version, direction string, policies ...*vpcv3.SecurityGroupPolicy) *vpcv3.SecurityGroupPolicySet {
+	set := &vpcv3.SecurityGroupPolicySet{}
+	if version != "" {
+		set.Version = common.StringPtr(version)
+	}
+	if strings.EqualFold(direction, "egress") {
+		set.Egress = policies
+	} else {
+		set.Ingress = policies
 	}
+	return set
+}
 
-	if jsonresp.Code != 0 {
-		log.Printf("[ERROR] describeSecurityGroupRuleIndex error, code:%v, message:%v", jsonresp.Code, jsonresp.Message)
-		err = fmt.Errorf(jsonresp.Message)
-		return
+func securityGroupPolicyRetryError(err error) *resource.RetryError {
+	if apiErr, ok := err.(*common.APIError); ok && apiErr.Code == securityGroupPolicyVersionMismatch {
+		log.Printf("[DEBUG] security group policies changed concurrently, retrying: %v", err)
+		return resource.RetryableError(err)
 	}
+	return resource.NonRetryableError(err)
+}
 
-	rules := jsonresp.Data
-	_rule := rules.Ingress
-	if rule["direction"] == "egress" {
-		_rule = rules.Egress
+func describeInstanceSecurityGroups(cvmConn *cvm.Client, instanceId string) ([]string, error) {
+	instance, err := describeInstanceById(cvmConn, instanceId)
+	if err != nil {
+		return nil, err
 	}
+	return common.StringValues(instance.SecurityGroupIds), nil
+}
 
-	exist := false
+// attachSecurityGroupToInstance appends the security group to the ones of the
+// instance, which keep their order, i.e. their priority.
+func attachSecurityGroupToInstance(cvmConn *cvm.Client, sgId, instanceId string) error {
+	instanceSecurityGroupsMu.Lock()
+	defer instanceSecurityGroupsMu.Unlock()
+
+	sgIds, err := describeInstanceSecurityGroups(cvmConn, instanceId)
+	if err != nil {
+		return err
+	}
+	if containsSecurityGroup(sgIds, sgId) {
+		return nil
+	}
+	return bindInstanceWithSgIds(cvmConn, instanceId, append(sgIds, sgId))
+}
 
-	for _, r := range _rule {
+// detachSecurityGroupFromInstance removes the security group from the ones of
+// the instance, an instance is not allowed to have no security group.
+func detachSecurityGroupFromInstance(cvmConn *cvm.Client, sgId, instanceId string) error {
+	instanceSecurityGroupsMu.Lock()
+	defer instanceSecurityGroupsMu.Unlock()
 
-		r.IpProtocol = strings.ToLower(r.IpProtocol)
-		r.PortRange = strings.ToLower(r.PortRange)
-		r.Action = strings.ToLower(r.Action)
-		rule["ipProtocol"] = strings.ToLower(rule["ipProtocol"])
-		rule["portRange"] = strings.ToLower(rule["portRange"])
-		rule["action"] = strings.ToLower(rule["action"])
+	sgIds, err := describeInstanceSecurityGroups(cvmConn, instanceId)
+	if err != nil {
+		return err
+	}
+	remaining := make([]string, 0, len(sgIds))
+	for _, id := range sgIds {
+		if id != sgId {
+			remaining = append(remaining, id)
+		}
+	}
+	if len(remaining) == len(sgIds) {
+		return nil
+	}
+	if len(remaining) == 0 {
+		return fmt.Errorf("security group %v is the last one of instance %v, which can not be detached", sgId, instanceId)
+	}
+	return bindInstanceWithSgIds(cvmConn, instanceId, remaining)
+}
 
-		if r.CidrIp == rule["cidrIp"] && r.IpProtocol == rule["ipProtocol"] && r.PortRange == rule["portRange"] && r.Action == rule["action"] {
-			exist = true
-			index = r.Index
-			break
+func describeNetworkInterfaceSecurityGroups(vpcConn *vpcv3.Client, networkInterfaceId string) ([]string, error) {
+	req := vpcv3.NewDescribeNetworkInterfacesRequest()
+	req.NetworkInterfaceIds = []*string{common.StringPtr(networkInterfaceId)}
+	resp, err := vpcConn.DescribeNetworkInterfaces(req)
+	if err != nil {
+		if isNotFound(err) {
+			err = errNetworkInterfaceNotFound
 		}
+		return nil, err
 	}
+	if len(resp.Response.NetworkInterfaceSet) == 0 {
+		return nil, errNetworkInterfaceNotFound
+	}
+	return common.StringValues(resp.Response.NetworkInterfaceSet[0].GroupSet), nil
+}
+
+func attachSecurityGroupToNetworkInterface(vpcConn *vpcv3.Client, sgId, networkInterfaceId string) error {
+	req := vpcv3.NewAssociateNetworkInterfaceSecurityGroupsRequest()
+	req.NetworkInterfaceIds = []*string{common.StringPtr(networkInterfaceId)}
+	req.SecurityGroupIds = []*string{common.StringPtr(sgId)}
+	_, err := vpcConn.AssociateNetworkInterfaceSecurityGroups(req)
+	return err
+}
+
+func detachSecurityGroupFromNetworkInterface(vpcConn *vpcv3.Client, sgId, networkInterfaceId string) error {
+	req := vpcv3.NewDisassociateNetworkInterfaceSecurityGroupsRequest()
+	req.NetworkInterfaceIds = []*string{common.StringPtr(networkInterfaceId)}
+	req.SecurityGroupIds = []*string{common.StringPtr(sgId)}
+	_, err := vpcConn.DisassociateNetworkInterfaceSecurityGroups(req)
+	return err
+}
 
-	if exist == false {
-		err = errSecurityGroupRuleNotFound
+func describeLoadBalancerSecurityGroups(clbConn *clb.Client, loadBalancerId string) ([]string, error) {
+	req := clb.NewDescribeLoadBalancersRequest()
+	req.LoadBalancerIds = []*string{common.StringPtr(loadBalancerId)}
+	resp, err := clbConn.DescribeLoadBalancers(req)
+	if err != nil {
+		if isNotFound(err) {
+			err = errLoadBalancerNotFound
+		}
+		return nil, err
+	}
+	if len(resp.Response.LoadBalancerSet) == 0 {
+		return nil, errLoadBalancerNotFound
 	}
+	return common.StringValues(resp.Response.LoadBalancerSet[0].SecureGroups), nil
+}
 
-	return
+// setLoadBalancerSecurityGroup adds the security group to the load balancer
+// or removes it, depending on operation, i.e. ADD or DEL.
+func setLoadBalancerSecurityGroup(clbConn *clb.Client, sgId, loadBalancerId, operation string) error {
+	req := clb.NewSetSecurityGroupForLoadbalancersRequest()
+	req.SecurityGroup = common.StringPtr(sgId)
+	req.OperationType = common.StringPtr(operation)
+	req.LoadBalancerIds = []*string{common.StringPtr(loadBalancerId)}
+	_, err := clbConn.SetSecurityGroupForLoadbalancers(req)
+	return err
 }
 
 func getSecurityGroupAssociatedInstancesBySgId(client *client.Client, sgId string) (instanceIds []string, err error) {
@@ -118,7 +396,7 @@ func getSecurityGroupAssociatedInstancesBySgId(client *client.Client, sgId strin
 	}
 
 	if jsonresp.Code != 0 {
-		err = fmt.Errorf(jsonresp.Message)
+		err = errors.New(jsonresp.Message)
 		return
 	}
 	if jsonresp.TotalCount == 0 {
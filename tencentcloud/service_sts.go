@@ -0,0 +1,124 @@
+package tencentcloud
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	sts "github.com/zqfan/tencentcloud-sdk-go/services/sts/v20180813"
+)
+
+const (
+	stsDefaultSessionDuration = 7200
+	stsMaxSessionDuration     = 43200
+	// refresh the temporary credential before it expires, so a request
+	// signed right before the expiration still has time to reach the server
+	stsRefreshAhead = 5 * time.Minute
+)
+
+var errAssumeRoleNoCredential = errors.New("AssumeRole returned no credential")
+
+// stsCredential implements common.Credential with the temporary credential
+// of an assumed role, it is shared by all clients and refreshed on demand.
+type stsCredential struct {
+	mu sync.Mutex
+
+	conn *sts.Client
+	role AssumeRoleConfig
+
+	secretId    string
+	secretKey   string
+	token       string
+	expiredTime time.Time
+}
+
+func newStsCredential(conn *sts.Client, role AssumeRoleConfig) (*stsCredential, error) {
+	if role.SessionDuration <= 0 {
+		role.SessionDuration = stsDefaultSessionDuration
+	}
+	c := &stsCredential{
+		conn: conn,
+		role: role,
+	}
+	if err := c.refresh(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *stsCredential) refresh() error {
+	req := sts.NewAssumeRoleRequest()
+	req.RoleArn = common.StringPtr(c.role.RoleArn)
+	req.RoleSessionName = common.StringPtr(c.role.SessionName)
+	req.DurationSeconds = common.IntPtr(c.role.SessionDuration)
+	if c.role.Policy != "" {
+		// the policy is required to be url encoded
+		req.Policy = common.StringPtr(url.QueryEscape(c.role.Policy))
+	}
+	resp, err := c.conn.AssumeRole(req)
+	if err != nil {
+		return err
+	}
+	if resp.Response == nil || resp.Response.Credentials == nil || resp.Response.ExpiredTime == nil {
+		return errAssumeRoleNoCredential
+	}
+	credentials := resp.Response.Credentials
+	if credentials.TmpSecretId == nil || credentials.TmpSecretKey == nil || credentials.Token == nil {
+		return errAssumeRoleNoCredential
+	}
+
+	c.secretId = *credentials.TmpSecretId
+	c.secretKey = *credentials.TmpSecretKey
+	c.token = *credentials.Token
+	c.expiredTime = time.Unix(*resp.Response.ExpiredTime, 0)
+	log.Printf("[DEBUG] assume role %v, temporary credential expires at %v", c.role.RoleArn, c.expiredTime)
+	return nil
+}
+
+func (c *stsCredential) refreshAhead() time.Duration {
+	ahead := time.Duration(c.role.SessionDuration) * time.Second / 2
+	if ahead > stsRefreshAhead {
+		ahead = stsRefreshAhead
+	}
+	return ahead
+}
+
+func (c *stsCredential) current() (secretId, secretKey, token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Now().Add(c.refreshAhead()).After(c.expiredTime) {
+		// keep using the old one on failure, the API will tell if it expires
+		if err := c.refresh(); err != nil {
+			log.Printf("[WARN] refresh temporary credential of role %v failed: %v", c.role.RoleArn, err)
+		}
+	}
+	return c.secretId, c.secretKey, c.token
+}
+
+func (c *stsCredential) GetSecretId() string {
+	secretId, _, _ := c.current()
+	return secretId
+}
+
+func (c *stsCredential) GetSecretKey() string {
+	_, secretKey, _ := c.current()
+	return secretKey
+}
+
+func (c *stsCredential) GetCredentialParams() map[string]string {
+	secretId, _, token := c.current()
+	return map[string]string{
+		"SecretId": secretId,
+		"Token":    token,
+	}
+}
+
+// Snapshot implements common.RefreshingCredential, the clients sign a request
+// with the snapshot, so a refresh in between can not mix two credentials.
+func (c *stsCredential) Snapshot() common.Credential {
+	return common.NewTokenCredential(c.current())
+}
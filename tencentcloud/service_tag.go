@@ -0,0 +1,132 @@
+package tencentcloud
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	tag "github.com/zqfan/tencentcloud-sdk-go/services/tag/v20180813"
+)
+
+// tagsSchema returns the schema of the tags of a taggable resource, they are
+// merged with the default_tags of the provider before they are applied.
+func tagsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeMap,
+		Optional: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// tagResourceName returns the six-segment name of a resource in the tag
+// service, e.g. qcs::cvm:ap-guangzhou:uin/:instance/ins-xxx, the account can
+// be omitted.
+func tagResourceName(serviceType, resourcePrefix, region, id string) string {
+	return fmt.Sprintf("qcs::%s:%s:uin/:%s/%s", serviceType, region, resourcePrefix, id)
+}
+
+// mergeDefaultTags returns the default tags overridden by the tags of the
+// resource.
+func (client *TencentCloudClient) mergeDefaultTags(tags map[string]interface{}) map[string]string {
+	merged := make(map[string]string)
+	for k, v := range client.pool.config.DefaultTags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v.(string)
+	}
+	return merged
+}
+
+// updateResourceTags applies the tags of the resource merged with the
+// default tags, and deletes the ones which are no longer wanted. It is called
+// on create and when the tags change.
+func updateResourceTags(client *TencentCloudClient, d *schema.ResourceData, serviceType, resourcePrefix string) error {
+	o, n := d.GetChange("tags")
+	oldTags := client.mergeDefaultTags(o.(map[string]interface{}))
+	newTags := client.mergeDefaultTags(n.(map[string]interface{}))
+
+	req := tag.NewModifyResourceTagsRequest()
+	req.Resource = common.StringPtr(tagResourceName(serviceType, resourcePrefix, client.region, d.Id()))
+	for _, k := range sortedTagKeys(newTags) {
+		req.ReplaceTags = append(req.ReplaceTags, &tag.Tag{
+			TagKey:   common.StringPtr(k),
+			TagValue: common.StringPtr(newTags[k]),
+		})
+	}
+	for _, k := range sortedTagKeys(oldTags) {
+		if _, ok := newTags[k]; !ok {
+			req.DeleteTags = append(req.DeleteTags, &tag.TagKeyObject{TagKey: common.StringPtr(k)})
+		}
+	}
+	if len(req.ReplaceTags) == 0 && len(req.DeleteTags) == 0 {
+		return nil
+	}
+	_, err := client.tagConn.ModifyResourceTags(req)
+	return err
+}
+
+// readResourceTags sets the tags of the resource, a default tag is left out
+// unless it is also in the tags of the resource or its value is changed
+// outside of Terraform, so that the default tags cause no diff. A default tag
+// which is missing is set to an empty value, so the diff adds it back.
+func readResourceTags(client *TencentCloudClient, d *schema.ResourceData, serviceType, resourcePrefix string) error {
+	current := d.Get("tags").(map[string]interface{})
+	defaultTags := client.pool.config.DefaultTags
+
+	req := tag.NewDescribeResourceTagsByResourceIdsRequest()
+	req.ServiceType = common.StringPtr(serviceType)
+	req.ResourcePrefix = common.StringPtr(resourcePrefix)
+	req.ResourceRegion = common.StringPtr(client.region)
+	req.ResourceIds = []*string{common.StringPtr(d.Id())}
+	req.Limit = common.IntPtr(100)
+	resp, err := client.tagConn.DescribeResourceTagsByResourceIds(req)
+	if err != nil {
+		// a user who does not use tags may not be allowed to read them either
+		if isUnauthorized(err) && len(current) == 0 && len(defaultTags) == 0 {
+			log.Printf("[WARN] not allowed to read the tags of %v, skipped: %v", d.Id(), err)
+			return nil
+		}
+		return err
+	}
+
+	tags := make(map[string]string)
+	for _, t := range resp.Response.Tags {
+		if t.TagKey == nil || t.TagValue == nil {
+			continue
+		}
+		k, v := *t.TagKey, *t.TagValue
+		if defaultValue, ok := defaultTags[k]; ok && defaultValue == v {
+			if _, ok := current[k]; !ok {
+				continue
+			}
+		}
+		tags[k] = v
+	}
+	for k := range defaultTags {
+		if !hasResourceTag(resp.Response.Tags, k) {
+			tags[k] = ""
+		}
+	}
+	return d.Set("tags", tags)
+}
+
+func hasResourceTag(tags []*tag.TagResource, key string) bool {
+	for _, t := range tags {
+		if t.TagKey != nil && *t.TagKey == key {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedTagKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -7,51 +7,106 @@ import (
 	"log"
 	"time"
 
-	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/zqfan/tencentcloud-sdk-go/client"
 	"github.com/zqfan/tencentcloud-sdk-go/common"
 	vpc "github.com/zqfan/tencentcloud-sdk-go/services/vpc/unversioned"
 )
 
 var (
-	dnatNotFound = errors.New("DNAT Not found")
+	dnatNotFound      = errors.New("DNAT Not found")
+	errSubnetNotFound = errors.New("subnet not found")
+
+	errPeeringConnectionNotFound = errors.New("vpc peering connection not found")
 )
 
-func (client *TencentCloudClient) PollingVpcTaskResult(taskId *int) (status bool, err error) {
+// describeSubnetVpcId returns the vpc of a subnet, DescribeSubnet requires
+// both ids while an imported subnet knows only its own.
+func describeSubnetVpcId(client *client.Client, subnetId string) (vpcId string, err error) {
+	params := map[string]string{
+		"Action":   "DescribeSubnetEx",
+		"subnetId": subnetId,
+	}
+	response, err := sendRequest(client, "vpc", params)
+	if err != nil {
+		return
+	}
+	var jsonresp struct {
+		Data []struct {
+			VpcId    string `json:"vpcId"`
+			SubnetId string `json:"subnetId"`
+		} `json:"data"`
+	}
+	err = json.Unmarshal([]byte(response), &jsonresp)
+	if err != nil {
+		return
+	}
+	for _, subnet := range jsonresp.Data {
+		if subnet.SubnetId == subnetId {
+			vpcId = subnet.VpcId
+			return
+		}
+	}
+	err = errSubnetNotFound
+	return
+}
+
+// vpcTaskStates maps the status of an asynchronous vpc task or bill to a state
+// of a waiter.
+var vpcTaskStates = map[int]string{
+	vpc.BillStatusSuccess: "SUCCESS",
+	vpc.BillStatusFail:    "FAILED",
+	vpc.BillStatusDoing:   "DOING",
+}
+
+func (client *TencentCloudClient) PollingVpcTaskResult(taskId *int, timeout time.Duration) (status bool, err error) {
 	taskReq := vpc.NewDescribeVpcTaskResultRequest()
 	taskReq.TaskId = taskId
-	status = false
-	err = resource.Retry(3*time.Minute, func() *resource.RetryError {
-		taskResp, err := client.vpcConn.DescribeVpcTaskResult(taskReq)
-		b, _ := json.Marshal(taskResp)
-		log.Printf("[DEBUG] client.vpcConn.DescribeVpcTaskResult response: %s", b)
-		if _, ok := err.(*common.APIError); ok {
-			return resource.NonRetryableError(fmt.Errorf("client.vpcConn.CreateNatGateway error: %v", err))
-		}
-		if *taskResp.Data.Status == 0 {
-			status = true
-			return nil
-		}
-		return resource.RetryableError(fmt.Errorf("taskId %v, not ready, status: %v", taskId, *taskResp.Data.Status))
-	})
+	w := &waiter{
+		Name:    fmt.Sprintf("vpc task %v", *taskId),
+		Pending: []string{vpcTaskStates[vpc.BillStatusDoing]},
+		Target:  []string{vpcTaskStates[vpc.BillStatusSuccess]},
+		Failed:  []string{vpcTaskStates[vpc.BillStatusFail]},
+		Refresh: func() (interface{}, string, error) {
+			taskResp, err := client.vpcConn.DescribeVpcTaskResult(taskReq)
+			if err != nil {
+				return nil, "", err
+			}
+			b, _ := json.Marshal(taskResp)
+			log.Printf("[DEBUG] client.vpcConn.DescribeVpcTaskResult response: %s", b)
+			return taskResp, vpcTaskStates[*taskResp.Data.Status], nil
+		},
+		Timeout: timeout,
+	}
+	if _, err = w.Wait(); err != nil {
+		return
+	}
+	status = true
 	return
 }
 
-func (client *TencentCloudClient) PollingVpcBillResult(billId *string) (status bool, err error) {
+func (client *TencentCloudClient) PollingVpcBillResult(billId *string, timeout time.Duration) (status bool, err error) {
 	queryReq := vpc.NewQueryNatGatewayProductionStatusRequest()
 	queryReq.BillId = billId
-	status = false
-	err = resource.Retry(3*time.Minute, func() *resource.RetryError {
-		queryResp, err := client.vpcConn.QueryNatGatewayProductionStatus(queryReq)
-		b, _ := json.Marshal(queryResp)
-		log.Printf("[DEBUG] client.vpcConn.QueryNatGatewayProductionStatus response: %s", b)
-		if _, ok := err.(*common.APIError); ok {
-			return resource.NonRetryableError(fmt.Errorf("client.vpcConn.QueryNatGatewayProductionStatus error: %v", err))
-		}
-		if *queryResp.Data.Status == vpc.BillStatusSuccess {
-			return nil
-		}
-		return resource.RetryableError(fmt.Errorf("billId %v, not ready, status: %v", billId, *queryResp.Data.Status))
-	})
+	w := &waiter{
+		Name:    fmt.Sprintf("vpc bill %v", *billId),
+		Pending: []string{vpcTaskStates[vpc.BillStatusDoing]},
+		Target:  []string{vpcTaskStates[vpc.BillStatusSuccess]},
+		Failed:  []string{vpcTaskStates[vpc.BillStatusFail]},
+		Refresh: func() (interface{}, string, error) {
+			queryResp, err := client.vpcConn.QueryNatGatewayProductionStatus(queryReq)
+			if err != nil {
+				return nil, "", err
+			}
+			b, _ := json.Marshal(queryResp)
+			log.Printf("[DEBUG] client.vpcConn.QueryNatGatewayProductionStatus response: %s", b)
+			return queryResp, vpcTaskStates[*queryResp.Data.Status], nil
+		},
+		Timeout: timeout,
+	}
+	if _, err = w.Wait(); err != nil {
+		return
+	}
+	status = true
 	return
 }
 
@@ -62,7 +117,12 @@ func (client *TencentCloudClient) DescribeDnat(entry *vpc.DnaptRule) (response *
 	descResp, descErr := client.vpcConn.GetDnaptRule(descReq)
 	b, _ := json.Marshal(descResp)
 	log.Printf("[DEBUG] client.vpcConn.GetDnaptRule response: %s", b)
-	if _, ok := descErr.(*common.APIError); ok {
+	if descErr != nil {
+		// the rule is gone with its nat gateway
+		if isNotFound(descErr) {
+			err = dnatNotFound
+			return
+		}
 		err = fmt.Errorf("client.vpcConn.GetDnaptRule error: %v", descErr)
 		return
 	}
@@ -81,3 +141,86 @@ func (client *TencentCloudClient) DescribeDnat(entry *vpc.DnaptRule) (response *
 	}
 	return
 }
+
+// vpcPeeringConnectionStates maps the state of a vpc peering connection to the
+// value of the state attribute.
+var vpcPeeringConnectionStates = map[int]string{
+	vpc.PeeringConnectionStatePending:  "pending",
+	vpc.PeeringConnectionStateActive:   "active",
+	vpc.PeeringConnectionStateExpired:  "expired",
+	vpc.PeeringConnectionStateRejected: "rejected",
+	vpc.PeeringConnectionStateDeleted:  "deleted",
+}
+
+// describeVpcPeeringConnection returns a peering connection seen from either
+// of its sides. The cross-region ones are listed by another action, so they
+// are looked up after the ones within the region.
+func describeVpcPeeringConnection(conn *vpc.Client, peeringConnectionId string) (*vpc.VpcPeeringConnection, error) {
+	descReq := vpc.NewDescribeVpcPeeringConnectionsRequest()
+	descReq.PeeringConnectionId = common.StringPtr(peeringConnectionId)
+	descResp, err := conn.DescribeVpcPeeringConnections(descReq)
+	b, _ := json.Marshal(descResp)
+	log.Printf("[DEBUG] conn.DescribeVpcPeeringConnections response: %s", b)
+	if err != nil && !isNotFound(err) {
+		return nil, fmt.Errorf("conn.DescribeVpcPeeringConnections error: %v", err)
+	}
+	if err == nil {
+		for _, peering := range descResp.Data {
+			if common.StringValue(peering.PeeringConnectionId) == peeringConnectionId {
+				return peering, nil
+			}
+		}
+	}
+
+	descExReq := vpc.NewDescribeVpcPeeringConnectionsExRequest()
+	descExReq.PeeringConnectionId = common.StringPtr(peeringConnectionId)
+	descExResp, err := conn.DescribeVpcPeeringConnectionsEx(descExReq)
+	b, _ = json.Marshal(descExResp)
+	log.Printf("[DEBUG] conn.DescribeVpcPeeringConnectionsEx response: %s", b)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, errPeeringConnectionNotFound
+		}
+		return nil, fmt.Errorf("conn.DescribeVpcPeeringConnectionsEx error: %v", err)
+	}
+	for _, peering := range descExResp.Data {
+		if common.StringValue(peering.PeeringConnectionId) == peeringConnectionId {
+			return peering, nil
+		}
+	}
+	return nil, errPeeringConnectionNotFound
+}
+
+// isCrossRegionPeeringConnection reports whether the vpcs of a peering
+// connection are in different regions, such a connection is managed with the
+// Ex actions, which are asynchronous.
+func isCrossRegionPeeringConnection(peering *vpc.VpcPeeringConnection) bool {
+	return peering.PeerRegion != nil && *peering.PeerRegion != "" && *peering.PeerRegion != common.StringValue(peering.Region)
+}
+
+// acceptVpcPeeringConnection accepts a peering connection requested by
+// another account, it must be called in the region of the peer vpc.
+func (client *TencentCloudClient) acceptVpcPeeringConnection(peering *vpc.VpcPeeringConnection, timeout time.Duration) error {
+	if !isCrossRegionPeeringConnection(peering) {
+		acceptReq := vpc.NewAcceptVpcPeeringConnectionRequest()
+		acceptReq.PeeringConnectionId = peering.PeeringConnectionId
+		acceptResp, err := client.vpcConn.AcceptVpcPeeringConnection(acceptReq)
+		b, _ := json.Marshal(acceptResp)
+		log.Printf("[DEBUG] client.vpcConn.AcceptVpcPeeringConnection response: %s", b)
+		if err != nil {
+			return fmt.Errorf("client.vpcConn.AcceptVpcPeeringConnection error: %v", err)
+		}
+		return nil
+	}
+
+	acceptReq := vpc.NewAcceptVpcPeeringConnectionExRequest()
+	acceptReq.PeeringConnectionId = peering.PeeringConnectionId
+	acceptResp, err := client.vpcConn.AcceptVpcPeeringConnectionEx(acceptReq)
+	b, _ := json.Marshal(acceptResp)
+	log.Printf("[DEBUG] client.vpcConn.AcceptVpcPeeringConnectionEx response: %s", b)
+	if err != nil {
+		return fmt.Errorf("client.vpcConn.AcceptVpcPeeringConnectionEx error: %v", err)
+	}
+	_, err = client.PollingVpcTaskResult(acceptResp.TaskId, timeout)
+	return err
+}
@@ -0,0 +1,140 @@
+package tencentcloud
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/zqfan/tencentcloud-sdk-go/common"
+	vpc "github.com/zqfan/tencentcloud-sdk-go/services/vpc/unversioned"
+)
+
+var (
+	errVpnGatewayNotFound      = errors.New("vpn gateway not found")
+	errCustomerGatewayNotFound = errors.New("vpn customer gateway not found")
+	errVpnConnectionNotFound   = errors.New("vpn connection not found")
+)
+
+// vpnDescribeLimit is the page size of the describe actions of vpn.
+const vpnDescribeLimit = 100
+
+// vpnStates maps the state of a vpn gateway or a vpn connection to the value
+// of the state attribute.
+var vpnStates = map[int]string{
+	vpc.VpnGwStateCreating:  "creating",
+	vpc.VpnGwStateAvailable: "available",
+	vpc.VpnGwStateDeleting:  "deleting",
+}
+
+// describeVpnGateways lists all the vpn gateways matching the request, page by
+// page. The errors are returned as is, so that isNotFound works on them.
+func describeVpnGateways(conn *vpc.Client, descReq *vpc.DescribeVpnGwRequest) ([]*vpc.VpnGw, error) {
+	var gateways []*vpc.VpnGw
+	descReq.Limit = common.IntPtr(vpnDescribeLimit)
+	for {
+		descReq.Offset = common.IntPtr(len(gateways))
+		descResp, err := conn.DescribeVpnGw(descReq)
+		b, _ := json.Marshal(descResp)
+		log.Printf("[DEBUG] conn.DescribeVpnGw response: %s", b)
+		if err != nil {
+			return nil, err
+		}
+		gateways = append(gateways, descResp.Data...)
+		if len(descResp.Data) == 0 || descResp.TotalCount == nil || len(gateways) >= *descResp.TotalCount {
+			return gateways, nil
+		}
+	}
+}
+
+func describeVpnGateway(conn *vpc.Client, vpnGatewayId string) (*vpc.VpnGw, error) {
+	descReq := vpc.NewDescribeVpnGwRequest()
+	descReq.VpnGwId = common.StringPtr(vpnGatewayId)
+	gateways, err := describeVpnGateways(conn, descReq)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, errVpnGatewayNotFound
+		}
+		return nil, err
+	}
+	for _, gateway := range gateways {
+		if *gateway.VpnGwId == vpnGatewayId {
+			return gateway, nil
+		}
+	}
+	return nil, errVpnGatewayNotFound
+}
+
+// describeCustomerGateways lists all the customer gateways matching the
+// request, page by page.
+func describeCustomerGateways(conn *vpc.Client, descReq *vpc.DescribeUserGwRequest) ([]*vpc.UserGw, error) {
+	var gateways []*vpc.UserGw
+	descReq.Limit = common.IntPtr(vpnDescribeLimit)
+	for {
+		descReq.Offset = common.IntPtr(len(gateways))
+		descResp, err := conn.DescribeUserGw(descReq)
+		b, _ := json.Marshal(descResp)
+		log.Printf("[DEBUG] conn.DescribeUserGw response: %s", b)
+		if err != nil {
+			return nil, err
+		}
+		gateways = append(gateways, descResp.Data...)
+		if len(descResp.Data) == 0 || descResp.TotalCount == nil || len(gateways) >= *descResp.TotalCount {
+			return gateways, nil
+		}
+	}
+}
+
+func describeCustomerGateway(conn *vpc.Client, customerGatewayId string) (*vpc.UserGw, error) {
+	descReq := vpc.NewDescribeUserGwRequest()
+	descReq.UserGwId = common.StringPtr(customerGatewayId)
+	gateways, err := describeCustomerGateways(conn, descReq)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, errCustomerGatewayNotFound
+		}
+		return nil, err
+	}
+	for _, gateway := range gateways {
+		if *gateway.UserGwId == customerGatewayId {
+			return gateway, nil
+		}
+	}
+	return nil, errCustomerGatewayNotFound
+}
+
+// describeVpnConnections lists all the vpn connections matching the request,
+// page by page. The responses are not logged here as they carry the pre-shared
+// keys, the client logs them redacted.
+func describeVpnConnections(conn *vpc.Client, descReq *vpc.DescribeVpnConnRequest) ([]*vpc.VpnConn, error) {
+	var connections []*vpc.VpnConn
+	descReq.Limit = common.IntPtr(vpnDescribeLimit)
+	for {
+		descReq.Offset = common.IntPtr(len(connections))
+		descResp, err := conn.DescribeVpnConn(descReq)
+		if err != nil {
+			return nil, err
+		}
+		connections = append(connections, descResp.Data...)
+		if len(descResp.Data) == 0 || descResp.TotalCount == nil || len(connections) >= *descResp.TotalCount {
+			return connections, nil
+		}
+	}
+}
+
+func describeVpnConnection(conn *vpc.Client, vpnConnectionId string) (*vpc.VpnConn, error) {
+	descReq := vpc.NewDescribeVpnConnRequest()
+	descReq.VpnConnId = common.StringPtr(vpnConnectionId)
+	connections, err := describeVpnConnections(conn, descReq)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, errVpnConnectionNotFound
+		}
+		return nil, err
+	}
+	for _, connection := range connections {
+		if *connection.VpnConnId == vpnConnectionId {
+			return connection, nil
+		}
+	}
+	return nil, errVpnConnectionNotFound
+}
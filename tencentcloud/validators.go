@@ -1,6 +1,7 @@
 package tencentcloud
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net"
 	"regexp"
@@ -240,3 +241,18 @@ func validatePort(v interface{}, k string) (ws []string, errors []error) {
 	}
 	return
 }
+
+// validateUserData ensures the value is base64 encoded and is at most 16KB
+// before encoding, which is the limit of CVM.
+func validateUserData(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q must be base64 encoded: %v", k, err))
+		return
+	}
+	if len(decoded) > 16*1024 {
+		errors = append(errors, fmt.Errorf("%q must be at most 16KB before encoding, got %d bytes", k, len(decoded)))
+	}
+	return
+}
@@ -0,0 +1,91 @@
+package tencentcloud
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+// defaultWaiterMinTimeout is the smallest time between two refreshes of a
+// waiter, the time doubles on every refresh up to 10 seconds.
+const defaultWaiterMinTimeout = 500 * time.Millisecond
+
+// waiter waits for a resource to reach one of the target states, it is a
+// resource.StateChangeConf which reports the failures of all resources in the
+// same way and stops as soon as the resource reaches a terminal failure state.
+type waiter struct {
+	// Name identifies the resource in the errors, e.g. "instance ins-xxx".
+	Name string
+	// Pending are the states to keep waiting in, a state which is neither
+	// pending, target nor failed stops the wait. If it is empty all the other
+	// states are pending.
+	Pending []string
+	// Target are the states to wait for. If it is empty the wait is for the
+	// resource to be gone.
+	Target []string
+	// Failed are the terminal states which mean the operation failed.
+	Failed []string
+	// Refresh returns the resource and its state, the resource is nil if it
+	// is not found.
+	Refresh resource.StateRefreshFunc
+	Timeout time.Duration
+	// Delay is the time to wait before the first refresh, for the operations
+	// which take at least a while or whose result is not visible at once.
+	Delay time.Duration
+	// PollInterval is the time between two refreshes, if it is zero the time
+	// backs off from defaultWaiterMinTimeout.
+	PollInterval time.Duration
+	// NotFoundChecks is how many refreshes in a row may not find the resource
+	// before the wait fails, e.g. a new resource may not be listed at once.
+	// Zero means the default of resource.StateChangeConf, which is 20.
+	NotFoundChecks int
+}
+
+// Wait waits until the resource reaches a target state and returns the result
+// of the last refresh. An error returned by Refresh is returned as is.
+func (w *waiter) Wait() (interface{}, error) {
+	conf := &resource.StateChangeConf{
+		Pending:        w.Pending,
+		Target:         w.Target,
+		Timeout:        w.Timeout,
+		Delay:          w.Delay,
+		MinTimeout:     defaultWaiterMinTimeout,
+		PollInterval:   w.PollInterval,
+		NotFoundChecks: w.NotFoundChecks,
+		Refresh: func() (interface{}, string, error) {
+			result, state, err := w.Refresh()
+			if err != nil {
+				return nil, "", err
+			}
+			log.Printf("[DEBUG] waiting for %v to become %v, current state: %v", w.Name, w.targetString(), state)
+			for _, failed := range w.Failed {
+				if result != nil && state == failed {
+					return result, state, fmt.Errorf("%v is in failed state %v", w.Name, state)
+				}
+			}
+			return result, state, nil
+		},
+	}
+	result, err := conf.WaitForState()
+	switch e := err.(type) {
+	case *resource.TimeoutError:
+		if e.LastError != nil {
+			return nil, fmt.Errorf("timeout after %v waiting for %v to become %v: %v", w.Timeout, w.Name, w.targetString(), e.LastError)
+		}
+		return nil, fmt.Errorf("timeout after %v waiting for %v to become %v, last state: %v", w.Timeout, w.Name, w.targetString(), e.LastState)
+	case *resource.UnexpectedStateError:
+		return nil, fmt.Errorf("%v is in unexpected state %v while waiting for it to become %v", w.Name, e.State, w.targetString())
+	case *resource.NotFoundError:
+		return nil, fmt.Errorf("%v not found while waiting for it to become %v", w.Name, w.targetString())
+	}
+	return result, err
+}
+
+func (w *waiter) targetString() string {
+	if len(w.Target) == 0 {
+		return "deleted"
+	}
+	return fmt.Sprintf("%v", w.Target)
+}
@@ -0,0 +1,128 @@
+package tencentcloud
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testWaiterRefresh returns the states in order and keeps returning the last
+// one, an empty state means the resource is not found.
+func testWaiterRefresh(states ...string) func() (interface{}, string, error) {
+	i := 0
+	return func() (interface{}, string, error) {
+		state := states[i]
+		if i < len(states)-1 {
+			i++
+		}
+		if state == "" {
+			return nil, "", nil
+		}
+		return state, state, nil
+	}
+}
+
+func TestWaiter(t *testing.T) {
+	errRefresh := errors.New("refresh failed")
+	cases := []struct {
+		name    string
+		waiter  waiter
+		wantErr string
+	}{
+		{
+			name: "target",
+			waiter: waiter{
+				Pending: []string{"PENDING"},
+				Target:  []string{"RUNNING"},
+				Refresh: testWaiterRefresh("PENDING", "PENDING", "RUNNING"),
+			},
+		},
+		{
+			name: "not found at first",
+			waiter: waiter{
+				Pending:        []string{"PENDING"},
+				Target:         []string{"RUNNING"},
+				NotFoundChecks: 2,
+				Refresh:        testWaiterRefresh("", "", "RUNNING"),
+			},
+		},
+		{
+			name: "not found",
+			waiter: waiter{
+				Pending:        []string{"PENDING"},
+				Target:         []string{"RUNNING"},
+				NotFoundChecks: 1,
+				Refresh:        testWaiterRefresh("PENDING", ""),
+			},
+			wantErr: "instance ins-test not found while waiting for it to become [RUNNING]",
+		},
+		{
+			name: "deleted",
+			waiter: waiter{
+				Refresh: testWaiterRefresh("RUNNING", "TERMINATING", ""),
+			},
+		},
+		{
+			name: "any other state is pending",
+			waiter: waiter{
+				Target:  []string{"STOPPED"},
+				Refresh: testWaiterRefresh("RUNNING", "STOPPING", "STOPPED"),
+			},
+		},
+		{
+			name: "failed",
+			waiter: waiter{
+				Pending: []string{"PENDING"},
+				Target:  []string{"RUNNING"},
+				Failed:  []string{"LAUNCH_FAILED"},
+				Refresh: testWaiterRefresh("PENDING", "LAUNCH_FAILED"),
+			},
+			wantErr: "instance ins-test is in failed state LAUNCH_FAILED",
+		},
+		{
+			name: "unexpected",
+			waiter: waiter{
+				Pending: []string{"PENDING"},
+				Target:  []string{"RUNNING"},
+				Refresh: testWaiterRefresh("STOPPED"),
+			},
+			wantErr: "instance ins-test is in unexpected state STOPPED while waiting for it to become [RUNNING]",
+		},
+		{
+			name: "refresh error",
+			waiter: waiter{
+				Target: []string{"RUNNING"},
+				Refresh: func() (interface{}, string, error) {
+					return nil, "", errRefresh
+				},
+			},
+			wantErr: errRefresh.Error(),
+		},
+		{
+			name: "timeout",
+			waiter: waiter{
+				Pending: []string{"PENDING"},
+				Target:  []string{"RUNNING"},
+				Timeout: 100 * time.Millisecond,
+				Refresh: testWaiterRefresh("PENDING"),
+			},
+			wantErr: "timeout after 100ms waiting for instance ins-test to become [RUNNING], last state: PENDING",
+		},
+	}
+
+	for _, c := range cases {
+		c.waiter.Name = "instance ins-test"
+		c.waiter.PollInterval = time.Millisecond
+		if c.waiter.Timeout == 0 {
+			c.waiter.Timeout = 10 * time.Second
+		}
+		_, err := c.waiter.Wait()
+		if c.wantErr == "" && err != nil {
+			t.Errorf("%v: expect no error, got %v", c.name, err)
+		}
+		if c.wantErr != "" && (err == nil || !strings.Contains(err.Error(), c.wantErr)) {
+			t.Errorf("%v: expect error %q, got %v", c.name, c.wantErr, err)
+		}
+	}
+}